@@ -27,6 +27,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	toml "github.com/pelletier/go-toml"
 	logger "github.com/sirupsen/logrus"
@@ -37,9 +38,11 @@ import (
 var (
 	onceConfigRead      sync.Once
 	onceGetDefaultVhost sync.Once
-	adapterConfig       *Config
-	defaultVhost        map[string]string
-	e                   error
+	// adapterConfig is replaced rather than modified when the configuration file is reloaded so that the
+	// configurations read by the running components do not change underneath them
+	adapterConfig atomic.Pointer[Config]
+	defaultVhost  map[string]string
+	e             error
 )
 
 // DefaultGatewayName represents the name of the default gateway
@@ -73,8 +76,8 @@ const (
 // configuration object is achieved through the configuration file.
 func ReadConfigs() (*Config, error) {
 	onceConfigRead.Do(func() {
-		adapterConfig = defaultConfig
-		_, err := os.Stat(pkgconf.GetApkHome() + relativeConfigPath)
+		adapterConfig.Store(defaultConfig)
+		_, err := os.Stat(GetConfigPath())
 		if err != nil {
 			loggerConfig.ErrorC(logging.ErrorDetails{
 				Message:   fmt.Sprintf("Configuration file not found : %s", err.Error()),
@@ -82,7 +85,7 @@ func ReadConfigs() (*Config, error) {
 				ErrorCode: 1000,
 			})
 		}
-		content, readErr := ioutil.ReadFile(pkgconf.GetApkHome() + relativeConfigPath)
+		content, readErr := ioutil.ReadFile(GetConfigPath())
 		if readErr != nil {
 			loggerConfig.ErrorC(logging.ErrorDetails{
 				Message:   fmt.Sprintf("Error reading configurations : %s", readErr.Error()),
@@ -91,7 +94,7 @@ func ReadConfigs() (*Config, error) {
 			})
 			return
 		}
-		parseErr := toml.Unmarshal(content, adapterConfig)
+		parseErr := parseConfigs(content, defaultConfig)
 		if parseErr != nil {
 			loggerConfig.ErrorC(logging.ErrorDetails{
 				Message:   fmt.Sprintf("Error parsing the configurations : %s", parseErr.Error()),
@@ -100,22 +103,39 @@ func ReadConfigs() (*Config, error) {
 			})
 			return
 		}
+	})
+	return adapterConfig.Load(), e
+}
 
-		adapterConfig.resolveDeprecatedProperties()
+// GetConfigPath returns the file location of the configuration file
+func GetConfigPath() string {
+	return pkgconf.GetApkHome() + relativeConfigPath
+}
 
-		pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(adapterConfig.ControlPlane)).Elem(), "ControlPlane", true)
-	})
-	return adapterConfig, e
+// parseConfigs populates the given configuration object from the content of the configuration file
+func parseConfigs(content []byte, conf *Config) error {
+	if err := toml.Unmarshal(content, conf); err != nil {
+		return err
+	}
+	conf.resolveDeprecatedProperties()
+	// The secret references are resolved before the environment variables as the latter resolves everything between
+	// the first and the last curly brackets of a value
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(conf.Agent.Secrets)).Elem(), "Agent_Secrets", true)
+	if err := resolveSecretReferences(conf); err != nil {
+		return err
+	}
+	pkgconf.ResolveConfigEnvValues(reflect.ValueOf(&(conf.ControlPlane)).Elem(), "ControlPlane", true)
+	return nil
 }
 
 // SetConfig sets the given configuration to the adapter configuration
 func SetConfig(conf *Config) {
-	adapterConfig = conf
+	adapterConfig.Store(conf)
 }
 
 // SetDefaultConfig sets the default configuration to the adapter configuration
 func SetDefaultConfig() {
-	adapterConfig = defaultConfig
+	adapterConfig.Store(defaultConfig)
 }
 
 // ReadLogConfigs implements adapter/proxy log-configuration read operation.The read operation will happen only once, hence
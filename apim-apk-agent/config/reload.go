@@ -0,0 +1,168 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Settings of the configuration file by how their changes are applied to the running agent. A setting matches the
+// changes of its nested settings as well. The changes of the other settings are applied after a restart.
+var (
+	// liveSettings are read whenever they are used or are applied to the components which cache them
+	liveSettings = []string{
+		"ControlPlane.ServiceURL",
+		"ControlPlane.ServiceURLDeprecated",
+		"ControlPlane.Username",
+		"ControlPlane.Password",
+		"ControlPlane.ClientID",
+		"ControlPlane.ClientSecret",
+		"ControlPlane.Provider",
+		"ControlPlane.InternalKeyIssuer",
+		"ControlPlane.SendRevisionUpdate",
+		"ControlPlane.SkipSSLVerification",
+		"ControlPlane.RetryInterval",
+		"ControlPlane.Retry",
+		"ControlPlane.CircuitBreaker",
+		"ControlPlane.Authentication",
+		"ControlPlane.HTTPClient",
+		"ControlPlane.RequestWorkerPool.PoolSize",
+		"ControlPlane.RequestWorkerPool.PauseTimeAfterFailure",
+		"Agent.Secrets.Vault",
+	}
+	// resyncSettings change the APIs served by the agent and are applied with a resync
+	resyncSettings = []string{
+		"ControlPlane.EnvironmentLabels",
+		"Tenants",
+	}
+	// reconnectSettings are applied by reconnecting to the event hub
+	reconnectSettings = []string{
+		"ControlPlane.BrokerConnectionParameters",
+	}
+)
+
+// ConfigChanges holds the settings changed in the configuration file by how they are applied. The settings are
+// given by their paths in the Config struct, such as ControlPlane.Retry.MaxInterval.
+type ConfigChanges struct {
+	// Live are the changed settings applied to the running agent
+	Live []string
+	// Resync are the changed settings applied with a resync of the APIs and the subscriptions
+	Resync []string
+	// Reconnect are the changed settings applied by reconnecting to the event hub
+	Reconnect []string
+	// RestartRequired are the changed settings which keep their previous values until the agent restarts
+	RestartRequired []string
+}
+
+// HasAppliedChanges returns whether any of the changed settings were applied to the configurations
+func (changes *ConfigChanges) HasAppliedChanges() bool {
+	return len(changes.Live) > 0 || len(changes.Resync) > 0 || len(changes.Reconnect) > 0
+}
+
+// ReloadConfigs reads the configuration file again and replaces the configuration object returned by ReadConfigs
+// with a copy which has the settings that can be changed while the agent runs applied. The other settings keep their
+// previous values. The configurations are not changed when the file cannot be read or parsed.
+//
+// The configuration objects returned by ReadConfigs earlier are not modified. The components which cache a setting
+// or a configuration object should be updated by the caller based on the returned changes.
+func ReloadConfigs() (*ConfigChanges, error) {
+	content, err := os.ReadFile(GetConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("error reading configurations: %w", err)
+	}
+	return reloadConfigs(content)
+}
+
+// reloadConfigs replaces the configuration object with the changes of the given content of the configuration file
+func reloadConfigs(content []byte) (*ConfigChanges, error) {
+	current, _ := ReadConfigs()
+	reloaded := getDefaultConfig()
+	if err := parseConfigs(content, reloaded); err != nil {
+		return nil, fmt.Errorf("error parsing the configurations: %w", err)
+	}
+	// The changed settings are replaced as a whole in the copy, hence the values shared with the current
+	// configurations are not modified
+	updated := *current
+	changes := applyConfigs(&updated, reloaded)
+	if changes.HasAppliedChanges() {
+		adapterConfig.Store(&updated)
+	}
+	return changes, nil
+}
+
+// applyConfigs applies the settings of the reloaded configurations which can be changed while the agent runs to the
+// given configurations and returns the changed settings
+func applyConfigs(current, reloaded *Config) *ConfigChanges {
+	changes := &ConfigChanges{}
+	currentValue := reflect.ValueOf(current).Elem()
+	reloadedValue := reflect.ValueOf(reloaded).Elem()
+	for _, setting := range changedSettings("", currentValue, reloadedValue) {
+		switch {
+		case matchesSetting(setting, liveSettings):
+			changes.Live = append(changes.Live, setting)
+		case matchesSetting(setting, resyncSettings):
+			changes.Resync = append(changes.Resync, setting)
+		case matchesSetting(setting, reconnectSettings):
+			changes.Reconnect = append(changes.Reconnect, setting)
+		default:
+			changes.RestartRequired = append(changes.RestartRequired, setting)
+			continue
+		}
+		settingValue(currentValue, setting).Set(settingValue(reloadedValue, setting))
+	}
+	return changes
+}
+
+// changedSettings returns the paths of the settings which differ between the given values. The fields of the
+// structs are compared separately while the other values, including slices, are compared as a whole.
+func changedSettings(path string, current, reloaded reflect.Value) []string {
+	if current.Kind() != reflect.Struct {
+		if reflect.DeepEqual(current.Interface(), reloaded.Interface()) {
+			return nil
+		}
+		return []string{path}
+	}
+	var changed []string
+	for i := 0; i < current.NumField(); i++ {
+		fieldPath := current.Type().Field(i).Name
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		changed = append(changed, changedSettings(fieldPath, current.Field(i), reloaded.Field(i))...)
+	}
+	return changed
+}
+
+func settingValue(value reflect.Value, setting string) reflect.Value {
+	for _, field := range strings.Split(setting, ".") {
+		value = value.FieldByName(field)
+	}
+	return value
+}
+
+func matchesSetting(setting string, settings []string) bool {
+	for _, candidate := range settings {
+		if setting == candidate || strings.HasPrefix(setting, candidate+".") {
+			return true
+		}
+	}
+	return false
+}
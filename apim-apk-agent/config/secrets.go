@@ -0,0 +1,255 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Types of the secret references
+const (
+	fileSecretType  = "file"
+	vaultSecretType = "vault"
+	// vaultKeySeparator separates the path of a Vault secret from the key of the value in a vault: reference
+	vaultKeySeparator = "#"
+)
+
+// secretReferencePattern matches the secret references embedded in a value, such as $file{/path/to/secret}
+var secretReferencePattern = regexp.MustCompile(`\$(file|vault)\{([^}]+)\}`)
+
+var (
+	secretReferencesLock sync.RWMutex
+	// secretFiles are the files referenced by the configurations read last
+	secretFiles []string
+	// vaultReferenced is whether the configurations read last reference secrets in Vault
+	vaultReferenced bool
+)
+
+// GetSecretFiles returns the files referenced by the file: references of the configurations read last
+func GetSecretFiles() []string {
+	secretReferencesLock.RLock()
+	defer secretReferencesLock.RUnlock()
+	return secretFiles
+}
+
+// HasVaultReferences returns whether the configurations read last reference secrets in Vault
+func HasVaultReferences() bool {
+	secretReferencesLock.RLock()
+	defer secretReferencesLock.RUnlock()
+	return vaultReferenced
+}
+
+// secretResolver reads the secrets referenced by the configurations. The secrets in Vault are read once per
+// resolution for each path.
+type secretResolver struct {
+	vault        vault
+	httpClient   *http.Client
+	vaultSecrets map[string]map[string]string
+	files        []string
+	usesVault    bool
+}
+
+// resolveSecretReferences replaces the secret references in the control plane configurations with the secrets
+func resolveSecretReferences(conf *Config) error {
+	resolver := &secretResolver{
+		vault:        conf.Agent.Secrets.Vault,
+		vaultSecrets: make(map[string]map[string]string),
+	}
+	if err := resolver.resolveValues("ControlPlane", reflect.ValueOf(&conf.ControlPlane).Elem()); err != nil {
+		return err
+	}
+	secretReferencesLock.Lock()
+	defer secretReferencesLock.Unlock()
+	secretFiles = resolver.files
+	vaultReferenced = resolver.usesVault
+	return nil
+}
+
+// resolveValues resolves the secret references in the strings of the given value and of its fields and elements
+func (r *secretResolver) resolveValues(path string, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.String:
+		resolved, err := r.resolve(value.String())
+		if err != nil {
+			return fmt.Errorf("error resolving the secret of %s: %w", path, err)
+		}
+		value.SetString(resolved)
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if err := r.resolveValues(fmt.Sprintf("%s[%d]", path, i), value.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if err := r.resolveValues(path+"."+value.Type().Field(i).Name, value.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the secret when the value is a secret reference and replaces the secret references embedded in the
+// value otherwise
+func (r *secretResolver) resolve(value string) (string, error) {
+	if secretType, reference, found := strings.Cut(value, ":"); found &&
+		(secretType == fileSecretType || secretType == vaultSecretType) {
+		return r.readSecret(secretType, reference)
+	}
+	var resolveErr error
+	resolved := secretReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		groups := secretReferencePattern.FindStringSubmatch(match)
+		secret, err := r.readSecret(groups[1], groups[2])
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return secret
+	})
+	return resolved, resolveErr
+}
+
+func (r *secretResolver) readSecret(secretType, reference string) (string, error) {
+	if secretType == fileSecretType {
+		return r.readFile(reference)
+	}
+	return r.readVaultSecret(reference)
+}
+
+// readFile reads a secret from a file, such as a mounted Kubernetes Secret. The trailing line breaks are removed.
+func (r *secretResolver) readFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	r.files = append(r.files, filepath.Clean(path))
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// readVaultSecret reads the value of a key of a secret in Vault referenced as path#key
+func (r *secretResolver) readVaultSecret(reference string) (string, error) {
+	path, key, found := strings.Cut(reference, vaultKeySeparator)
+	if !found || path == "" || key == "" {
+		return "", fmt.Errorf("the Vault secret reference %q is not in the form path#key", reference)
+	}
+	r.usesVault = true
+	secret, found := r.vaultSecrets[path]
+	if !found {
+		var err error
+		if secret, err = r.fetchVaultSecret(path); err != nil {
+			return "", err
+		}
+		r.vaultSecrets[path] = secret
+	}
+	value, found := secret[key]
+	if !found {
+		return "", fmt.Errorf("key %q not found in the Vault secret %s", key, path)
+	}
+	return value, nil
+}
+
+// fetchVaultSecret reads a secret from the KV secrets engine of Vault. The path includes the mount of the engine,
+// and the data segment for the version 2 of the engine, such as secret/data/apk-agent.
+func (r *secretResolver) fetchVaultSecret(path string) (map[string]string, error) {
+	if r.vault.Address == "" {
+		return nil, fmt.Errorf("the Vault address is not configured to read the secret %s", path)
+	}
+	token := r.vault.Token
+	if token == "" && r.vault.TokenPath != "" {
+		content, err := os.ReadFile(r.vault.TokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the Vault token: %w", err)
+		}
+		token = strings.TrimSpace(string(content))
+	}
+	req, err := http.NewRequest(http.MethodGet,
+		strings.TrimSuffix(r.vault.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if r.vault.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.vault.Namespace)
+	}
+	httpClient, err := r.getHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the Vault secret %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s from Vault for the secret %s", resp.Status, path)
+	}
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing the Vault secret %s: %w", path, err)
+	}
+	data := response.Data
+	// The version 2 of the KV secrets engine nests the data of the secret along with its metadata
+	if nested, isMap := data["data"].(map[string]interface{}); isMap && data["metadata"] != nil {
+		data = nested
+	}
+	secret := make(map[string]string, len(data))
+	for key, value := range data {
+		if stringValue, isString := value.(string); isString {
+			secret[key] = stringValue
+		} else {
+			secret[key] = fmt.Sprint(value)
+		}
+	}
+	return secret, nil
+}
+
+func (r *secretResolver) getHTTPClient() (*http.Client, error) {
+	if r.httpClient != nil {
+		return r.httpClient, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if r.vault.CACertPath != "" {
+		caCerts, err := os.ReadFile(r.vault.CACertPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the CA certificates of Vault: %w", err)
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no CA certificates found in %s", r.vault.CACertPath)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: certPool}
+	}
+	r.httpClient = &http.Client{Transport: transport, Timeout: r.vault.RequestTimeout * time.Second}
+	return r.httpClient, nil
+}
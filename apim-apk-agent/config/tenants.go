@@ -0,0 +1,91 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import "strings"
+
+// IsTenantServed returns whether the events and the APIs of the given tenant domain are served by the agent
+func (config *Config) IsTenantServed(tenantDomain string) bool {
+	if len(config.Tenants) == 0 {
+		return true
+	}
+	_, found := config.getTenant(tenantDomain)
+	return found
+}
+
+// GetTenantNamespace returns the data plane namespace the CRs of the given tenant domain are deployed in
+func (config *Config) GetTenantNamespace(tenantDomain string) string {
+	if configuredTenant, found := config.getTenant(tenantDomain); found && configuredTenant.Namespace != "" {
+		return configuredTenant.Namespace
+	}
+	return config.DataPlane.Namespace
+}
+
+// GetTenantEnvironmentLabels returns the gateway environments served for the given tenant domain
+func (config *Config) GetTenantEnvironmentLabels(tenantDomain string) []string {
+	if configuredTenant, found := config.getTenant(tenantDomain); found && len(configuredTenant.EnvironmentLabels) > 0 {
+		return configuredTenant.EnvironmentLabels
+	}
+	return config.ControlPlane.EnvironmentLabels
+}
+
+// GetEnvironmentLabels returns the gateway environments served for any of the tenants
+func (config *Config) GetEnvironmentLabels() []string {
+	labels := make([]string, 0, len(config.ControlPlane.EnvironmentLabels))
+	labels = appendMissing(labels, config.ControlPlane.EnvironmentLabels...)
+	for _, configuredTenant := range config.Tenants {
+		labels = appendMissing(labels, configuredTenant.EnvironmentLabels...)
+	}
+	return labels
+}
+
+// GetDataPlaneNamespaces returns the data plane namespaces of all the tenants
+func (config *Config) GetDataPlaneNamespaces() []string {
+	namespaces := []string{config.DataPlane.Namespace}
+	for _, configuredTenant := range config.Tenants {
+		if configuredTenant.Namespace != "" {
+			namespaces = appendMissing(namespaces, configuredTenant.Namespace)
+		}
+	}
+	return namespaces
+}
+
+func (config *Config) getTenant(tenantDomain string) (tenant, bool) {
+	for _, configuredTenant := range config.Tenants {
+		if strings.EqualFold(configuredTenant.Domain, tenantDomain) {
+			return configuredTenant, true
+		}
+	}
+	return tenant{}, false
+}
+
+func appendMissing(values []string, newValues ...string) []string {
+	for _, newValue := range newValues {
+		found := false
+		for _, value := range values {
+			if value == newValue {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, newValue)
+		}
+	}
+	return values
+}
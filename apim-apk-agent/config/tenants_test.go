@@ -0,0 +1,64 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTenantConfig() *Config {
+	return &Config{
+		ControlPlane: controlPlane{EnvironmentLabels: []string{"Default"}},
+		DataPlane:    dataPlane{Namespace: "apk"},
+		Tenants: []tenant{
+			{Domain: "carbon.super"},
+			{Domain: "wso2.com", Namespace: "apk-wso2", EnvironmentLabels: []string{"Default", "Internal"}},
+		},
+	}
+}
+
+func TestTenantsNotConfigured(t *testing.T) {
+	conf := &Config{
+		ControlPlane: controlPlane{EnvironmentLabels: []string{"Default"}},
+		DataPlane:    dataPlane{Namespace: "apk"},
+	}
+	assert.True(t, conf.IsTenantServed("wso2.com"))
+	assert.Equal(t, "apk", conf.GetTenantNamespace("wso2.com"))
+	assert.Equal(t, []string{"Default"}, conf.GetTenantEnvironmentLabels("wso2.com"))
+	assert.Equal(t, []string{"Default"}, conf.GetEnvironmentLabels())
+	assert.Equal(t, []string{"apk"}, conf.GetDataPlaneNamespaces())
+}
+
+func TestIsTenantServed(t *testing.T) {
+	conf := newTenantConfig()
+	assert.True(t, conf.IsTenantServed("carbon.super"))
+	assert.True(t, conf.IsTenantServed("WSO2.com"))
+	assert.False(t, conf.IsTenantServed("abc.com"))
+}
+
+func TestGetTenantNamespaceAndEnvironmentLabels(t *testing.T) {
+	conf := newTenantConfig()
+	assert.Equal(t, "apk", conf.GetTenantNamespace("carbon.super"))
+	assert.Equal(t, "apk-wso2", conf.GetTenantNamespace("wso2.com"))
+	assert.Equal(t, []string{"Default"}, conf.GetTenantEnvironmentLabels("carbon.super"))
+	assert.Equal(t, []string{"Default", "Internal"}, conf.GetTenantEnvironmentLabels("wso2.com"))
+	assert.Equal(t, []string{"Default", "Internal"}, conf.GetEnvironmentLabels())
+	assert.Equal(t, []string{"apk", "apk-wso2"}, conf.GetDataPlaneNamespaces())
+}
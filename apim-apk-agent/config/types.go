@@ -78,12 +78,133 @@ type Config struct {
 	Agent        agent        `toml:"agent"`
 	// Metric represents configurations to expose/export go metrics
 	Metrics metrics `toml:"metrics"`
+	// Tenants are the control plane tenants served by the agent. All the tenants are served with the data plane
+	// namespace and the environment labels of the control plane when it is empty.
+	Tenants []tenant `toml:"tenants"`
+}
+
+// tenant maps a control plane tenant (organization) to the data plane namespace its APIs are deployed in
+type tenant struct {
+	// Domain is the tenant domain or the organization ID of the tenant in the control plane
+	Domain string
+	// Namespace is the data plane namespace of the tenant. DataPlane.Namespace is used when it is empty.
+	Namespace string
+	// EnvironmentLabels are the gateway environments of the tenant. ControlPlane.EnvironmentLabels are used when
+	// it is empty.
+	EnvironmentLabels []string
 }
 type agent struct {
 	Enabled    bool
 	Keystore   keystore
 	TrustStore truststore
 	Mode       string
+	// RenderDirectory is the directory the CRs are written to when the agent runs in the Render mode
+	RenderDirectory string
+	LeaderElection  leaderElection
+	// Snapshot persists the applications and the subscriptions so that they are served after a restart while the
+	// control plane is unreachable
+	Snapshot snapshot
+	// EventBufferSize is the number of the recent events kept to be replayed to the reconnecting common controllers
+	EventBufferSize int
+	// ManagementAPI exposes the endpoints to inspect and control the running agent on the internal REST server
+	ManagementAPI managementAPI
+	// RestServer holds the authentication of the internal REST server
+	RestServer restServer
+	// ConfigReload applies the changes of the configuration file without restarting the agent
+	ConfigReload configReload
+	// Secrets configures the resolution of the file: and vault: references in the control plane configurations
+	Secrets secrets
+}
+
+// configReload holds the configurations of watching the configuration file for changes
+type configReload struct {
+	Enabled bool
+}
+
+// secrets holds the configurations of resolving the secret references. A control plane setting is read from a file
+// with file:/path/to/secret and from HashiCorp Vault with vault:secret/data/path#key. The references are embedded in
+// the other values, such as the broker URLs, as $file{/path/to/secret} and $vault{secret/data/path#key}.
+type secrets struct {
+	// RefreshInterval is the interval in seconds the configurations are reloaded to pick up the rotated secrets in
+	// Vault. The secrets are not refreshed when it is 0. The rotated secret files are picked up when they change.
+	RefreshInterval time.Duration
+	Vault           vault
+}
+
+// vault holds the connection to the HashiCorp Vault server the vault: references are read from. The KV secrets
+// engines of both versions are supported.
+type vault struct {
+	// Address is the URL of the Vault server
+	Address string
+	// Token authenticates the requests to Vault. TokenPath is used when it is empty.
+	Token string
+	// TokenPath is the file the token is read from for each resolution, such as the token sink of a Vault agent
+	TokenPath string
+	// Namespace is the Vault Enterprise namespace of the secrets
+	Namespace string
+	// CACertPath is the file of the CA certificates trusted for the Vault server in addition to the system ones
+	CACertPath string
+	// RequestTimeout is the timeout in seconds of the requests to Vault
+	RequestTimeout time.Duration
+}
+
+// restServer holds the authentication rules of the internal REST server. The read rules apply to the GET, HEAD and
+// OPTIONS requests while the write rules apply to the rest. The write requests require MTLS by default.
+type restServer struct {
+	ReadAuthentication  restAuthentication
+	WriteAuthentication restAuthentication
+	// JWT holds the configurations to validate the bearer tokens of the JWT authentication method
+	JWT jwtValidation
+}
+
+// restAuthentication holds the authentication rules of a set of endpoints
+type restAuthentication struct {
+	// Methods are the accepted authentication methods, MTLS and/or JWT. A request is authenticated when it passes
+	// any of them. The requests are not authenticated when it is empty.
+	Methods []string
+	// Scopes are required in the bearer tokens authenticated with the JWT method
+	Scopes []string
+}
+
+// jwtValidation holds the configurations to validate the bearer tokens sent to the internal REST server
+type jwtValidation struct {
+	// Issuer is the expected iss claim of the tokens
+	Issuer string
+	// Audience is the expected aud claim of the tokens. The audience is not validated when it is empty.
+	Audience string
+	// CertificatePath is the PEM encoded certificate or public key the signatures of the tokens are validated with
+	CertificatePath string
+}
+
+// managementAPI holds the configurations of the endpoints to inspect and control the running agent
+type managementAPI struct {
+	Enabled bool
+	// Username and Password are the basic authentication credentials of the management endpoints
+	Username string
+	Password string
+}
+
+// snapshot holds the configurations of the store the snapshots of the agent state are saved to
+type snapshot struct {
+	Enabled bool
+	// Type of the store. One of File, ConfigMap or Secret.
+	Type string
+	// Path is the file the snapshots are saved to when the type is File
+	Path string
+	// Name is the name of the ConfigMap or the Secret the snapshots are saved to
+	Name string
+	// Namespace of the ConfigMap or the Secret. DataPlane.Namespace is used when it is empty.
+	Namespace string
+}
+
+// leaderElection holds the configurations to run multiple replicas of the agent where only the elected
+// leader applies the CRs and sends the revision acknowledgements
+type leaderElection struct {
+	Enabled bool
+	// LeaseName is the name of the Lease used to elect the leader
+	LeaseName string
+	// LeaseNamespace is the namespace of the Lease. The namespace of the agent is used when it is not set.
+	LeaseNamespace string
 }
 type keystore struct {
 	KeyPath  string
@@ -114,6 +235,59 @@ type controlPlane struct {
 	ClientID                   string
 	ClientSecret               string
 	Provider                   string
+	// ReconcileInterval is the interval in seconds to reconcile the CRs in the data plane with the control plane.
+	// Reconciliation is disabled when the interval is 0.
+	ReconcileInterval time.Duration
+	// Retry configures the backoff between the retries of the control plane requests. RetryInterval is the interval
+	// before the first retry.
+	Retry          controlPlaneRetry
+	CircuitBreaker circuitBreaker
+	// Authentication configures how the requests to the control plane are authenticated
+	Authentication controlPlaneAuthentication
+	// SendRevisionFailure reports the failed revision deployments to the internal/data/v1/apis/failed-revisions
+	// endpoint of the control plane along with the revision updates. The endpoint needs a control plane extension as
+	// API Manager 4.4.0 does not expose it, hence it must only be enabled with a control plane which does. Otherwise
+	// the failed revisions are only logged, and they are not acknowledged as deployed to the control plane.
+	SendRevisionFailure bool
+}
+
+type controlPlaneAuthentication struct {
+	// Type is ClientCredentials, JWTBearer or Basic. The client credentials grant is used when it is empty and the
+	// client ID and the client secret are set. Otherwise the basic authentication is used when BasicAuthFallback is
+	// enabled, or the control plane requests fail.
+	Type string
+	// TokenEndpoint is the OAuth2 token endpoint. The oauth2/token endpoint of the service URL is used when it is
+	// empty.
+	TokenEndpoint string
+	// Scopes are requested for the access tokens
+	Scopes []string
+	// AssertionPath is the file of the JWT exchanged for the access tokens with the JWT bearer grant
+	AssertionPath string
+	// RefreshBeforeExpiry is the time in seconds before the expiry of an access token it is refreshed
+	RefreshBeforeExpiry time.Duration
+	// BasicAuthFallback sends the username and the password when an access token cannot be obtained
+	BasicAuthFallback bool
+}
+
+type controlPlaneRetry struct {
+	// MaxInterval is the maximum interval in seconds between the retries. The interval doubles after each failure.
+	MaxInterval time.Duration
+	// Jitter is the fraction of the interval randomized so that the replicas do not retry at the same time
+	Jitter float64
+	// BudgetPerMinute is the maximum number of retries of the requests to a control plane endpoint within a minute
+	BudgetPerMinute int
+	// RevisionAckMaxAttempts is the maximum number of attempts to deliver a revision acknowledgement before it is
+	// given up and logged as a dead letter. The attempts are not limited when it is 0.
+	RevisionAckMaxAttempts int
+}
+
+type circuitBreaker struct {
+	// FailureThreshold is the number of consecutive failed control plane requests which opens the circuit. The
+	// circuit breaker is disabled when it is 0.
+	FailureThreshold int
+	// OpenDuration is the time in seconds the control plane requests are failed without being sent after the
+	// circuit opens. A single trial request is sent afterwards and the circuit closes if it succeeds.
+	OpenDuration time.Duration
 }
 
 // Dataplane struct contains the configurations related to the APK
@@ -121,6 +295,9 @@ type dataPlane struct {
 	Enabled            bool
 	K8ResourceEndpoint string
 	Namespace          string
+	// LocalCRGeneration generates the CRs within the agent. K8ResourceEndpoint is used as a fallback
+	// for the APIs which can not be handled locally.
+	LocalCRGeneration bool
 }
 
 type requestWorkerPool struct {
@@ -134,6 +311,22 @@ type brokerConnectionParameters struct {
 	EventListeningEndpoints []string
 	ReconnectInterval       time.Duration
 	ReconnectRetryCount     int
+	// DurableQueue consumes the events through durable queues so that the events published while the agent is
+	// down are delivered once it is back.
+	DurableQueue durableQueue
+}
+
+type durableQueue struct {
+	Enabled bool
+	// AgentIdentity is the prefix of the queue names of the agent. It should not change across the restarts of the
+	// agent and should be unique to each agent consuming from the same broker, as the agents sharing an identity
+	// compete for the events. It is required when the durable queues are enabled.
+	AgentIdentity string
+	// DeadLetterExchange receives the events which cannot be processed by the agent
+	DeadLetterExchange string
+	// Expiry is the time in seconds the queues of the agent are kept by the broker after they are last used so that
+	// the abandoned queues are deleted. 0 keeps them until they are deleted manually.
+	Expiry time.Duration
 }
 
 type httpClient struct {
@@ -30,7 +30,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/fsnotify/fsnotify"
 	healthservice "github.com/wso2/apk/adapter/pkg/health/api/wso2/health/service"
 	cpv1alpha2 "github.com/wso2/apk/common-go-libs/apis/cp/v1alpha2"
 	dpv1alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha1"
@@ -39,14 +38,20 @@ import (
 	"github.com/wso2/apk/common-go-libs/loggers"
 	"github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/service/apkmgt"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/constants"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/eventhub"
+	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
 	logging "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/logging"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/messaging"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/notifier"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/reconciler"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/synchronizer"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/health"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
@@ -96,30 +101,24 @@ func Run(conf *config.Config) {
 
 	logger.LoggerAgent.Debugf("Run method started with context : %v", ctx)
 
-	// log config watcher
-	watcherLogConf, _ := fsnotify.NewWatcher()
-	logConfigPath, errC := config.GetLogConfigPath()
-	if errC == nil {
-		errC = watcherLogConf.Add(logConfigPath)
-	}
-
-	if errC != nil {
-		logger.LoggerAgent.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Error reading the log configs, error: %v", errC.Error()))
+	if conf.Agent.Mode == constants.RenderMode {
+		runRender(conf)
+		return
 	}
 
 	logger.LoggerAgent.Info("Starting apim-apk-agent ....")
 	eventHubEnabled := conf.ControlPlane.Enabled
+	if err := messaging.ValidateDurableQueue(conf); eventHubEnabled && err != nil {
+		logger.LoggerAgent.Fatalf("Invalid durable queue configuration: %v", err)
+	}
+	if eventHubEnabled && conf.ControlPlane.SendRevisionFailure {
+		logger.LoggerAgent.Warn("The failed revision deployments are reported to the control plane as " +
+			"sendRevisionFailure is enabled. The control plane must be extended to expose the " +
+			"internal/data/v1/apis/failed-revisions endpoint, which API Manager 4.4.0 does not.")
+	}
 
 	var probeAddr string
-	var scheme = runtime.NewScheme()
-	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
-	utilruntime.Must(gwapiv1.AddToScheme(scheme))
-	utilruntime.Must(dpv1alpha1.AddToScheme(scheme))
-	utilruntime.Must(dpv1alpha2.AddToScheme(scheme))
-	utilruntime.Must(dpv1alpha3.AddToScheme(scheme))
-	utilruntime.Must(cpv1alpha2.AddToScheme(scheme))
-	utilruntime.Must(cpv1alpha2.AddToScheme(scheme))
-	utilruntime.Must(dpv1alpha3.AddToScheme(scheme))
+	scheme := newScheme()
 
 	options := ctrl.Options{
 		Scheme:                 scheme,
@@ -135,6 +134,9 @@ func Run(conf *config.Config) {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
+		LeaderElection:          conf.Agent.LeaderElection.Enabled,
+		LeaderElectionID:        conf.Agent.LeaderElection.LeaseName,
+		LeaderElectionNamespace: conf.Agent.LeaderElection.LeaseNamespace,
 	}
 
 	if conf.Metrics.Enabled {
@@ -153,6 +155,15 @@ func Run(conf *config.Config) {
 	if err != nil {
 		logger.LoggerAgent.Error("unable to start kubernetes controller manager", err)
 	}
+	k8sclient.SetEventRecorder(mgr.GetEventRecorderFor(constants.EventRecorderName))
+	// Writes to the data plane fail with ErrNotLeader while this replica is not the leader
+	k8sClient := leaderelection.NewLeaderOnlyClient(k8sclient.NewMetricsClient(mgr.GetClient()))
+	if conf.Agent.LeaderElection.Enabled {
+		leaderelection.SetLeader(false)
+		go leaderelection.WatchLeadership(mgr.Elected(), func() {
+			onElectedAsLeader(k8sClient)
+		})
+	}
 
 	// Start the manager in a goroutine
 	var wg sync.WaitGroup
@@ -168,31 +179,76 @@ func Run(conf *config.Config) {
 	AgentMode := conf.Agent.Mode
 	logger.LoggerAgent.Infof("Agent Mode: %v", AgentMode)
 
+	utils.SetEventBufferSize(conf.Agent.EventBufferSize)
+	controller := newAgentController(k8sClient)
+	managementserver.SetAgentController(controller)
+	// The changes of the configuration files are applied without a restart
+	watcher, errC := newConfigWatcher(conf, controller)
+	if errC != nil {
+		logger.LoggerAgent.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Error watching the configuration files, error: %v", errC.Error()))
+	}
+	// The last known state is served from the snapshot until the control plane is reachable
+	snapshotRestored := restoreSnapshot(conf, mgr.GetAPIReader(), k8sClient)
+	// The revision acknowledgements which were not delivered before the restart are retried
+	notifier.RestoreRevisionAcks()
+	if snapshotRestored {
+		startManagementServers()
+	}
+
 	if AgentMode == "CPtoDP" {
 		// Load initial Policy data from control plane
-		synchronizer.FetchRateLimitPoliciesOnEvent("", "", mgr.GetClient())
+		synchronizer.FetchRateLimitPoliciesOnEvent("", "", k8sClient)
 	}
 	// Load initial Subscription Rate Limit data from control plane
-	synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent("", "", mgr.GetClient(), true)
+	synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent("", "", k8sClient, true)
 	// Load initial AI Provider data from control plane
-	synchronizer.FetchAIProvidersOnEvent("", "", "", mgr.GetClient(), true)
+	synchronizer.FetchAIProvidersOnEvent("", "", "", k8sClient, true)
+	// Load initial Blocking Condition data from control plane
+	synchronizer.FetchBlockingConditionsOnStartUp(k8sClient)
 
 	// Load initial data from control plane
-	eventhub.LoadInitialData(conf, mgr.GetClient())
+	eventhub.LoadInitialData(conf, k8sClient)
 	health.RestService.SetStatus(true)
 
 	if eventHubEnabled {
 		var connectionURLList = conf.ControlPlane.BrokerConnectionParameters.EventListeningEndpoints
 		if strings.Contains(connectionURLList[0], amqpProtocol) {
-			go messaging.ProcessEvents(conf, mgr.GetClient())
+			go startEventConsumers(conf, k8sClient, mgr.Elected())
 		}
 	}
 
 	// Load initial KM data from control plane
-	synchronizer.FetchKeyManagersOnStartUp(mgr.GetClient())
+	synchronizer.FetchKeyManagersOnStartUp(k8sClient)
+
+	if AgentMode == "CPtoDP" {
+		// Periodically repair the drift between the control plane and the data plane
+		go reconciler.Start(conf, k8sClient)
+	}
 
 	health.NotificationListenerService.SetStatus(true)
 
+	if !snapshotRestored {
+		startManagementServers()
+	}
+OUTER:
+	for {
+		select {
+		case event := <-watcher.events():
+			watcher.handle(event)
+		case s := <-sig:
+			switch s {
+			case os.Interrupt:
+				logger.LoggerAgent.Info("Shutting down...")
+				break OUTER
+			}
+		}
+	}
+	logger.LoggerAgent.Info("Bye!")
+}
+
+// startManagementServers starts the gRPC server the common controllers stream the events from and the internal
+// REST server they fetch the applications and the subscriptions from
+func startManagementServers() {
 	var grpcOptions []grpc.ServerOption
 	grpcOptions = append(grpcOptions, grpc.KeepaliveParams(
 		keepalive.ServerParameters{
@@ -246,23 +302,16 @@ func Run(conf *config.Config) {
 			logger.LoggerAgent.ErrorC(logging.PrintError(logging.Error1101, logging.BLOCKER, "Failed to start GRPC server, error: %v", err.Error()))
 		}
 	}()
-OUTER:
-	for {
-		select {
-		case l := <-watcherLogConf.Events:
-			switch l.Op.String() {
-			case "WRITE":
-				logger.LoggerAgent.Info("Loading updated log config file...")
-				config.ClearLogConfigInstance()
-				logger.UpdateLoggers()
-			}
-		case s := <-sig:
-			switch s {
-			case os.Interrupt:
-				logger.LoggerAgent.Info("Shutting down...")
-				break OUTER
-			}
-		}
-	}
-	logger.LoggerAgent.Info("Bye!")
+}
+
+// newScheme creates the runtime scheme with the resource types handled by the agent
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(gwapiv1.AddToScheme(scheme))
+	utilruntime.Must(dpv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(dpv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(dpv1alpha3.AddToScheme(scheme))
+	utilruntime.Must(cpv1alpha2.AddToScheme(scheme))
+	return scheme
 }
@@ -0,0 +1,211 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package agent
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/messaging"
+	internalutils "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/utils"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
+	pkgloggers "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
+)
+
+const (
+	// configMapDataDir is the symbolic link swapped by the kubelet when a mounted ConfigMap changes
+	configMapDataDir = "..data"
+	// configReloadDelay is the time waited for the consecutive changes of a file to settle before it is reloaded
+	configReloadDelay = 2 * time.Second
+)
+
+// configWatcher reloads the configuration files when they change. The directories of the files are watched as the
+// files mounted from a ConfigMap are replaced through a symbolic link rather than written.
+type configWatcher struct {
+	watcher *fsnotify.Watcher
+	lock    sync.Mutex
+	// reloads holds the reload functions of the watched files by their paths
+	reloads map[string]func()
+	// pending holds the reloads waiting for the changes of the files to settle
+	pending map[string]*time.Timer
+}
+
+// newConfigWatcher watches the log configuration file and, unless the hot reload is disabled, the configuration file
+// and the secret files it references
+func newConfigWatcher(conf *config.Config, controller *agentController) (*configWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &configWatcher{
+		watcher: watcher,
+		reloads: make(map[string]func()),
+		pending: make(map[string]*time.Timer),
+	}
+	logConfigPath, err := config.GetLogConfigPath()
+	if err == nil {
+		err = w.add(logConfigPath, reloadLogConfigs)
+	}
+	if err != nil {
+		return w, err
+	}
+	if conf.Agent.ConfigReload.Enabled {
+		var reloadLock sync.Mutex
+		var reload func()
+		reload = func() {
+			reloadLock.Lock()
+			defer reloadLock.Unlock()
+			reloadConfigs(controller)
+			if err := w.addSecretFiles(reload); err != nil {
+				logger.LoggerAgent.Errorf("Error watching the secret files: %v", err)
+			}
+		}
+		err = w.add(config.GetConfigPath(), reload)
+		if err == nil {
+			err = w.addSecretFiles(reload)
+		}
+		if conf.Agent.Secrets.RefreshInterval > 0 {
+			go w.refreshSecrets(conf.Agent.Secrets.RefreshInterval*time.Second, reload)
+		}
+	}
+	return w, err
+}
+
+func (w *configWatcher) add(path string, reload func()) error {
+	path = filepath.Clean(path)
+	if err := w.watcher.Add(filepath.Dir(path)); err != nil {
+		return err
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.reloads[path] = reload
+	return nil
+}
+
+// addSecretFiles watches the files referenced by the configurations so that the rotated secrets are applied. The
+// configuration file is reloaded when a secret file changes.
+func (w *configWatcher) addSecretFiles(reload func()) error {
+	for _, path := range config.GetSecretFiles() {
+		if err := w.add(path, reload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshSecrets reloads the configurations periodically while they reference secrets in Vault, which does not
+// notify the changes of the secrets
+func (w *configWatcher) refreshSecrets(interval time.Duration, reload func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if config.HasVaultReferences() {
+			logger.LoggerAgent.Debug("Reloading the configuration file to refresh the secrets in Vault")
+			reload()
+		}
+	}
+}
+
+// events returns the events of the watched directories. The returned channel is nil when the watcher could not be
+// created.
+func (w *configWatcher) events() <-chan fsnotify.Event {
+	if w == nil {
+		return nil
+	}
+	return w.watcher.Events
+}
+
+// handle schedules the reloads of the files affected by the event
+func (w *configWatcher) handle(event fsnotify.Event) {
+	if event.Op == fsnotify.Chmod {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	changed := filepath.Clean(event.Name)
+	for path, reload := range w.reloads {
+		if changed != path && !(filepath.Base(changed) == configMapDataDir && filepath.Dir(changed) == filepath.Dir(path)) {
+			continue
+		}
+		if timer, found := w.pending[path]; found {
+			timer.Reset(configReloadDelay)
+			continue
+		}
+		path, reload := path, reload
+		w.pending[path] = time.AfterFunc(configReloadDelay, func() {
+			w.lock.Lock()
+			delete(w.pending, path)
+			w.lock.Unlock()
+			logger.LoggerAgent.Infof("Reloading %s as it has changed", path)
+			reload()
+		})
+	}
+}
+
+// reloadLogConfigs applies the changed log configurations to the loggers
+func reloadLogConfigs() {
+	config.ClearLogConfigInstance()
+	logger.UpdateLoggers()
+	pkgloggers.UpdateLoggers()
+}
+
+// reloadConfigs applies the changes of the configuration file to the running agent. The settings cached by the
+// components are updated, the event consumers reconnect when the broker connection parameters change and the APIs
+// are resynced when the served environments or tenants change.
+func reloadConfigs(controller *agentController) {
+	changes, err := config.ReloadConfigs()
+	if err != nil {
+		logger.LoggerAgent.Errorf("Error while reloading the configuration file. Hence the previous configurations "+
+			"are kept: %v", err)
+		return
+	}
+	if len(changes.RestartRequired) > 0 {
+		logger.LoggerAgent.Warnf("Changes of %s cannot be applied while the agent runs and are applied after a "+
+			"restart", strings.Join(changes.RestartRequired, ", "))
+	}
+	if !changes.HasAppliedChanges() {
+		logger.LoggerAgent.Debug("No changes to apply in the configuration file")
+		return
+	}
+	conf, _ := config.ReadConfigs()
+	controlplane.UpdateClient(conf)
+	internalutils.UpdateWorkerPool(conf)
+	utils.UpdateControlPlaneParameters(conf)
+	if len(changes.Live) > 0 {
+		logger.LoggerAgent.Infof("Applied the changes of %s", strings.Join(changes.Live, ", "))
+	}
+	if len(changes.Reconnect) > 0 {
+		logger.LoggerAgent.Infof("Reconnecting to the event hub to apply the changes of %s",
+			strings.Join(changes.Reconnect, ", "))
+		if conf.ControlPlane.Enabled {
+			messaging.ReconnectEvents(conf)
+		}
+	}
+	if len(changes.Resync) > 0 {
+		logger.LoggerAgent.Infof("Resyncing to apply the changes of %s", strings.Join(changes.Resync, ", "))
+		if err := controller.Resync(); err != nil {
+			logger.LoggerAgent.Warnf("Unable to resync as %v. The changes are applied by the next reconciliation", err)
+		}
+	}
+}
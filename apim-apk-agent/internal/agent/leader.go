@@ -0,0 +1,59 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package agent
+
+import (
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/eventhub"
+	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/messaging"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/synchronizer"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// onElectedAsLeader re-applies the state of the control plane to the cluster once this replica becomes the leader
+// as the writes were dropped while it was a follower.
+func onElectedAsLeader(k8sClient client.Client) {
+	conf, _ := config.ReadConfigs()
+	logger.LoggerLeader.Info("Applying the control plane state to the cluster as the new leader")
+	if conf.Agent.Mode == "CPtoDP" {
+		synchronizer.FetchRateLimitPoliciesOnEvent("", "", k8sClient)
+	}
+	synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent("", "", k8sClient, true)
+	synchronizer.FetchAIProvidersOnEvent("", "", "", k8sClient, true)
+	if err := k8sclient.DeployBlockingConditionsCR(managementserver.GetAllBlockingConditions(), k8sClient); err != nil {
+		logger.LoggerLeader.Errorf("Error while applying the blocking conditions: %v", err)
+	}
+	if conf.Agent.Mode == "CPtoDP" {
+		eventhub.FetchAPIsOnStartUp(conf, k8sClient)
+	}
+	synchronizer.FetchKeyManagersOnStartUp(k8sClient)
+}
+
+// startEventConsumers consumes the events of the control plane. The durable queues are shared by the replicas of the
+// agent, hence they are consumed only by the leader so that the followers do not take the events the leader has to
+// apply. Each replica consumes its own queue otherwise to keep its in-memory state up to date.
+func startEventConsumers(conf *config.Config, k8sClient client.Client, elected <-chan struct{}) {
+	if conf.ControlPlane.BrokerConnectionParameters.DurableQueue.Enabled {
+		logger.LoggerLeader.Info("Waiting to be elected as the leader to consume the durable queues")
+		<-elected
+	}
+	messaging.ProcessEvents(conf, k8sClient)
+}
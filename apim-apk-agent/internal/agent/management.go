@@ -0,0 +1,133 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/eventhub"
+	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/reconciler"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/synchronizer"
+	internalutils "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/utils"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	apiUUIDLabel    = "apiUUID"
+	revisionIDLabel = "revisionID"
+)
+
+var errResyncInProgress = errors.New("a resync is already in progress")
+
+// agentController inspects and controls the agent on behalf of the management endpoints
+// The current configurations are read for each operation as they are replaced when the configuration file is
+// reloaded.
+type agentController struct {
+	k8sClient client.Client
+	resyncing atomic.Bool
+}
+
+func newAgentController(k8sClient client.Client) *agentController {
+	return &agentController{k8sClient: k8sClient}
+}
+
+// GetAPIs returns the API CRs in the data plane namespaces with their deployment status
+func (controller *agentController) GetAPIs() ([]managementserver.DeployedAPI, error) {
+	k8sAPIs, _, err := k8sclient.RetrieveAllAPISFromK8s(controller.k8sClient, "")
+	if err != nil {
+		return nil, err
+	}
+	apis := make([]managementserver.DeployedAPI, 0, len(k8sAPIs))
+	for _, k8sAPI := range k8sAPIs {
+		api := managementserver.DeployedAPI{
+			UUID:         k8sAPI.ObjectMeta.Labels[apiUUIDLabel],
+			Name:         k8sAPI.Spec.APIName,
+			Version:      k8sAPI.Spec.APIVersion,
+			BasePath:     k8sAPI.Spec.BasePath,
+			Organization: k8sAPI.Spec.Organization,
+			RevisionID:   k8sAPI.ObjectMeta.Labels[revisionIDLabel],
+			CRName:       k8sAPI.Name,
+			Namespace:    k8sAPI.Namespace,
+			Status:       k8sAPI.Status.DeploymentStatus.Status,
+			Accepted:     k8sAPI.Status.DeploymentStatus.Accepted,
+			Message:      k8sAPI.Status.DeploymentStatus.Message,
+		}
+		if k8sAPI.Status.DeploymentStatus.TransitionTime != nil {
+			api.TransitionTime = k8sAPI.Status.DeploymentStatus.TransitionTime.Format(time.RFC3339)
+		}
+		apis = append(apis, api)
+	}
+	return apis, nil
+}
+
+// GetKeyManagers returns the TokenIssuer CRs created by the agent
+func (controller *agentController) GetKeyManagers() ([]managementserver.DeployedKeyManager, error) {
+	tokenIssuers, _, err := synchronizer.RetrieveAllTokenIssuersFromK8s(controller.k8sClient, "")
+	if err != nil {
+		return nil, err
+	}
+	keyManagers := make([]managementserver.DeployedKeyManager, 0, len(tokenIssuers))
+	for _, tokenIssuer := range tokenIssuers {
+		keyManagers = append(keyManagers, managementserver.DeployedKeyManager{
+			Name:         tokenIssuer.Spec.Name,
+			Organization: tokenIssuer.Spec.Organization,
+			Issuer:       tokenIssuer.Spec.Issuer,
+			CRName:       tokenIssuer.Name,
+			Namespace:    tokenIssuer.Namespace,
+		})
+	}
+	return keyManagers, nil
+}
+
+// Resync reloads the subscription data and, on the leader, reconciles the CRs with the control plane in the
+// background. Only one resync runs at a time.
+func (controller *agentController) Resync() error {
+	if !controller.resyncing.CompareAndSwap(false, true) {
+		return errResyncInProgress
+	}
+	go func() {
+		defer controller.resyncing.Store(false)
+		conf, _ := config.ReadConfigs()
+		eventhub.LoadSubscriptionData(conf)
+		utils.SendInitialEventToAllConnectedClients()
+		if leaderelection.IsLeader() {
+			reconciler.Reconcile(conf, controller.k8sClient)
+		}
+		logger.LoggerAgent.Info("Resync completed")
+	}()
+	return nil
+}
+
+// ResyncAPI redeploys the revisions of an API deployed in the gateway environments of the agent
+func (controller *agentController) ResyncAPI(apiUUID string) error {
+	if !leaderelection.IsLeader() {
+		return fmt.Errorf("the APIs are deployed by the leader")
+	}
+	conf, _ := config.ReadConfigs()
+	_, err := internalutils.FetchAPIsOnEvent(conf, &apiUUID, controller.k8sClient)
+	return err
+}
@@ -0,0 +1,48 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package agent
+
+import (
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/synchronizer"
+	internalutils "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// runRender fetches all the APIs deployed in the configured environments from the control plane and writes the
+// generated CRs to the render directory without connecting to a Kubernetes cluster. The CRs which are not
+// related to a single API (e.g. rate limit policies) are only held in an in-memory client.
+func runRender(conf *config.Config) {
+	logger.LoggerAgent.Infof("Starting apim-apk-agent in the Render mode. CRs will be written to %s",
+		conf.Agent.RenderDirectory)
+	inMemoryClient := fake.NewClientBuilder().WithScheme(newScheme()).Build()
+
+	// Rate limit policies are required to map the policies attached to the APIs
+	synchronizer.FetchRateLimitPoliciesOnEvent("", "", inMemoryClient)
+	apis, err := internalutils.FetchAPIsOnEvent(conf, nil, inMemoryClient)
+	if err != nil {
+		logger.LoggerAgent.Errorf("Error while rendering the APIs: %v", err)
+		return
+	}
+	if apis == nil {
+		logger.LoggerAgent.Error("Unable to fetch the APIs from the control plane for rendering")
+		return
+	}
+	logger.LoggerAgent.Infof("%d APIs rendered to %s", len(*apis), conf.Agent.RenderDirectory)
+}
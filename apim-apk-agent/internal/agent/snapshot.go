@@ -0,0 +1,70 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	fileSnapshotStore      = "File"
+	configMapSnapshotStore = "ConfigMap"
+	secretSnapshotStore    = "Secret"
+)
+
+// restoreSnapshot restores the last snapshot of the applications and the subscriptions and saves the snapshots
+// after each change from then on. It returns whether a snapshot was restored.
+func restoreSnapshot(conf *config.Config, reader client.Reader, k8sClient client.Client) bool {
+	if !conf.Agent.Snapshot.Enabled {
+		return false
+	}
+	store, err := newSnapshotStore(conf, reader, k8sClient)
+	if err != nil {
+		logger.LoggerAgent.Errorf("Snapshots are disabled: %v", err)
+		return false
+	}
+	restored, err := managementserver.RestoreSnapshot(store)
+	if err != nil {
+		logger.LoggerAgent.Errorf("Unable to restore the snapshot: %v", err)
+	}
+	managementserver.EnableSnapshots(store)
+	return restored
+}
+
+// newSnapshotStore returns the snapshot store of the configured type
+func newSnapshotStore(conf *config.Config, reader client.Reader, k8sClient client.Client) (managementserver.SnapshotStore, error) {
+	namespace := conf.Agent.Snapshot.Namespace
+	if namespace == "" {
+		namespace = conf.DataPlane.Namespace
+	}
+	switch {
+	case strings.EqualFold(conf.Agent.Snapshot.Type, fileSnapshotStore):
+		return managementserver.NewFileSnapshotStore(conf.Agent.Snapshot.Path), nil
+	case strings.EqualFold(conf.Agent.Snapshot.Type, configMapSnapshotStore):
+		return managementserver.NewKubernetesSnapshotStore(reader, k8sClient, namespace, conf.Agent.Snapshot.Name, false), nil
+	case strings.EqualFold(conf.Agent.Snapshot.Type, secretSnapshotStore):
+		return managementserver.NewKubernetesSnapshotStore(reader, k8sClient, namespace, conf.Agent.Snapshot.Name, true), nil
+	}
+	return nil, fmt.Errorf("unknown snapshot store type %q", conf.Agent.Snapshot.Type)
+}
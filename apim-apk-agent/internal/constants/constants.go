@@ -47,3 +47,26 @@ const (
 	V1 = "v1"
 	V2 = "v2"
 )
+
+// Blocking condition related constants
+const (
+	BlockingConditionsConfigMapName = "apim-blocking-conditions"
+	BlockingConditionsConfigMapKey  = "blockingConditions.json"
+)
+
+// Agent mode related constants
+const (
+	// RenderMode writes the CRs generated for the APIs to the disk instead of applying them to the cluster
+	RenderMode = "Render"
+)
+
+// API deployment event related constants
+const (
+	EventRecorderName         = "apim-apk-agent"
+	APIDeployedReason         = "APIDeployed"
+	APIUpdatedReason          = "APIUpdated"
+	APIUndeployedReason       = "APIUndeployed"
+	APIDeploymentFailedReason = "APIDeploymentFailed"
+	// DeploymentSummaryAnnotation holds the summary of the child CRs applied with the last revision of an API
+	DeploymentSummaryAnnotation = "apk.wso2.com/deployment-summary"
+)
@@ -20,28 +20,25 @@ package eventhub
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"reflect"
 	"strconv"
-	"time"
 
 	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
 	internalk8sClient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
 	internalutils "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/utils"
-	pkgAuth "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/auth"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	authorizationBasic         string = "Basic "
-	authorizationHeaderDefault string = "Authorization"
-	internalWebAppEP           string = "internal/data/v1/"
+	internalWebAppEP string = "internal/data/v1/"
 	// ContextParam is required to call /apis endpoint
 	ContextParam string = "context"
 	// VersionParam is trequired to call /apis endpoint
@@ -50,6 +47,7 @@ const (
 	GatewayLabelParam string = "gatewayLabel"
 	// APIUUIDParam is required to call /apis endpoint
 	APIUUIDParam string = "apiId"
+	apisEndpoint string = "apis"
 )
 
 var (
@@ -58,6 +56,7 @@ var (
 	subList           *types.SubscriptionList
 	appList           *types.ApplicationList
 	appKeyMappingList *types.ApplicationKeyMappingList
+	apiList           *types.APIList
 
 	resources = []resource{
 		{
@@ -72,9 +71,12 @@ var (
 			endpoint:     "application-key-mappings",
 			responseType: appKeyMappingList,
 		},
+		{
+			endpoint:     apisEndpoint,
+			responseType: apiList,
+		},
 	}
-	accessToken string
-	conf        *config.Config
+	conf *config.Config
 )
 
 type response struct {
@@ -93,8 +95,18 @@ type resource struct {
 
 // LoadInitialData loads subscription/application and keymapping data from control-plane
 func LoadInitialData(configFile *config.Config, client client.Client) {
+	LoadSubscriptionData(configFile)
+	AgentMode := conf.Agent.Mode
+	if AgentMode == "CPtoDP" {
+		FetchAPIsOnStartUp(conf, client)
+	}
+	go utils.SendInitialEventToAllConnectedClients()
+}
+
+// LoadSubscriptionData loads the subscriptions, the applications and the application key mappings from the
+// control plane retrying until they are received
+func LoadSubscriptionData(configFile *config.Config) {
 	conf = configFile
-	accessToken = pkgAuth.GetBasicAuth(configFile.ControlPlane.Username, configFile.ControlPlane.Password)
 	var responseChannel = make(chan response)
 	for _, url := range resources {
 		// Create a local copy of the loop variable
@@ -117,30 +129,21 @@ func LoadInitialData(configFile *config.Config, client client.Client) {
 				// Keep the iteration going on until a response is received.
 				// Error handle
 				go func(d response, endpoint string, responseType interface{}) {
-					// Retry fetching from control plane after a configured time interval
-					if conf.ControlPlane.RetryInterval == 0 {
-						// Assign default retry interval
-						conf.ControlPlane.RetryInterval = 5
-					}
-					logger.LoggerEventhub.Debugf("Time Duration for retrying: %v", conf.ControlPlane.RetryInterval*time.Second)
-					time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
-					logger.LoggerEventhub.Infof("Retrying to fetch APIs from control plane. Time Duration for the next retry: %v", conf.ControlPlane.RetryInterval*time.Second)
+					// Retry fetching from control plane backing off while the control plane keeps failing
+					controlplane.GetClient().WaitBeforeRetry(endpoint)
+					logger.LoggerEventhub.Infof("Retrying to fetch %s from control plane", endpoint)
 					go InvokeService(endpoint, responseType, nil, responseChannel, 0)
 				}(data, localURL.endpoint, localURL.responseType)
 			}
 		}
 	}
-	AgentMode := conf.Agent.Mode
-	if AgentMode == "CPtoDP" {
-		FetchAPIsOnStartUp(conf, client)
-	}
-	go utils.SendInitialEventToAllConnectedClients()
 }
 
 // InvokeService invokes the internal data resource
 func InvokeService(endpoint string, responseType interface{}, queryParamMap map[string]string, c chan response,
 	retryAttempt int) {
-
+	// The configurations are read for each request as they are replaced when the configuration file is reloaded
+	conf, _ := config.ReadConfigs()
 	serviceURL := conf.ControlPlane.ServiceURL + internalWebAppEP + endpoint
 	// Create the request
 	req, err := http.NewRequest("GET", serviceURL, nil)
@@ -164,18 +167,14 @@ func InvokeService(endpoint string, responseType interface{}, queryParamMap map[
 	}
 	req.URL.RawQuery = q.Encode()
 
-	// Check if TLS is enabled
-	skipSSL := conf.ControlPlane.SkipSSLVerification
-
-	// Setting authorization header
-	req.Header.Set(authorizationHeaderDefault, authorizationBasic+accessToken)
-	if reflect.TypeOf(responseType) == reflect.TypeOf(types.APIList{}) {
+	// The data of all the tenants is fetched when multiple tenants are served and filtered afterwards
+	if reflect.TypeOf(responseType) == reflect.TypeOf(apiList) || len(conf.Tenants) > 0 {
 		req.Header.Set("x-wso2-tenant", "ALL")
 	}
 
 	// Make the request
 	//logger.LoggerEventhub.Debug("Sending the request to the control plane over the REST API: " + serviceURL)
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
+	resp, err := controlplane.GetClient().Do(endpoint, req)
 
 	if err != nil {
 		if resp != nil {
@@ -223,12 +222,36 @@ func retrieveDataFromResponseChannel(response response) {
 			logger.LoggerEventhub.Info("Received Application Key Mapping information.")
 			appKeyMappingList := newResponse.(*types.ApplicationKeyMappingList)
 			MarshalMultipleApplicationKeyMappings(appKeyMappingList)
+		case *types.APIList:
+			logger.LoggerEventhub.Info("Received API information.")
+			apiList := newResponse.(*types.APIList)
+			internalutils.UpdateAPIMetadata(apiList.List)
 		default:
 			logger.LoggerEventhub.Debugf("Unknown type %T", t)
 		}
 	}
 }
 
+// LoadAPIMetadata fetches the metadata of the APIs from the control plane and updates their lifecycle statuses and
+// default versions. The metadata of all the APIs is fetched when apiUUID is empty.
+func LoadAPIMetadata(apiUUID string) error {
+	var queryParamMap map[string]string
+	if apiUUID != "" {
+		queryParamMap = map[string]string{APIUUIDParam: apiUUID}
+	}
+	responseChannel := make(chan response)
+	go InvokeService(apisEndpoint, apiList, queryParamMap, responseChannel, 0)
+	data := <-responseChannel
+	if data.Payload == nil {
+		if data.Error == nil {
+			return fmt.Errorf("no API metadata received from the control plane, status code: %d", data.ErrorCode)
+		}
+		return data.Error
+	}
+	retrieveDataFromResponseChannel(data)
+	return nil
+}
+
 // FetchAPIsOnStartUp APIs from control plane during the server start up and push them
 // to the router and enforcer components.
 func FetchAPIsOnStartUp(conf *config.Config, k8sClient client.Client) {
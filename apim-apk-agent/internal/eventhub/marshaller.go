@@ -103,6 +103,9 @@ func MarshalKeyManagers(keyManagersList *[]eventhubTypes.KeyManager) []eventhubT
 func MarshalMultipleApplications(appList *types.ApplicationList) {
 	applicationMap := make(map[string]managementserver.Application)
 	for _, application := range appList.List {
+		if !isOrganizationServed(application.Organization) {
+			continue
+		}
 		applicationSub := MarshalApplication(&application)
 		applicationMap[applicationSub.UUID] = applicationSub
 	}
@@ -114,6 +117,9 @@ func MarshalMultipleApplications(appList *types.ApplicationList) {
 func MarshalMultipleApplicationKeyMappings(keymappingList *types.ApplicationKeyMappingList) {
 	resourceMap := make(map[string]managementserver.ApplicationKeyMapping)
 	for _, keyMapping := range keymappingList.List {
+		if keyMapping.TenantDomain != "" && !isOrganizationServed(keyMapping.TenantDomain) {
+			continue
+		}
 		applicationKeyMappingReference := GetApplicationKeyMappingReference(&keyMapping)
 		keyMappingSub := marshalKeyMapping(&keyMapping)
 		resourceMap[applicationKeyMappingReference] = keyMappingSub
@@ -127,6 +133,9 @@ func MarshalMultipleSubscriptions(subscriptionsList *types.SubscriptionList) {
 	subscriptionMap := make(map[string]managementserver.Subscription)
 	applicationMappingMap := make(map[string]managementserver.ApplicationMapping)
 	for _, subscription := range subscriptionsList.List {
+		if !isOrganizationServed(subscription.ApplicationOrganization) {
+			continue
+		}
 		subscriptionSub := MarshalSubscription(&subscription)
 		subscriptionMap[subscriptionSub.UUID] = subscriptionSub
 		applicationMappingMap[subscriptionSub.UUID] = managementserver.ApplicationMapping{
@@ -175,8 +184,14 @@ func marshalKeyMapping(keyMappingInternal *types.ApplicationKeyMapping) manageme
 		SecurityScheme:        "OAuth2",
 		EnvID:                 "Default",
 		Timestamp:             keyMappingInternal.TimeStamp,
+		Organization:          keyMappingInternal.TenantDomain,
 	}
 }
+
+// isOrganizationServed returns whether the data of the given organization is kept by the agent
+func isOrganizationServed(organization string) bool {
+	return conf == nil || conf.IsTenantServed(organization)
+}
 func marshalKeyManagrConfig(configuration map[string]interface{}) eventhubTypes.KeyManagerConfig {
 	marshalledConfiguration := eventhubTypes.KeyManagerConfig{}
 	if configuration["token_format_string"] != nil {
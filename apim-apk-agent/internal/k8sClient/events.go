@@ -0,0 +1,40 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
+	"k8s.io/client-go/tools/record"
+)
+
+var eventRecorder record.EventRecorder
+
+// SetEventRecorder sets the recorder used to record the Kubernetes events of the API deployments.
+func SetEventRecorder(recorder record.EventRecorder) {
+	eventRecorder = recorder
+}
+
+// RecordAPIEvent records a Kubernetes event on the given API CR. Nothing is recorded until the event recorder
+// is set or while this replica is not the leader.
+func RecordAPIEvent(api *dpv1alpha3.API, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if eventRecorder == nil || !leaderelection.IsLeader() {
+		return
+	}
+	eventRecorder.Eventf(api, eventType, reason, messageFmt, args...)
+}
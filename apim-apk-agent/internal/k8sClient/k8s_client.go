@@ -22,6 +22,8 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -38,13 +40,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
-// DeployAPICR applies the given API struct to the Kubernetes cluster.
-func DeployAPICR(api *dpv1alpha3.API, k8sClient client.Client) {
+// DeployAPICR applies the given API struct to the Kubernetes cluster. The annotations of the given API are merged
+// with the annotations of the existing API CR.
+func DeployAPICR(api *dpv1alpha3.API, k8sClient client.Client) (controllerutil.OperationResult, error) {
 	crAPI := &dpv1alpha3.API{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: api.ObjectMeta.Namespace, Name: api.Name}, crAPI); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -52,18 +56,26 @@ func DeployAPICR(api *dpv1alpha3.API, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), api); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create API CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("API CR created: " + api.Name)
-		}
-	} else {
-		crAPI.Spec = api.Spec
-		crAPI.ObjectMeta.Labels = api.ObjectMeta.Labels
-		if err := k8sClient.Update(context.Background(), crAPI); err != nil {
-			loggers.LoggerK8sClient.Error("Unable to update API CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("API CR updated: " + api.Name)
+			return controllerutil.OperationResultNone, err
 		}
+		loggers.LoggerK8sClient.Info("API CR created: " + api.Name)
+		return controllerutil.OperationResultCreated, nil
+	}
+	crAPI.Spec = api.Spec
+	crAPI.ObjectMeta.Labels = api.ObjectMeta.Labels
+	if len(api.ObjectMeta.Annotations) > 0 && crAPI.ObjectMeta.Annotations == nil {
+		crAPI.ObjectMeta.Annotations = make(map[string]string)
+	}
+	for key, value := range api.ObjectMeta.Annotations {
+		crAPI.ObjectMeta.Annotations[key] = value
 	}
+	if err := k8sClient.Update(context.Background(), crAPI); err != nil {
+		loggers.LoggerK8sClient.Error("Unable to update API CR: " + err.Error())
+		return controllerutil.OperationResultNone, err
+	}
+	crAPI.DeepCopyInto(api)
+	loggers.LoggerK8sClient.Info("API CR updated: " + api.Name)
+	return controllerutil.OperationResultUpdated, nil
 }
 
 // UndeployK8sAPICR removes the API Custom Resource from the Kubernetes cluster based on API ID label.
@@ -71,34 +83,47 @@ func UndeployK8sAPICR(k8sClient client.Client, k8sAPI dpv1alpha3.API) error {
 	err := k8sClient.Delete(context.Background(), &k8sAPI, &client.DeleteOptions{})
 	if err != nil {
 		loggers.LoggerK8sClient.Errorf("Unable to delete API CR: %v", err)
+		RecordAPIEvent(&k8sAPI, corev1.EventTypeWarning, constants.APIDeploymentFailedReason,
+			"Unable to undeploy the API: %v", err)
 		return err
 	}
 	loggers.LoggerK8sClient.Infof("Deleted API CR: %s", k8sAPI.Name)
+	RecordAPIEvent(&k8sAPI, corev1.EventTypeNormal, constants.APIUndeployedReason, "API revision %s undeployed",
+		k8sAPI.ObjectMeta.Labels["revisionID"])
 	return nil
 }
 
 // UndeployAPICR removes the API Custom Resource from the Kubernetes cluster based on API ID label.
-func UndeployAPICR(apiID string, k8sClient client.Client) {
+func UndeployAPICR(apiID string, k8sClient client.Client) error {
 	conf, errReadConfig := config.ReadConfigs()
 	if errReadConfig != nil {
 		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
 	}
-	apiList := &dpv1alpha3.APIList{}
-	err := k8sClient.List(context.Background(), apiList, &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(map[string]string{"apiUUID": apiID})})
-	// Retrieve all API CRs from the Kubernetes cluster
-	if err != nil {
-		loggers.LoggerK8sClient.Errorf("Unable to list API CRs: %v", err)
-	}
-	for _, api := range apiList.Items {
-		if err := UndeployK8sAPICR(k8sClient, api); err != nil {
-			loggers.LoggerK8sClient.Errorf("Unable to delete API CR: %v", err)
+	var errs []error
+	// The API can be in the namespace of any of the tenants
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		apiList := &dpv1alpha3.APIList{}
+		err := k8sClient.List(context.Background(), apiList, &client.ListOptions{Namespace: namespace, LabelSelector: labels.SelectorFromSet(map[string]string{"apiUUID": apiID})})
+		// Retrieve all API CRs from the Kubernetes cluster
+		if err != nil {
+			loggers.LoggerK8sClient.Errorf("Unable to list API CRs: %v", err)
+			errs = append(errs, err)
+			continue
+		}
+		for _, api := range apiList.Items {
+			if err := UndeployK8sAPICR(k8sClient, api); err != nil {
+				loggers.LoggerK8sClient.Errorf("Unable to delete API CR: %v", err)
+				errs = append(errs, err)
+				continue
+			}
+			loggers.LoggerK8sClient.Infof("Deleted API CR: %s", api.Name)
 		}
-		loggers.LoggerK8sClient.Infof("Deleted API CR: %s", api.Name)
 	}
+	return errors.Join(errs...)
 }
 
 // DeployConfigMapCR applies the given ConfigMap struct to the Kubernetes cluster.
-func DeployConfigMapCR(configMap *corev1.ConfigMap, k8sClient client.Client) {
+func DeployConfigMapCR(configMap *corev1.ConfigMap, k8sClient client.Client) error {
 	crConfigMap := &corev1.ConfigMap{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: configMap.ObjectMeta.Namespace, Name: configMap.Name}, crConfigMap); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -106,21 +131,87 @@ func DeployConfigMapCR(configMap *corev1.ConfigMap, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), configMap); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create ConfigMap CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("ConfigMap CR created: " + configMap.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("ConfigMap CR created: " + configMap.Name)
 	} else {
 		crConfigMap.Data = configMap.Data
 		if err := k8sClient.Update(context.Background(), crConfigMap); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update ConfigMap CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("ConfigMap CR updated: " + configMap.Name)
+			return err
+		}
+		loggers.LoggerK8sClient.Info("ConfigMap CR updated: " + configMap.Name)
+	}
+	return nil
+}
+
+// DeployBlockingConditionsCR writes the given blocking conditions to the blocking conditions ConfigMaps in the
+// data plane namespaces. Each namespace gets the blocking conditions of the tenants deployed in it.
+func DeployBlockingConditionsCR(blockingConditions []eventhubTypes.BlockingCondition, k8sClient client.Client) error {
+	conf, _ := config.ReadConfigs()
+	namespaces := conf.GetDataPlaneNamespaces()
+	blockingConditionsOfNamespaces := make(map[string][]eventhubTypes.BlockingCondition, len(namespaces))
+	for _, namespace := range namespaces {
+		blockingConditionsOfNamespaces[namespace] = make([]eventhubTypes.BlockingCondition, 0)
+	}
+	for _, blockingCondition := range blockingConditions {
+		namespace := conf.GetTenantNamespace(blockingCondition.TenantDomain)
+		blockingConditionsOfNamespaces[namespace] = append(blockingConditionsOfNamespaces[namespace], blockingCondition)
+	}
+	var errs []error
+	for _, namespace := range namespaces {
+		if err := deployBlockingConditionsConfigMap(blockingConditionsOfNamespaces[namespace], namespace,
+			k8sClient); err != nil {
+			errs = append(errs, err)
 		}
 	}
+	return errors.Join(errs...)
+}
+
+// deployBlockingConditionsConfigMap writes the given blocking conditions to the blocking conditions ConfigMap in
+// the given namespace
+func deployBlockingConditionsConfigMap(blockingConditions []eventhubTypes.BlockingCondition, namespace string,
+	k8sClient client.Client) error {
+	blockingConditionsJSON, err := json.Marshal(blockingConditions)
+	if err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to marshal blocking conditions: %v", err)
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.BlockingConditionsConfigMapName,
+			Namespace: namespace,
+			Labels:    map[string]string{"InitiateFrom": "CP"},
+		},
+		Data: map[string]string{constants.BlockingConditionsConfigMapKey: string(blockingConditionsJSON)},
+	}
+	crConfigMap := &corev1.ConfigMap{}
+	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: configMap.Namespace, Name: configMap.Name}, crConfigMap); err != nil {
+		if !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Error("Unable to get blocking conditions ConfigMap: " + err.Error())
+			return err
+		}
+		if err := k8sClient.Create(context.Background(), configMap); err != nil {
+			loggers.LoggerK8sClient.Error("Unable to create blocking conditions ConfigMap: " + err.Error())
+			return err
+		}
+		loggers.LoggerK8sClient.Infof("Blocking conditions ConfigMap created in %s with %d conditions", namespace,
+			len(blockingConditions))
+		return nil
+	}
+	crConfigMap.Data = configMap.Data
+	crConfigMap.ObjectMeta.Labels = configMap.ObjectMeta.Labels
+	if err := k8sClient.Update(context.Background(), crConfigMap); err != nil {
+		loggers.LoggerK8sClient.Error("Unable to update blocking conditions ConfigMap: " + err.Error())
+		return err
+	}
+	loggers.LoggerK8sClient.Infof("Blocking conditions ConfigMap updated in %s with %d conditions", namespace,
+		len(blockingConditions))
+	return nil
 }
 
 // DeployHTTPRouteCR applies the given HttpRoute struct to the Kubernetes cluster.
-func DeployHTTPRouteCR(httpRoute *gwapiv1.HTTPRoute, k8sClient client.Client) {
+func DeployHTTPRouteCR(httpRoute *gwapiv1.HTTPRoute, k8sClient client.Client) error {
 	crHTTPRoute := &gwapiv1.HTTPRoute{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: httpRoute.ObjectMeta.Namespace, Name: httpRoute.Name}, crHTTPRoute); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -128,21 +219,22 @@ func DeployHTTPRouteCR(httpRoute *gwapiv1.HTTPRoute, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), httpRoute); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create HTTPRoute CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("HTTPRoute CR created: " + httpRoute.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("HTTPRoute CR created: " + httpRoute.Name)
 	} else {
 		crHTTPRoute.Spec = httpRoute.Spec
 		if err := k8sClient.Update(context.Background(), crHTTPRoute); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update HTTPRoute CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("HTTPRoute CR updated: " + httpRoute.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("HTTPRoute CR updated: " + httpRoute.Name)
 	}
+	return nil
 }
 
 // DeployGQLRouteCR applies the given GqlRoute struct to the Kubernetes cluster.
-func DeployGQLRouteCR(gqlRoute *dpv1alpha2.GQLRoute, k8sClient client.Client) {
+func DeployGQLRouteCR(gqlRoute *dpv1alpha2.GQLRoute, k8sClient client.Client) error {
 	crGQLRoute := &dpv1alpha2.GQLRoute{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: gqlRoute.ObjectMeta.Namespace, Name: gqlRoute.Name}, crGQLRoute); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -150,21 +242,22 @@ func DeployGQLRouteCR(gqlRoute *dpv1alpha2.GQLRoute, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), gqlRoute); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create GQLRoute CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("GQLRoute CR created: " + gqlRoute.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("GQLRoute CR created: " + gqlRoute.Name)
 	} else {
 		crGQLRoute.Spec = gqlRoute.Spec
 		if err := k8sClient.Update(context.Background(), crGQLRoute); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update GQLRoute CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("GQLRoute CR updated: " + gqlRoute.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("GQLRoute CR updated: " + gqlRoute.Name)
 	}
+	return nil
 }
 
 // DeploySecretCR applies the given Secret struct to the Kubernetes cluster.
-func DeploySecretCR(secret *corev1.Secret, k8sClient client.Client) {
+func DeploySecretCR(secret *corev1.Secret, k8sClient client.Client) error {
 	crSecret := &corev1.Secret{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: secret.ObjectMeta.Namespace, Name: secret.Name}, crSecret); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -172,21 +265,22 @@ func DeploySecretCR(secret *corev1.Secret, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), secret); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create Secret CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Secret CR created: " + secret.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Secret CR created: " + secret.Name)
 	} else {
 		crSecret.Data = secret.Data
 		if err := k8sClient.Update(context.Background(), crSecret); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update Secret CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Secret CR updated: " + secret.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Secret CR updated: " + secret.Name)
 	}
+	return nil
 }
 
 // DeployAuthenticationCR applies the given Authentication struct to the Kubernetes cluster.
-func DeployAuthenticationCR(authPolicy *dpv1alpha2.Authentication, k8sClient client.Client) {
+func DeployAuthenticationCR(authPolicy *dpv1alpha2.Authentication, k8sClient client.Client) error {
 	crAuthPolicy := &dpv1alpha2.Authentication{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: authPolicy.ObjectMeta.Namespace, Name: authPolicy.Name}, crAuthPolicy); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -194,21 +288,22 @@ func DeployAuthenticationCR(authPolicy *dpv1alpha2.Authentication, k8sClient cli
 		}
 		if err := k8sClient.Create(context.Background(), authPolicy); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create Authentication CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Authentication CR created: " + authPolicy.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Authentication CR created: " + authPolicy.Name)
 	} else {
 		crAuthPolicy.Spec = authPolicy.Spec
 		if err := k8sClient.Update(context.Background(), crAuthPolicy); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update Authentication CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Authentication CR updated: " + authPolicy.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Authentication CR updated: " + authPolicy.Name)
 	}
+	return nil
 }
 
 // DeployBackendJWTCR applies the given BackendJWT struct to the Kubernetes cluster.
-func DeployBackendJWTCR(backendJWT *dpv1alpha1.BackendJWT, k8sClient client.Client) {
+func DeployBackendJWTCR(backendJWT *dpv1alpha1.BackendJWT, k8sClient client.Client) error {
 	crBackendJWT := &dpv1alpha1.BackendJWT{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: backendJWT.ObjectMeta.Namespace, Name: backendJWT.Name}, crBackendJWT); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -216,21 +311,22 @@ func DeployBackendJWTCR(backendJWT *dpv1alpha1.BackendJWT, k8sClient client.Clie
 		}
 		if err := k8sClient.Create(context.Background(), backendJWT); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create BackendJWT CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("BackendJWT CR created: " + backendJWT.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("BackendJWT CR created: " + backendJWT.Name)
 	} else {
 		crBackendJWT.Spec = backendJWT.Spec
 		if err := k8sClient.Update(context.Background(), crBackendJWT); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update BackendJWT CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("BackendJWT CR updated: " + backendJWT.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("BackendJWT CR updated: " + backendJWT.Name)
 	}
+	return nil
 }
 
 // DeployAPIPolicyCR applies the given APIPolicies struct to the Kubernetes cluster.
-func DeployAPIPolicyCR(apiPolicies *dpv1alpha3.APIPolicy, k8sClient client.Client) {
+func DeployAPIPolicyCR(apiPolicies *dpv1alpha3.APIPolicy, k8sClient client.Client) error {
 	crAPIPolicies := &dpv1alpha3.APIPolicy{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: apiPolicies.ObjectMeta.Namespace, Name: apiPolicies.Name}, crAPIPolicies); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -238,21 +334,22 @@ func DeployAPIPolicyCR(apiPolicies *dpv1alpha3.APIPolicy, k8sClient client.Clien
 		}
 		if err := k8sClient.Create(context.Background(), apiPolicies); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create APIPolicies CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("APIPolicies CR created: " + apiPolicies.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("APIPolicies CR created: " + apiPolicies.Name)
 	} else {
 		crAPIPolicies.Spec = apiPolicies.Spec
 		if err := k8sClient.Update(context.Background(), crAPIPolicies); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update APIPolicies CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("APIPolicies CR updated: " + apiPolicies.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("APIPolicies CR updated: " + apiPolicies.Name)
 	}
+	return nil
 }
 
 // DeployInterceptorServicesCR applies the given InterceptorServices struct to the Kubernetes cluster.
-func DeployInterceptorServicesCR(interceptorServices *dpv1alpha1.InterceptorService, k8sClient client.Client) {
+func DeployInterceptorServicesCR(interceptorServices *dpv1alpha1.InterceptorService, k8sClient client.Client) error {
 	crInterceptorServices := &dpv1alpha1.InterceptorService{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: interceptorServices.ObjectMeta.Namespace, Name: interceptorServices.Name}, crInterceptorServices); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -260,21 +357,22 @@ func DeployInterceptorServicesCR(interceptorServices *dpv1alpha1.InterceptorServ
 		}
 		if err := k8sClient.Create(context.Background(), interceptorServices); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create InterceptorServices CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("InterceptorServices CR created: " + interceptorServices.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("InterceptorServices CR created: " + interceptorServices.Name)
 	} else {
 		crInterceptorServices.Spec = interceptorServices.Spec
 		if err := k8sClient.Update(context.Background(), crInterceptorServices); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update InterceptorServices CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("InterceptorServices CR updated: " + interceptorServices.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("InterceptorServices CR updated: " + interceptorServices.Name)
 	}
+	return nil
 }
 
 // DeployScopeCR applies the given Scope struct to the Kubernetes cluster.
-func DeployScopeCR(scope *dpv1alpha1.Scope, k8sClient client.Client) {
+func DeployScopeCR(scope *dpv1alpha1.Scope, k8sClient client.Client) error {
 	crScope := &dpv1alpha1.Scope{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: scope.ObjectMeta.Namespace, Name: scope.Name}, crScope); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -282,21 +380,22 @@ func DeployScopeCR(scope *dpv1alpha1.Scope, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), scope); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create Scope CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Scope CR created: " + scope.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Scope CR created: " + scope.Name)
 	} else {
 		crScope.Spec = scope.Spec
 		if err := k8sClient.Update(context.Background(), crScope); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update Scope CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Scope CR updated: " + scope.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Scope CR updated: " + scope.Name)
 	}
+	return nil
 }
 
 // DeployAIProviderCR applies the given AIProvider struct to the Kubernetes cluster.
-func DeployAIProviderCR(aiProvider *dpv1alpha3.AIProvider, k8sClient client.Client) {
+func DeployAIProviderCR(aiProvider *dpv1alpha3.AIProvider, k8sClient client.Client) error {
 	crAIProvider := &dpv1alpha3.AIProvider{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: aiProvider.ObjectMeta.Namespace, Name: aiProvider.Name}, crAIProvider); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -304,29 +403,24 @@ func DeployAIProviderCR(aiProvider *dpv1alpha3.AIProvider, k8sClient client.Clie
 		}
 		if err := k8sClient.Create(context.Background(), aiProvider); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create AIProvider CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("AIProvider CR created: " + aiProvider.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("AIProvider CR created: " + aiProvider.Name)
 	} else {
 		crAIProvider.Spec = aiProvider.Spec
 		if err := k8sClient.Update(context.Background(), crAIProvider); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update AIProvider CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("AIProvider CR updated: " + aiProvider.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("AIProvider CR updated: " + aiProvider.Name)
 	}
+	return nil
 }
 
-// DeleteAIProviderCR removes the AIProvider Custom Resource from the Kubernetes cluster based on CR name
-func DeleteAIProviderCR(aiProviderName string, k8sClient client.Client) {
-	conf, errReadConfig := config.ReadConfigs()
-	if errReadConfig != nil {
-		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
-		return
-	}
-
+// DeleteAIProviderCR removes the AIProvider Custom Resource from the given namespace based on CR name
+func DeleteAIProviderCR(aiProviderName string, namespace string, k8sClient client.Client) {
 	crAIProvider := &dpv1alpha3.AIProvider{}
-	err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: aiProviderName}, crAIProvider)
+	err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: aiProviderName}, crAIProvider)
 	if err != nil {
 		if k8error.IsNotFound(err) {
 			loggers.LoggerK8sClient.Infof("AI Provider CR not found: %s", aiProviderName)
@@ -345,16 +439,10 @@ func DeleteAIProviderCR(aiProviderName string, k8sClient client.Client) {
 	}
 }
 
-// DeleteAIRatelimitPolicy removes the AIRatelimitPolicy Custom Resource from the Kubernetes cluster based on CR name
-func DeleteAIRatelimitPolicy(airlName string, k8sClient client.Client) {
-	conf, errReadConfig := config.ReadConfigs()
-	if errReadConfig != nil {
-		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
-		return
-	}
-
+// DeleteAIRatelimitPolicy removes the AIRatelimitPolicy Custom Resource from the given namespace based on CR name
+func DeleteAIRatelimitPolicy(airlName string, namespace string, k8sClient client.Client) {
 	crAIRatelimitPolicy := &dpv1alpha3.AIRateLimitPolicy{}
-	err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: airlName}, crAIRatelimitPolicy)
+	err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: airlName}, crAIRatelimitPolicy)
 	if err != nil {
 		if k8error.IsNotFound(err) {
 			loggers.LoggerK8sClient.Infof("AIRatelimitPolicy CR not found: %s", airlName)
@@ -374,7 +462,7 @@ func DeleteAIRatelimitPolicy(airlName string, k8sClient client.Client) {
 }
 
 // DeployRateLimitPolicyCR applies the given RateLimitPolicies struct to the Kubernetes cluster.
-func DeployRateLimitPolicyCR(rateLimitPolicies *dpv1alpha1.RateLimitPolicy, k8sClient client.Client) {
+func DeployRateLimitPolicyCR(rateLimitPolicies *dpv1alpha1.RateLimitPolicy, k8sClient client.Client) error {
 	crRateLimitPolicies := &dpv1alpha1.RateLimitPolicy{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: rateLimitPolicies.ObjectMeta.Namespace, Name: rateLimitPolicies.Name}, crRateLimitPolicies); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -382,22 +470,23 @@ func DeployRateLimitPolicyCR(rateLimitPolicies *dpv1alpha1.RateLimitPolicy, k8sC
 		}
 		if err := k8sClient.Create(context.Background(), rateLimitPolicies); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create RateLimitPolicies CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("RateLimitPolicies CR created: " + rateLimitPolicies.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("RateLimitPolicies CR created: " + rateLimitPolicies.Name)
 	} else {
 		crRateLimitPolicies.Spec = rateLimitPolicies.Spec
 		crRateLimitPolicies.ObjectMeta.Labels = rateLimitPolicies.ObjectMeta.Labels
 		if err := k8sClient.Update(context.Background(), crRateLimitPolicies); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update RateLimitPolicies CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("RateLimitPolicies CR updated: " + rateLimitPolicies.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("RateLimitPolicies CR updated: " + rateLimitPolicies.Name)
 	}
+	return nil
 }
 
 // DeployAIRateLimitPolicyCR applies the given AIRateLimitPolicies struct to the Kubernetes cluster.
-func DeployAIRateLimitPolicyCR(aiRateLimitPolicies *dpv1alpha3.AIRateLimitPolicy, k8sClient client.Client) {
+func DeployAIRateLimitPolicyCR(aiRateLimitPolicies *dpv1alpha3.AIRateLimitPolicy, k8sClient client.Client) error {
 	crAIRateLimitPolicies := &dpv1alpha3.AIRateLimitPolicy{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: aiRateLimitPolicies.ObjectMeta.Namespace, Name: aiRateLimitPolicies.Name}, crAIRateLimitPolicies); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -405,18 +494,19 @@ func DeployAIRateLimitPolicyCR(aiRateLimitPolicies *dpv1alpha3.AIRateLimitPolicy
 		}
 		if err := k8sClient.Create(context.Background(), aiRateLimitPolicies); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create RateLimitPolicies CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("RateLimitPolicies CR created: " + aiRateLimitPolicies.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("RateLimitPolicies CR created: " + aiRateLimitPolicies.Name)
 	} else {
 		crAIRateLimitPolicies.Spec = aiRateLimitPolicies.Spec
 		crAIRateLimitPolicies.ObjectMeta.Labels = aiRateLimitPolicies.ObjectMeta.Labels
 		if err := k8sClient.Update(context.Background(), crAIRateLimitPolicies); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update RateLimitPolicies CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("RateLimitPolicies CR updated: " + aiRateLimitPolicies.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("RateLimitPolicies CR updated: " + aiRateLimitPolicies.Name)
 	}
+	return nil
 }
 
 // UpdateRateLimitPolicyCR applies the updated policy details to all the RateLimitPolicies struct which has the provided label to the Kubernetes cluster.
@@ -430,7 +520,7 @@ func UpdateRateLimitPolicyCR(policy eventhubTypes.RateLimitPolicy, k8sClient cli
 	labelMap := map[string]string{"rateLimitPolicyName": policyName, "organization": policyOrganization}
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.GetTenantNamespace(policy.TenantDomain),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 	err := k8sClient.List(context.Background(), rateLimitPolicyList, listOption)
@@ -459,11 +549,12 @@ func DeploySubscriptionRateLimitPolicyCR(policy eventhubTypes.SubscriptionPolicy
 		"InitiateFrom": "CP",
 		"CPName":       policy.Name,
 	}
-	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: crName}, &crRateLimitPolicy); err != nil {
+	namespace := conf.GetTenantNamespace(policy.TenantDomain)
+	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: crName}, &crRateLimitPolicy); err != nil {
 		crRateLimitPolicy = dpv1alpha3.RateLimitPolicy{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      crName,
-				Namespace: conf.DataPlane.Namespace,
+				Namespace: namespace,
 				Labels:    labelMap,
 			},
 			Spec: dpv1alpha3.RateLimitPolicySpec{
@@ -532,7 +623,7 @@ func DeployAIRateLimitPolicyFromCPPolicy(policy eventhubTypes.SubscriptionPolicy
 	crRateLimitPolicies := dpv1alpha3.AIRateLimitPolicy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      PrepareSubscritionPolicyCRName(policy.Name, policy.TenantDomain),
-			Namespace: conf.DataPlane.Namespace,
+			Namespace: conf.GetTenantNamespace(policy.TenantDomain),
 			Labels:    labelMap,
 		},
 		Spec: dpv1alpha3.AIRateLimitPolicySpec{
@@ -565,11 +656,10 @@ func DeployAIRateLimitPolicyFromCPPolicy(policy eventhubTypes.SubscriptionPolicy
 	}
 }
 
-// UnDeploySubscriptionRateLimitPolicyCR applies the given RateLimitPolicies struct to the Kubernetes cluster.
-func UnDeploySubscriptionRateLimitPolicyCR(crName string, k8sClient client.Client) {
-	conf, _ := config.ReadConfigs()
+// UnDeploySubscriptionRateLimitPolicyCR removes the RateLimitPolicy with the given name from the given namespace.
+func UnDeploySubscriptionRateLimitPolicyCR(crName string, namespace string, k8sClient client.Client) {
 	crRateLimitPolicies := &dpv1alpha1.RateLimitPolicy{}
-	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: crName}, crRateLimitPolicies); err != nil {
+	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: crName}, crRateLimitPolicies); err != nil {
 		loggers.LoggerK8sClient.Error("Unable to get RateLimitPolicies CR: " + err.Error())
 	}
 	err := k8sClient.Delete(context.Background(), crRateLimitPolicies, &client.DeleteOptions{})
@@ -579,11 +669,10 @@ func UnDeploySubscriptionRateLimitPolicyCR(crName string, k8sClient client.Clien
 	loggers.LoggerK8sClient.Debug("RateLimitPolicies CR deleted: " + crRateLimitPolicies.Name)
 }
 
-// UndeploySubscriptionAIRateLimitPolicyCR applies the given AIRateLimitPolicies struct to the Kubernetes cluster.
-func UndeploySubscriptionAIRateLimitPolicyCR(crName string, k8sClient client.Client) {
-	conf, _ := config.ReadConfigs()
+// UndeploySubscriptionAIRateLimitPolicyCR removes the AIRateLimitPolicy with the given name from the given namespace.
+func UndeploySubscriptionAIRateLimitPolicyCR(crName string, namespace string, k8sClient client.Client) {
 	crAIRateLimitPolicies := &dpv1alpha3.AIRateLimitPolicy{}
-	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: crName}, crAIRateLimitPolicies); err != nil {
+	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: crName}, crAIRateLimitPolicies); err != nil {
 		loggers.LoggerK8sClient.Error("Unable to get AIRateLimitPolicies CR: " + err.Error())
 	}
 	err := k8sClient.Delete(context.Background(), crAIRateLimitPolicies, &client.DeleteOptions{})
@@ -594,7 +683,7 @@ func UndeploySubscriptionAIRateLimitPolicyCR(crName string, k8sClient client.Cli
 }
 
 // DeployBackendCR applies the given Backends struct to the Kubernetes cluster.
-func DeployBackendCR(backends *dpv1alpha2.Backend, k8sClient client.Client) {
+func DeployBackendCR(backends *dpv1alpha2.Backend, k8sClient client.Client) error {
 	crBackends := &dpv1alpha2.Backend{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: backends.ObjectMeta.Namespace, Name: backends.Name}, crBackends); err != nil {
 		if !k8error.IsNotFound(err) {
@@ -602,17 +691,18 @@ func DeployBackendCR(backends *dpv1alpha2.Backend, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), backends); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create Backends CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Backends CR created: " + backends.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Backends CR created: " + backends.Name)
 	} else {
 		crBackends.Spec = backends.Spec
 		if err := k8sClient.Update(context.Background(), crBackends); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update Backends CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Backends CR updated: " + backends.Name)
+			return err
 		}
+		loggers.LoggerK8sClient.Info("Backends CR updated: " + backends.Name)
 	}
+	return nil
 }
 
 // CreateAndUpdateTokenIssuersCR applies the given TokenIssuers struct to the Kubernetes cluster.
@@ -625,9 +715,10 @@ func CreateAndUpdateTokenIssuersCR(keyManager eventhubTypes.ResolvedKeyManager,
 		"InitiateFrom": "CP",
 	}
 
+	namespace := conf.GetTenantNamespace(keyManager.Organization)
 	tokenIssuer := dpv1alpha2.TokenIssuer{
 		ObjectMeta: metav1.ObjectMeta{Name: keyManager.UUID,
-			Namespace: conf.DataPlane.Namespace,
+			Namespace: namespace,
 			Labels:    labelMap,
 		},
 		Spec: dpv1alpha2.TokenIssuerSpec{
@@ -669,7 +760,7 @@ func CreateAndUpdateTokenIssuersCR(keyManager eventhubTypes.ResolvedKeyManager,
 
 	internalKeyTokenIssuer := dpv1alpha2.TokenIssuer{
 		ObjectMeta: metav1.ObjectMeta{Name: keyManager.Organization + constants.InternalKeySuffix,
-			Namespace: conf.DataPlane.Namespace,
+			Namespace: namespace,
 			Labels:    labelMap,
 		},
 		Spec: dpv1alpha2.TokenIssuerSpec{
@@ -734,7 +825,7 @@ func DeleteTokenIssuersCR(k8sClient client.Client, keymanagerName string, tenant
 	labelMap := map[string]string{"name": sha1ValueofKmName, "organization": sha1ValueOfOrganization}
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.GetTenantNamespace(tenantDomain),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 
@@ -764,7 +855,7 @@ func UpdateTokenIssuersCR(keyManager eventhubTypes.ResolvedKeyManager, k8sClient
 	sha1ValueOfOrganization := getSha1Value(keyManager.Organization)
 	labelMap := map[string]string{"name": sha1ValueofKmName, "organization": sha1ValueOfOrganization}
 	tokenIssuer := &dpv1alpha2.TokenIssuer{}
-	err := k8sClient.Get(context.Background(), client.ObjectKey{Name: keyManager.UUID, Namespace: conf.DataPlane.Namespace}, tokenIssuer)
+	err := k8sClient.Get(context.Background(), client.ObjectKey{Name: keyManager.UUID, Namespace: conf.GetTenantNamespace(keyManager.Organization)}, tokenIssuer)
 	if err != nil {
 		loggers.LoggerK8sClient.Error("Unable to get TokenIssuer CR: " + err.Error())
 		return err
@@ -815,16 +906,29 @@ func getSha1Value(input string) string {
 	return hex.EncodeToString(hashBytes)
 }
 
-// RetrieveAllAPISFromK8s retrieves all the API CRs from the Kubernetes cluster
+// RetrieveAllAPISFromK8s retrieves all the API CRs from the data plane namespaces of all the tenants
 func RetrieveAllAPISFromK8s(k8sClient client.Client, nextToken string) ([]dpv1alpha3.API, string, error) {
 	conf, _ := config.ReadConfigs()
+	resolvedAPIList := make([]dpv1alpha3.API, 0)
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		apiList, _, err := retrieveAPIsFromK8sNamespace(k8sClient, namespace, nextToken)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedAPIList = append(resolvedAPIList, apiList...)
+	}
+	return resolvedAPIList, "", nil
+}
+
+// retrieveAPIsFromK8sNamespace retrieves all the API CRs in the given namespace
+func retrieveAPIsFromK8sNamespace(k8sClient client.Client, namespace string, nextToken string) ([]dpv1alpha3.API, string, error) {
 	apiList := dpv1alpha3.APIList{}
 	resolvedAPIList := make([]dpv1alpha3.API, 0)
 	var err error
 	if nextToken == "" {
-		err = k8sClient.List(context.Background(), &apiList, &client.ListOptions{Namespace: conf.DataPlane.Namespace})
+		err = k8sClient.List(context.Background(), &apiList, &client.ListOptions{Namespace: namespace})
 	} else {
-		err = k8sClient.List(context.Background(), &apiList, &client.ListOptions{Namespace: conf.DataPlane.Namespace, Continue: nextToken})
+		err = k8sClient.List(context.Background(), &apiList, &client.ListOptions{Namespace: namespace, Continue: nextToken})
 	}
 	if err != nil {
 		loggers.LoggerK8sClient.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Failed to get application from k8s %v", err.Error()))
@@ -832,7 +936,7 @@ func RetrieveAllAPISFromK8s(k8sClient client.Client, nextToken string) ([]dpv1al
 	}
 	resolvedAPIList = append(resolvedAPIList, apiList.Items...)
 	if apiList.Continue != "" {
-		tempAPIList, _, err := RetrieveAllAPISFromK8s(k8sClient, apiList.Continue)
+		tempAPIList, _, err := retrieveAPIsFromK8sNamespace(k8sClient, namespace, apiList.Continue)
 		if err != nil {
 			return nil, "", err
 		}
@@ -841,16 +945,29 @@ func RetrieveAllAPISFromK8s(k8sClient client.Client, nextToken string) ([]dpv1al
 	return resolvedAPIList, apiList.Continue, nil
 }
 
-// RetrieveAllAIProvidersFromK8s retrieves all the API CRs from the Kubernetes cluster
+// RetrieveAllAIProvidersFromK8s retrieves all the AIProvider CRs from the data plane namespaces of all the tenants
 func RetrieveAllAIProvidersFromK8s(k8sClient client.Client, nextToken string) ([]dpv1alpha3.AIProvider, string, error) {
 	conf, _ := config.ReadConfigs()
+	resolvedAIProviderList := make([]dpv1alpha3.AIProvider, 0)
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		aiProviderList, _, err := retrieveAIProvidersFromK8sNamespace(k8sClient, namespace, nextToken)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedAIProviderList = append(resolvedAIProviderList, aiProviderList...)
+	}
+	return resolvedAIProviderList, "", nil
+}
+
+// retrieveAIProvidersFromK8sNamespace retrieves all the AIProvider CRs in the given namespace
+func retrieveAIProvidersFromK8sNamespace(k8sClient client.Client, namespace string, nextToken string) ([]dpv1alpha3.AIProvider, string, error) {
 	aiProviderList := dpv1alpha3.AIProviderList{}
 	resolvedAIProviderList := make([]dpv1alpha3.AIProvider, 0)
 	var err error
 	if nextToken == "" {
-		err = k8sClient.List(context.Background(), &aiProviderList, &client.ListOptions{Namespace: conf.DataPlane.Namespace})
+		err = k8sClient.List(context.Background(), &aiProviderList, &client.ListOptions{Namespace: namespace})
 	} else {
-		err = k8sClient.List(context.Background(), &aiProviderList, &client.ListOptions{Namespace: conf.DataPlane.Namespace, Continue: nextToken})
+		err = k8sClient.List(context.Background(), &aiProviderList, &client.ListOptions{Namespace: namespace, Continue: nextToken})
 	}
 	if err != nil {
 		loggers.LoggerK8sClient.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Failed to get ai provider from k8s %v", err.Error()))
@@ -858,7 +975,7 @@ func RetrieveAllAIProvidersFromK8s(k8sClient client.Client, nextToken string) ([
 	}
 	resolvedAIProviderList = append(resolvedAIProviderList, aiProviderList.Items...)
 	if aiProviderList.Continue != "" {
-		tempAIProviderList, _, err := RetrieveAllAIProvidersFromK8s(k8sClient, aiProviderList.Continue)
+		tempAIProviderList, _, err := retrieveAIProvidersFromK8sNamespace(k8sClient, namespace, aiProviderList.Continue)
 		if err != nil {
 			return nil, "", err
 		}
@@ -867,16 +984,29 @@ func RetrieveAllAIProvidersFromK8s(k8sClient client.Client, nextToken string) ([
 	return resolvedAIProviderList, aiProviderList.Continue, nil
 }
 
-// RetrieveAllRatelimitPoliciesSFromK8s retrieves all the API CRs from the Kubernetes cluster
+// RetrieveAllRatelimitPoliciesSFromK8s retrieves all the RateLimitPolicy CRs from the data plane namespaces of all the tenants
 func RetrieveAllRatelimitPoliciesSFromK8s(k8sClient client.Client, nextToken string) ([]dpv1alpha3.RateLimitPolicy, string, error) {
 	conf, _ := config.ReadConfigs()
+	resolvedRLList := make([]dpv1alpha3.RateLimitPolicy, 0)
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		rlList, _, err := retrieveRatelimitPoliciesSFromK8sNamespace(k8sClient, namespace, nextToken)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedRLList = append(resolvedRLList, rlList...)
+	}
+	return resolvedRLList, "", nil
+}
+
+// retrieveRatelimitPoliciesSFromK8sNamespace retrieves all the RateLimitPolicy CRs in the given namespace
+func retrieveRatelimitPoliciesSFromK8sNamespace(k8sClient client.Client, namespace string, nextToken string) ([]dpv1alpha3.RateLimitPolicy, string, error) {
 	rlList := dpv1alpha3.RateLimitPolicyList{}
 	resolvedRLList := make([]dpv1alpha3.RateLimitPolicy, 0)
 	var err error
 	if nextToken == "" {
-		err = k8sClient.List(context.Background(), &rlList, &client.ListOptions{Namespace: conf.DataPlane.Namespace})
+		err = k8sClient.List(context.Background(), &rlList, &client.ListOptions{Namespace: namespace})
 	} else {
-		err = k8sClient.List(context.Background(), &rlList, &client.ListOptions{Namespace: conf.DataPlane.Namespace, Continue: nextToken})
+		err = k8sClient.List(context.Background(), &rlList, &client.ListOptions{Namespace: namespace, Continue: nextToken})
 	}
 	if err != nil {
 		loggers.LoggerK8sClient.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Failed to get ratelimitpolicies from k8s %v", err.Error()))
@@ -884,7 +1014,7 @@ func RetrieveAllRatelimitPoliciesSFromK8s(k8sClient client.Client, nextToken str
 	}
 	resolvedRLList = append(resolvedRLList, rlList.Items...)
 	if rlList.Continue != "" {
-		tempRLList, _, err := RetrieveAllRatelimitPoliciesSFromK8s(k8sClient, rlList.Continue)
+		tempRLList, _, err := retrieveRatelimitPoliciesSFromK8sNamespace(k8sClient, namespace, rlList.Continue)
 		if err != nil {
 			return nil, "", err
 		}
@@ -893,16 +1023,29 @@ func RetrieveAllRatelimitPoliciesSFromK8s(k8sClient client.Client, nextToken str
 	return resolvedRLList, rlList.Continue, nil
 }
 
-// RetrieveAllAIRatelimitPoliciesSFromK8s retrieves all the API CRs from the Kubernetes cluster
+// RetrieveAllAIRatelimitPoliciesSFromK8s retrieves all the AIRateLimitPolicy CRs from the data plane namespaces of all the tenants
 func RetrieveAllAIRatelimitPoliciesSFromK8s(k8sClient client.Client, nextToken string) ([]dpv1alpha3.AIRateLimitPolicy, string, error) {
 	conf, _ := config.ReadConfigs()
+	resolvedAIRLList := make([]dpv1alpha3.AIRateLimitPolicy, 0)
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		airlList, _, err := retrieveAIRatelimitPoliciesSFromK8sNamespace(k8sClient, namespace, nextToken)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedAIRLList = append(resolvedAIRLList, airlList...)
+	}
+	return resolvedAIRLList, "", nil
+}
+
+// retrieveAIRatelimitPoliciesSFromK8sNamespace retrieves all the AIRateLimitPolicy CRs in the given namespace
+func retrieveAIRatelimitPoliciesSFromK8sNamespace(k8sClient client.Client, namespace string, nextToken string) ([]dpv1alpha3.AIRateLimitPolicy, string, error) {
 	airlList := dpv1alpha3.AIRateLimitPolicyList{}
 	resolvedAIRLList := make([]dpv1alpha3.AIRateLimitPolicy, 0)
 	var err error
 	if nextToken == "" {
-		err = k8sClient.List(context.Background(), &airlList, &client.ListOptions{Namespace: conf.DataPlane.Namespace})
+		err = k8sClient.List(context.Background(), &airlList, &client.ListOptions{Namespace: namespace})
 	} else {
-		err = k8sClient.List(context.Background(), &airlList, &client.ListOptions{Namespace: conf.DataPlane.Namespace, Continue: nextToken})
+		err = k8sClient.List(context.Background(), &airlList, &client.ListOptions{Namespace: namespace, Continue: nextToken})
 	}
 	if err != nil {
 		loggers.LoggerK8sClient.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Failed to get airatelimitpolicies from k8s %v", err.Error()))
@@ -910,7 +1053,7 @@ func RetrieveAllAIRatelimitPoliciesSFromK8s(k8sClient client.Client, nextToken s
 	}
 	resolvedAIRLList = append(resolvedAIRLList, airlList.Items...)
 	if airlList.Continue != "" {
-		tempAIRLList, _, err := RetrieveAllAIRatelimitPoliciesSFromK8s(k8sClient, airlList.Continue)
+		tempAIRLList, _, err := retrieveAIRatelimitPoliciesSFromK8sNamespace(k8sClient, namespace, airlList.Continue)
 		if err != nil {
 			return nil, "", err
 		}
@@ -919,6 +1062,84 @@ func RetrieveAllAIRatelimitPoliciesSFromK8s(k8sClient client.Client, nextToken s
 	return resolvedAIRLList, airlList.Continue, nil
 }
 
+// RetrieveAllHTTPRoutesFromK8s retrieves all the HTTPRoute CRs from the data plane namespaces of all the tenants
+func RetrieveAllHTTPRoutesFromK8s(k8sClient client.Client, nextToken string) ([]gwapiv1.HTTPRoute, string, error) {
+	conf, _ := config.ReadConfigs()
+	resolvedHTTPRouteList := make([]gwapiv1.HTTPRoute, 0)
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		httpRouteList, _, err := retrieveHTTPRoutesFromK8sNamespace(k8sClient, namespace, nextToken)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedHTTPRouteList = append(resolvedHTTPRouteList, httpRouteList...)
+	}
+	return resolvedHTTPRouteList, "", nil
+}
+
+// retrieveHTTPRoutesFromK8sNamespace retrieves all the HTTPRoute CRs in the given namespace
+func retrieveHTTPRoutesFromK8sNamespace(k8sClient client.Client, namespace string, nextToken string) ([]gwapiv1.HTTPRoute, string, error) {
+	httpRouteList := gwapiv1.HTTPRouteList{}
+	resolvedHTTPRouteList := make([]gwapiv1.HTTPRoute, 0)
+	var err error
+	if nextToken == "" {
+		err = k8sClient.List(context.Background(), &httpRouteList, &client.ListOptions{Namespace: namespace})
+	} else {
+		err = k8sClient.List(context.Background(), &httpRouteList, &client.ListOptions{Namespace: namespace, Continue: nextToken})
+	}
+	if err != nil {
+		loggers.LoggerK8sClient.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Failed to get httproutes from k8s %v", err.Error()))
+		return nil, "", err
+	}
+	resolvedHTTPRouteList = append(resolvedHTTPRouteList, httpRouteList.Items...)
+	if httpRouteList.Continue != "" {
+		tempHTTPRouteList, _, err := retrieveHTTPRoutesFromK8sNamespace(k8sClient, namespace, httpRouteList.Continue)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedHTTPRouteList = append(resolvedHTTPRouteList, tempHTTPRouteList...)
+	}
+	return resolvedHTTPRouteList, httpRouteList.Continue, nil
+}
+
+// RetrieveAllGQLRoutesFromK8s retrieves all the GQLRoute CRs from the data plane namespaces of all the tenants
+func RetrieveAllGQLRoutesFromK8s(k8sClient client.Client, nextToken string) ([]dpv1alpha2.GQLRoute, string, error) {
+	conf, _ := config.ReadConfigs()
+	resolvedGQLRouteList := make([]dpv1alpha2.GQLRoute, 0)
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		gqlRouteList, _, err := retrieveGQLRoutesFromK8sNamespace(k8sClient, namespace, nextToken)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedGQLRouteList = append(resolvedGQLRouteList, gqlRouteList...)
+	}
+	return resolvedGQLRouteList, "", nil
+}
+
+// retrieveGQLRoutesFromK8sNamespace retrieves all the GQLRoute CRs in the given namespace
+func retrieveGQLRoutesFromK8sNamespace(k8sClient client.Client, namespace string, nextToken string) ([]dpv1alpha2.GQLRoute, string, error) {
+	gqlRouteList := dpv1alpha2.GQLRouteList{}
+	resolvedGQLRouteList := make([]dpv1alpha2.GQLRoute, 0)
+	var err error
+	if nextToken == "" {
+		err = k8sClient.List(context.Background(), &gqlRouteList, &client.ListOptions{Namespace: namespace})
+	} else {
+		err = k8sClient.List(context.Background(), &gqlRouteList, &client.ListOptions{Namespace: namespace, Continue: nextToken})
+	}
+	if err != nil {
+		loggers.LoggerK8sClient.ErrorC(logging.PrintError(logging.Error1102, logging.CRITICAL, "Failed to get gqlroutes from k8s %v", err.Error()))
+		return nil, "", err
+	}
+	resolvedGQLRouteList = append(resolvedGQLRouteList, gqlRouteList.Items...)
+	if gqlRouteList.Continue != "" {
+		tempGQLRouteList, _, err := retrieveGQLRoutesFromK8sNamespace(k8sClient, namespace, gqlRouteList.Continue)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedGQLRouteList = append(resolvedGQLRouteList, tempGQLRouteList...)
+	}
+	return resolvedGQLRouteList, gqlRouteList.Continue, nil
+}
+
 // PrepareSubscritionPolicyCRName prepare the cr name for a given policy name and organization pair
 func PrepareSubscritionPolicyCRName(name, org string) string {
 	return getSha1Value(fmt.Sprintf("%s-%s", name, org))
@@ -0,0 +1,70 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// metricsClient is a Kubernetes client which exposes the results of the writes as metrics
+type metricsClient struct {
+	client.Client
+}
+
+// NewMetricsClient returns a client which records the result of each create, update, patch and delete made through
+// the given client as metrics.
+func NewMetricsClient(c client.Client) client.Client {
+	return &metricsClient{Client: c}
+}
+
+func (c *metricsClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	metrics.RecordCRApply(getKind(obj), err)
+	return err
+}
+
+func (c *metricsClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	metrics.RecordCRApply(getKind(obj), err)
+	return err
+}
+
+func (c *metricsClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	metrics.RecordCRApply(getKind(obj), err)
+	return err
+}
+
+func (c *metricsClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	metrics.RecordCRApply(getKind(obj), client.IgnoreNotFound(err))
+	return err
+}
+
+// getKind returns the kind of the given object. The type name is used for the typed objects as their kind is not
+// set in most cases.
+func getKind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.TypeOf(obj).Elem().Name()
+}
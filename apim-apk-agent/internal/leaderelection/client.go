@@ -0,0 +1,120 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package leaderelection
+
+import (
+	"context"
+	"errors"
+
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrNotLeader is returned for the writes dropped as this replica is not the leader
+var ErrNotLeader = errors.New("the data plane is written only by the leader replica")
+
+// leaderOnlyClient is a Kubernetes client which drops the writes while this replica is not the leader
+type leaderOnlyClient struct {
+	client.Client
+}
+
+// leaderOnlySubResourceClient is a sub resource client which drops the writes while this replica is not the leader
+type leaderOnlySubResourceClient struct {
+	client.SubResourceClient
+}
+
+// NewLeaderOnlyClient returns a client which reads through the given client and writes through it only while
+// this replica is the leader. Dropped writes fail with ErrNotLeader.
+func NewLeaderOnlyClient(c client.Client) client.Client {
+	return &leaderOnlyClient{Client: c}
+}
+
+func skipWrite(operation string, obj client.Object) bool {
+	if IsLeader() {
+		return false
+	}
+	logger.LoggerLeader.Debugf("Skipped the %s of %T %s as this replica is not the leader", operation, obj, obj.GetName())
+	return true
+}
+
+func (c *leaderOnlyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if skipWrite("create", obj) {
+		return ErrNotLeader
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *leaderOnlyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if skipWrite("update", obj) {
+		return ErrNotLeader
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *leaderOnlyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if skipWrite("patch", obj) {
+		return ErrNotLeader
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *leaderOnlyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if skipWrite("delete", obj) {
+		return ErrNotLeader
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *leaderOnlyClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if skipWrite("delete", obj) {
+		return ErrNotLeader
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *leaderOnlyClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *leaderOnlyClient) SubResource(subResource string) client.SubResourceClient {
+	return &leaderOnlySubResourceClient{SubResourceClient: c.Client.SubResource(subResource)}
+}
+
+func (c *leaderOnlySubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object,
+	opts ...client.SubResourceCreateOption) error {
+	if skipWrite("sub resource create", obj) {
+		return ErrNotLeader
+	}
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *leaderOnlySubResourceClient) Update(ctx context.Context, obj client.Object,
+	opts ...client.SubResourceUpdateOption) error {
+	if skipWrite("sub resource update", obj) {
+		return ErrNotLeader
+	}
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (c *leaderOnlySubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.SubResourcePatchOption) error {
+	if skipWrite("sub resource patch", obj) {
+		return ErrNotLeader
+	}
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
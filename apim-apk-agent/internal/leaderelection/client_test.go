@@ -0,0 +1,63 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package leaderelection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLeaderOnlyClient(t *testing.T) {
+	defer SetLeader(true)
+	fakeClient := fake.NewClientBuilder().Build()
+	leaderOnlyClient := NewLeaderOnlyClient(fakeClient)
+	configMapKey := client.ObjectKey{Namespace: "apk", Name: "test-config"}
+	newConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: configMapKey.Namespace, Name: configMapKey.Name}}
+	}
+
+	SetLeader(false)
+	if err := leaderOnlyClient.Create(context.Background(), newConfigMap()); !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected the create to fail with ErrNotLeader, but got %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), configMapKey, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("Expected the ConfigMap not to be created while not the leader")
+	}
+
+	SetLeader(true)
+	if err := leaderOnlyClient.Create(context.Background(), newConfigMap()); err != nil {
+		t.Fatalf("Error while creating the ConfigMap as the leader: %v", err)
+	}
+	if err := leaderOnlyClient.Get(context.Background(), configMapKey, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("Expected the ConfigMap to be created as the leader, but got %v", err)
+	}
+
+	SetLeader(false)
+	if err := leaderOnlyClient.Delete(context.Background(), newConfigMap()); !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected the delete to fail with ErrNotLeader, but got %v", err)
+	}
+	if err := leaderOnlyClient.Get(context.Background(), configMapKey, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("Expected the ConfigMap to remain while not the leader, but got %v", err)
+	}
+}
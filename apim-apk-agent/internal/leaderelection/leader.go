@@ -0,0 +1,49 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package leaderelection holds the leadership state of the agent replica. Only the leader applies the CRs to the
+// data plane and sends the revision acknowledgements to the control plane while the other replicas keep their
+// in-memory state up to date to serve the connected clients.
+package leaderelection
+
+import (
+	"sync/atomic"
+
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+)
+
+// follower is kept instead of the leadership so that a replica is the leader unless the leader election is enabled
+var follower atomic.Bool
+
+// IsLeader returns whether this replica is the leader
+func IsLeader() bool {
+	return !follower.Load()
+}
+
+// SetLeader sets whether this replica is the leader
+func SetLeader(isLeader bool) {
+	follower.Store(!isLeader)
+}
+
+// WatchLeadership marks this replica as the leader once the elected channel is closed and runs onElected.
+func WatchLeadership(elected <-chan struct{}, onElected func()) {
+	logger.LoggerLeader.Info("Waiting to be elected as the leader")
+	<-elected
+	logger.LoggerLeader.Info("Elected as the leader")
+	SetLeader(true)
+	onElected()
+}
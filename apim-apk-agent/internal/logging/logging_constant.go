@@ -145,7 +145,7 @@ var Mapper = map[int]pkgLogging.ErrorDetails{
 	},
 	Error1102: {
 		ErrorCode: Error1102,
-		Message:   "Error reading the log configs.",
+		Message:   "Error watching the configuration files.",
 	},
 	Error1103: {
 		ErrorCode: Error1103,
@@ -24,78 +24,265 @@
 package mapper
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/constants"
 	internalk8sClient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/transformer"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// ResourceResult is the result of applying a single CR of an API
+type ResourceResult struct {
+	Kind  string
+	Name  string
+	Error error
+}
+
+// DeploymentResult is the result of applying the CRs of an API revision
+type DeploymentResult struct {
+	APIName   string
+	Namespace string
+	// Operation denotes whether the API CR was created or updated
+	Operation controllerutil.OperationResult
+	Resources []ResourceResult
+	// RolledBack denotes whether the CRs were restored to the previous revision due to a failure
+	RolledBack bool
+	// RollbackError is the error occurred while restoring the previous revision
+	RollbackError error
+}
+
+// deploymentSummary is written to the API CR to show which child CRs were applied
+type deploymentSummary struct {
+	Applied []string          `json:"applied"`
+	Failed  map[string]string `json:"failed,omitempty"`
+}
+
+// Failed returns the results of the CRs which could not be applied
+func (result *DeploymentResult) Failed() []ResourceResult {
+	failed := make([]ResourceResult, 0)
+	for _, resource := range result.Resources {
+		if resource.Error != nil {
+			failed = append(failed, resource)
+		}
+	}
+	return failed
+}
+
+// Err returns an error listing the CRs which could not be applied, or nil when all the CRs are applied
+func (result *DeploymentResult) Err() error {
+	failed := result.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	failures := make([]string, 0, len(failed))
+	for _, resource := range failed {
+		failures = append(failures, fmt.Sprintf("%s/%s: %v", resource.Kind, resource.Name, resource.Error))
+	}
+	return fmt.Errorf("%d of %d CRs of the API %s could not be applied: %s", len(failed), len(result.Resources),
+		result.APIName, strings.Join(failures, ", "))
+}
+
+func (result *DeploymentResult) add(kind string, name string, err error) {
+	result.Resources = append(result.Resources, ResourceResult{Kind: kind, Name: name, Error: err})
+}
+
+// summary returns the summary of the CRs applied so far
+func (result *DeploymentResult) summary() string {
+	summary := deploymentSummary{Applied: make([]string, 0)}
+	for _, resource := range result.Resources {
+		if resource.Error == nil {
+			summary.Applied = append(summary.Applied, resource.Kind+"/"+resource.Name)
+			continue
+		}
+		if summary.Failed == nil {
+			summary.Failed = make(map[string]string)
+		}
+		summary.Failed[resource.Kind+"/"+resource.Name] = resource.Error.Error()
+	}
+	sort.Strings(summary.Applied)
+	summaryJSON, _ := json.Marshal(summary)
+	return string(summaryJSON)
+}
+
 // MapAndCreateCR will read the CRD Yaml and based on the Kind of the CR, unmarshal and maps the
-// data and sends to the K8-Client for creating the respective CR inside the cluster
-func MapAndCreateCR(k8sArtifact transformer.K8sArtifacts, k8sClient client.Client) *error {
+// data and sends to the K8-Client for creating the respective CR inside the cluster. The CRs are applied as a
+// single transaction: the existing CRs are snapshotted first, and if any CR fails to apply, the CRs applied so far
+// are restored to the snapshot. The API CR is applied last with a summary of the child CRs, and a Kubernetes event
+// is recorded on it with the outcome.
+func MapAndCreateCR(k8sArtifact transformer.K8sArtifacts, k8sClient client.Client) *DeploymentResult {
+	result := &DeploymentResult{APIName: k8sArtifact.API.Name, Operation: controllerutil.OperationResultNone}
 	namespace, err := getDeploymentNamespace(k8sArtifact)
 	if err != nil {
-		return &err
+		result.add("API", k8sArtifact.API.Name, err)
+		return result
 	}
+	result.Namespace = namespace
 	k8sArtifact.API.Namespace = namespace
-	
+
+	deployments := getCRDeployments(&k8sArtifact, namespace, k8sClient)
+	deployments = append(deployments, crDeployment{
+		kind:   "API",
+		object: &k8sArtifact.API,
+		deploy: func() error {
+			if k8sArtifact.API.ObjectMeta.Annotations == nil {
+				k8sArtifact.API.ObjectMeta.Annotations = make(map[string]string)
+			}
+			k8sArtifact.API.ObjectMeta.Annotations[constants.DeploymentSummaryAnnotation] = result.summary()
+			operation, err := internalk8sClient.DeployAPICR(&k8sArtifact.API, k8sClient)
+			result.Operation = operation
+			return err
+		},
+	})
+
+	snapshots, err := takeSnapshots(deployments, k8sClient)
+	if err != nil {
+		// Nothing is applied when the existing CRs can not be snapshotted
+		result.add("API", k8sArtifact.API.Name, err)
+		recordDeploymentEvent(&k8sArtifact.API, result)
+		return result
+	}
+	for i, deployment := range deployments {
+		err := deployment.deploy()
+		result.add(deployment.kind, deployment.object.GetName(), err)
+		if err != nil {
+			logger.LoggerMapper.Errorf("Unable to apply %s %s of the API %s. Restoring the previous revision",
+				deployment.kind, deployment.object.GetName(), k8sArtifact.API.Name)
+			result.RolledBack = true
+			// The failed CR is left out as a failed create or update does not change it
+			result.RollbackError = rollback(deployments[:i], snapshots[:i], k8sClient)
+			break
+		}
+	}
+	recordDeploymentEvent(&k8sArtifact.API, result)
+	return result
+}
+
+// getCRDeployments returns the child CRs of the API in the order they should be applied
+func getCRDeployments(k8sArtifact *transformer.K8sArtifacts, namespace string, k8sClient client.Client) []crDeployment {
+	deployments := make([]crDeployment, 0)
 	for _, configMaps := range k8sArtifact.ConfigMaps {
 		configMaps.Namespace = namespace
-		internalk8sClient.DeployConfigMapCR(configMaps, k8sClient)
+		configMap := configMaps
+		deployments = append(deployments, crDeployment{"ConfigMap", configMap, func() error {
+			return internalk8sClient.DeployConfigMapCR(configMap, k8sClient)
+		}})
 	}
 	for _, authPolicies := range k8sArtifact.Authentication {
 		authPolicies.Namespace = namespace
-		internalk8sClient.DeployAuthenticationCR(authPolicies, k8sClient)
+		authPolicy := authPolicies
+		deployments = append(deployments, crDeployment{"Authentication", authPolicy, func() error {
+			return internalk8sClient.DeployAuthenticationCR(authPolicy, k8sClient)
+		}})
 	}
 	for _, interceptorServices := range k8sArtifact.InterceptorServices {
 		interceptorServices.Namespace = namespace
-		internalk8sClient.DeployInterceptorServicesCR(interceptorServices, k8sClient)
+		interceptorService := interceptorServices
+		deployments = append(deployments, crDeployment{"InterceptorService", interceptorService, func() error {
+			return internalk8sClient.DeployInterceptorServicesCR(interceptorService, k8sClient)
+		}})
 	}
 	if k8sArtifact.BackendJWT != nil {
 		k8sArtifact.BackendJWT.Namespace = namespace
-		internalk8sClient.DeployBackendJWTCR(k8sArtifact.BackendJWT, k8sClient)
+		backendJWT := k8sArtifact.BackendJWT
+		deployments = append(deployments, crDeployment{"BackendJWT", backendJWT, func() error {
+			return internalk8sClient.DeployBackendJWTCR(backendJWT, k8sClient)
+		}})
 	}
 	for _, scopes := range k8sArtifact.Scopes {
 		scopes.Namespace = namespace
-		internalk8sClient.DeployScopeCR(scopes, k8sClient)
+		scope := scopes
+		deployments = append(deployments, crDeployment{"Scope", scope, func() error {
+			return internalk8sClient.DeployScopeCR(scope, k8sClient)
+		}})
 	}
-	for _, rateLimitPolicy := range k8sArtifact.RateLimitPolicies {
-		rateLimitPolicy.Namespace = namespace
-		internalk8sClient.DeployRateLimitPolicyCR(rateLimitPolicy, k8sClient)
+	for _, rateLimitPolicies := range k8sArtifact.RateLimitPolicies {
+		rateLimitPolicies.Namespace = namespace
+		rateLimitPolicy := rateLimitPolicies
+		deployments = append(deployments, crDeployment{"RateLimitPolicy", rateLimitPolicy, func() error {
+			return internalk8sClient.DeployRateLimitPolicyCR(rateLimitPolicy, k8sClient)
+		}})
 	}
-	for _, aiRateLimitPolicy := range k8sArtifact.AIRateLimitPolicies {
-		aiRateLimitPolicy.Namespace = namespace
-		internalk8sClient.DeployAIRateLimitPolicyCR(aiRateLimitPolicy, k8sClient)
+	for _, aiRateLimitPolicies := range k8sArtifact.AIRateLimitPolicies {
+		aiRateLimitPolicies.Namespace = namespace
+		aiRateLimitPolicy := aiRateLimitPolicies
+		deployments = append(deployments, crDeployment{"AIRateLimitPolicy", aiRateLimitPolicy, func() error {
+			return internalk8sClient.DeployAIRateLimitPolicyCR(aiRateLimitPolicy, k8sClient)
+		}})
 	}
 	for _, secrets := range k8sArtifact.Secrets {
 		secrets.Namespace = namespace
-		internalk8sClient.DeploySecretCR(secrets, k8sClient)
+		secret := secrets
+		deployments = append(deployments, crDeployment{"Secret", secret, func() error {
+			return internalk8sClient.DeploySecretCR(secret, k8sClient)
+		}})
 	}
 	for _, apiPolicies := range k8sArtifact.APIPolicies {
 		apiPolicies.Namespace = namespace
-		internalk8sClient.DeployAPIPolicyCR(apiPolicies, k8sClient)
+		apiPolicy := apiPolicies
+		deployments = append(deployments, crDeployment{"APIPolicy", apiPolicy, func() error {
+			return internalk8sClient.DeployAPIPolicyCR(apiPolicy, k8sClient)
+		}})
 	}
 	for _, httpRoutes := range k8sArtifact.HTTPRoutes {
 		httpRoutes.Namespace = namespace
-		internalk8sClient.DeployHTTPRouteCR(httpRoutes, k8sClient)
+		httpRoute := httpRoutes
+		deployments = append(deployments, crDeployment{"HTTPRoute", httpRoute, func() error {
+			return internalk8sClient.DeployHTTPRouteCR(httpRoute, k8sClient)
+		}})
 	}
 	for _, gqlRoutes := range k8sArtifact.GQLRoutes {
 		gqlRoutes.Namespace = namespace
-		internalk8sClient.DeployGQLRouteCR(gqlRoutes, k8sClient)
+		gqlRoute := gqlRoutes
+		deployments = append(deployments, crDeployment{"GQLRoute", gqlRoute, func() error {
+			return internalk8sClient.DeployGQLRouteCR(gqlRoute, k8sClient)
+		}})
 	}
 	for _, backends := range k8sArtifact.Backends {
 		backends.Namespace = namespace
-		internalk8sClient.DeployBackendCR(backends, k8sClient)
+		backend := backends
+		deployments = append(deployments, crDeployment{"Backend", backend, func() error {
+			return internalk8sClient.DeployBackendCR(backend, k8sClient)
+		}})
 	}
-	internalk8sClient.DeployAPICR(&k8sArtifact.API, k8sClient)
-	return nil
+	return deployments
 }
+
+// recordDeploymentEvent records a Kubernetes event on the API CR with the outcome of the deployment
+func recordDeploymentEvent(api *dpv1alpha3.API, result *DeploymentResult) {
+	revisionID := api.ObjectMeta.Labels["revisionID"]
+	if err := result.Err(); err != nil {
+		rollbackStatus := "the previous revision is restored"
+		if !result.RolledBack {
+			rollbackStatus = "no CRs were applied"
+		} else if result.RollbackError != nil {
+			rollbackStatus = fmt.Sprintf("the previous revision could not be restored: %v", result.RollbackError)
+		}
+		internalk8sClient.RecordAPIEvent(api, corev1.EventTypeWarning, constants.APIDeploymentFailedReason,
+			"Unable to apply the API revision %s and %s: %v", revisionID, rollbackStatus, err)
+		return
+	}
+	reason := constants.APIUpdatedReason
+	if result.Operation == controllerutil.OperationResultCreated {
+		reason = constants.APIDeployedReason
+	}
+	internalk8sClient.RecordAPIEvent(api, corev1.EventTypeNormal, reason, "API revision %s applied with %d CRs",
+		revisionID, len(result.Resources))
+}
+
 func getDeploymentNamespace(k8sArtifact transformer.K8sArtifacts) (string, error) {
 	conf, errReadConfig := config.ReadConfigs()
 	if errReadConfig != nil {
 		logger.LoggerMapper.Errorf("Error reading configs: %v", errReadConfig)
 		return "", errReadConfig
 	}
-	return conf.DataPlane.Namespace, nil
+	return conf.GetTenantNamespace(k8sArtifact.API.Spec.Organization), nil
 }
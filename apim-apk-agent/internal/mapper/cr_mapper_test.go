@@ -0,0 +1,151 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package mapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dpv1alpha2 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha2"
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/constants"
+	internalk8sClient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/transformer"
+	corev1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func newTestArtifact() transformer.K8sArtifacts {
+	return transformer.K8sArtifacts{
+		API: dpv1alpha3.API{
+			ObjectMeta: metav1.ObjectMeta{Name: "pizzashack", Labels: map[string]string{"revisionID": "1"}},
+		},
+		ConfigMaps: map[string]*corev1.ConfigMap{
+			"pizzashack-definition": {ObjectMeta: metav1.ObjectMeta{Name: "pizzashack-definition"}},
+		},
+		Backends: map[string]*dpv1alpha2.Backend{
+			"pizzashack-backend": {ObjectMeta: metav1.ObjectMeta{Name: "pizzashack-backend"}},
+		},
+	}
+}
+
+func newTestClientBuilder() *fake.ClientBuilder {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = dpv1alpha2.AddToScheme(scheme)
+	_ = dpv1alpha3.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme)
+}
+
+func TestMapAndCreateCR(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	internalk8sClient.SetEventRecorder(recorder)
+	defer internalk8sClient.SetEventRecorder(nil)
+	k8sClient := newTestClientBuilder().Build()
+
+	result := MapAndCreateCR(newTestArtifact(), k8sClient)
+	assert.NoError(t, result.Err())
+	assert.Equal(t, controllerutil.OperationResultCreated, result.Operation)
+	assert.Len(t, result.Resources, 3)
+	assert.True(t, strings.HasPrefix(<-recorder.Events, "Normal "+constants.APIDeployedReason))
+
+	api := &dpv1alpha3.API{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: result.Namespace, Name: "pizzashack"}, api))
+	var summary deploymentSummary
+	assert.NoError(t, json.Unmarshal([]byte(api.Annotations[constants.DeploymentSummaryAnnotation]), &summary))
+	assert.Equal(t, []string{"Backend/pizzashack-backend", "ConfigMap/pizzashack-definition"}, summary.Applied)
+	assert.Empty(t, summary.Failed)
+
+	result = MapAndCreateCR(newTestArtifact(), k8sClient)
+	assert.NoError(t, result.Err())
+	assert.Equal(t, controllerutil.OperationResultUpdated, result.Operation)
+	assert.True(t, strings.HasPrefix(<-recorder.Events, "Normal "+constants.APIUpdatedReason))
+}
+
+func TestMapAndCreateCRWithFailedResource(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	internalk8sClient.SetEventRecorder(recorder)
+	defer internalk8sClient.SetEventRecorder(nil)
+	k8sClient := newTestClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if _, isBackend := obj.(*dpv1alpha2.Backend); isBackend {
+				return errors.New("backend rejected")
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	result := MapAndCreateCR(newTestArtifact(), k8sClient)
+	failed := result.Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "Backend", failed[0].Kind)
+	assert.Equal(t, "pizzashack-backend", failed[0].Name)
+	assert.ErrorContains(t, result.Err(), "Backend/pizzashack-backend: backend rejected")
+	assert.True(t, result.RolledBack)
+	assert.NoError(t, result.RollbackError)
+	assert.True(t, strings.HasPrefix(<-recorder.Events, "Warning "+constants.APIDeploymentFailedReason))
+
+	// The CRs created within the failed deployment are removed
+	err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: result.Namespace, Name: "pizzashack-definition"}, &corev1.ConfigMap{})
+	assert.True(t, k8error.IsNotFound(err))
+	err = k8sClient.Get(context.Background(), client.ObjectKey{Namespace: result.Namespace, Name: "pizzashack"}, &dpv1alpha3.API{})
+	assert.True(t, k8error.IsNotFound(err))
+}
+
+func TestMapAndCreateCRRestoresPreviousRevision(t *testing.T) {
+	failUpdates := false
+	k8sClient := newTestClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if _, isBackend := obj.(*dpv1alpha2.Backend); isBackend && failUpdates {
+				return errors.New("backend rejected")
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+	previousRevision := newTestArtifact()
+	previousRevision.ConfigMaps["pizzashack-definition"].Data = map[string]string{"definition": "v1"}
+	assert.NoError(t, MapAndCreateCR(previousRevision, k8sClient).Err())
+
+	failUpdates = true
+	newRevision := newTestArtifact()
+	newRevision.API.Labels["revisionID"] = "2"
+	newRevision.ConfigMaps["pizzashack-definition"].Data = map[string]string{"definition": "v2"}
+	result := MapAndCreateCR(newRevision, k8sClient)
+	assert.Error(t, result.Err())
+	assert.True(t, result.RolledBack)
+	assert.NoError(t, result.RollbackError)
+
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: result.Namespace, Name: "pizzashack-definition"}, configMap))
+	assert.Equal(t, "v1", configMap.Data["definition"])
+	api := &dpv1alpha3.API{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: result.Namespace, Name: "pizzashack"}, api))
+	assert.Equal(t, "1", api.Labels["revisionID"])
+}
@@ -0,0 +1,136 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package mapper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/transformer"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8Yaml "sigs.k8s.io/yaml"
+)
+
+const redactedSecretValue = "<redacted>"
+
+// RenderCRs writes the CRs of an API as YAML files instead of creating them inside the cluster. The files are
+// grouped by the organization and the API as <outputDirectory>/<organization>/<apiName>-<apiVersion>/ and the
+// directory of the API is recreated on each call so that it always reflects the latest revision.
+// Values of the Secrets are redacted.
+func RenderCRs(k8sArtifact transformer.K8sArtifacts, outputDirectory string) error {
+	namespace, err := getDeploymentNamespace(k8sArtifact)
+	if err != nil {
+		return err
+	}
+	k8sArtifact.API.Namespace = namespace
+
+	apiDirectory := filepath.Join(outputDirectory, sanitizePathSegment(k8sArtifact.API.Spec.Organization),
+		sanitizePathSegment(k8sArtifact.API.Spec.APIName+"-"+k8sArtifact.API.Spec.APIVersion))
+	if err := os.RemoveAll(apiDirectory); err != nil {
+		logger.LoggerMapper.Errorf("Unable to clean the render directory %s: %v", apiDirectory, err)
+		return err
+	}
+	if err := os.MkdirAll(apiDirectory, 0750); err != nil {
+		logger.LoggerMapper.Errorf("Unable to create the render directory %s: %v", apiDirectory, err)
+		return err
+	}
+
+	objects := make(map[string]client.Object)
+	addObject := func(kind string, object client.Object) {
+		object.SetNamespace(namespace)
+		objects[strings.ToLower(kind)+"-"+object.GetName()] = object
+	}
+	for _, configMap := range k8sArtifact.ConfigMaps {
+		addObject("ConfigMap", configMap)
+	}
+	for _, authentication := range k8sArtifact.Authentication {
+		addObject("Authentication", authentication)
+	}
+	for _, interceptorService := range k8sArtifact.InterceptorServices {
+		addObject("InterceptorService", interceptorService)
+	}
+	if k8sArtifact.BackendJWT != nil {
+		addObject("BackendJWT", k8sArtifact.BackendJWT)
+	}
+	for _, scope := range k8sArtifact.Scopes {
+		addObject("Scope", scope)
+	}
+	for _, rateLimitPolicy := range k8sArtifact.RateLimitPolicies {
+		addObject("RateLimitPolicy", rateLimitPolicy)
+	}
+	for _, aiRateLimitPolicy := range k8sArtifact.AIRateLimitPolicies {
+		addObject("AIRateLimitPolicy", aiRateLimitPolicy)
+	}
+	for _, secret := range k8sArtifact.Secrets {
+		addObject("Secret", redactSecret(secret))
+	}
+	for _, apiPolicy := range k8sArtifact.APIPolicies {
+		addObject("APIPolicy", apiPolicy)
+	}
+	for _, httpRoute := range k8sArtifact.HTTPRoutes {
+		addObject("HTTPRoute", httpRoute)
+	}
+	for _, gqlRoute := range k8sArtifact.GQLRoutes {
+		addObject("GQLRoute", gqlRoute)
+	}
+	for _, backend := range k8sArtifact.Backends {
+		addObject("Backend", backend)
+	}
+	addObject("API", &k8sArtifact.API)
+
+	for fileName, object := range objects {
+		content, err := k8Yaml.Marshal(object)
+		if err != nil {
+			logger.LoggerMapper.Errorf("Error while marshalling %s: %v", fileName, err)
+			return err
+		}
+		filePath := filepath.Join(apiDirectory, sanitizePathSegment(fileName)+".yaml")
+		if err := os.WriteFile(filePath, content, 0600); err != nil {
+			logger.LoggerMapper.Errorf("Error while writing %s: %v", filePath, err)
+			return err
+		}
+	}
+	logger.LoggerMapper.Infof("%d CRs of the API %s:%s rendered to %s", len(objects), k8sArtifact.API.Spec.APIName,
+		k8sArtifact.API.Spec.APIVersion, apiDirectory)
+	return nil
+}
+
+// redactSecret returns a copy of the secret with its values redacted
+func redactSecret(secret *corev1.Secret) *corev1.Secret {
+	redacted := secret.DeepCopy()
+	for key := range redacted.Data {
+		redacted.Data[key] = []byte(redactedSecretValue)
+	}
+	for key := range redacted.StringData {
+		redacted.StringData[key] = redactedSecretValue
+	}
+	return redacted
+}
+
+// sanitizePathSegment makes the given value safe to be used as a single path segment
+func sanitizePathSegment(value string) string {
+	value = strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(value)
+	if value == "" || value == "." {
+		return fmt.Sprintf("_%s", value)
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package mapper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/transformer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8Yaml "sigs.k8s.io/yaml"
+)
+
+func TestRenderCRs(t *testing.T) {
+	outputDirectory := t.TempDir()
+	k8sArtifact := transformer.K8sArtifacts{
+		API: dpv1alpha3.API{
+			ObjectMeta: metav1.ObjectMeta{Name: "pizzashack"},
+			Spec: dpv1alpha3.APISpec{
+				APIName:      "PizzaShackAPI",
+				APIVersion:   "1.0.0",
+				Organization: "carbon.super",
+			},
+		},
+		Secrets: map[string]*corev1.Secret{
+			"pizzashack-secret": {
+				ObjectMeta: metav1.ObjectMeta{Name: "pizzashack-secret"},
+				Data:       map[string][]byte{"password": []byte("admin")},
+			},
+		},
+	}
+	staleFile := filepath.Join(outputDirectory, "carbon.super", "PizzaShackAPI-1.0.0", "stale.yaml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(staleFile), 0750))
+	assert.NoError(t, os.WriteFile(staleFile, []byte{}, 0600))
+
+	assert.NoError(t, RenderCRs(k8sArtifact, outputDirectory))
+
+	apiDirectory := filepath.Join(outputDirectory, "carbon.super", "PizzaShackAPI-1.0.0")
+	files, err := os.ReadDir(apiDirectory)
+	assert.NoError(t, err)
+	fileNames := make([]string, 0, len(files))
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name())
+	}
+	assert.ElementsMatch(t, []string{"api-pizzashack.yaml", "secret-pizzashack-secret.yaml"}, fileNames)
+
+	var secret corev1.Secret
+	content, err := os.ReadFile(filepath.Join(apiDirectory, "secret-pizzashack-secret.yaml"))
+	assert.NoError(t, err)
+	assert.NoError(t, k8Yaml.Unmarshal(content, &secret))
+	assert.Equal(t, redactedSecretValue, string(secret.Data["password"]))
+	assert.Equal(t, "admin", string(k8sArtifact.Secrets["pizzashack-secret"].Data["password"]))
+}
+
+func TestSanitizePathSegment(t *testing.T) {
+	assert.Equal(t, "___etc", sanitizePathSegment("/../etc"))
+	assert.Equal(t, "_", sanitizePathSegment(""))
+	assert.Equal(t, "carbon.super", sanitizePathSegment("carbon.super"))
+}
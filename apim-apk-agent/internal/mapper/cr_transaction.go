@@ -0,0 +1,90 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package mapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// crDeployment is a CR applied as a part of an API deployment
+type crDeployment struct {
+	kind   string
+	object client.Object
+	deploy func() error
+}
+
+// takeSnapshots returns the existing state of the given CRs in the cluster. The snapshot of a CR is nil when it
+// does not exist in the cluster.
+func takeSnapshots(deployments []crDeployment, k8sClient client.Client) ([]client.Object, error) {
+	snapshots := make([]client.Object, len(deployments))
+	for i, deployment := range deployments {
+		existing := deployment.object.DeepCopyObject().(client.Object)
+		if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(deployment.object), existing); err != nil {
+			if k8error.IsNotFound(err) {
+				continue
+			}
+			logger.LoggerMapper.Errorf("Unable to snapshot %s %s: %v", deployment.kind, deployment.object.GetName(), err)
+			return nil, fmt.Errorf("unable to snapshot %s %s: %w", deployment.kind, deployment.object.GetName(), err)
+		}
+		snapshots[i] = existing
+	}
+	return snapshots, nil
+}
+
+// rollback restores the given CRs to their snapshots in the reverse order. The CRs which did not exist before
+// are deleted.
+func rollback(deployments []crDeployment, snapshots []client.Object, k8sClient client.Client) error {
+	var rollbackErrors []error
+	for i := len(deployments) - 1; i >= 0; i-- {
+		deployment := deployments[i]
+		var err error
+		if snapshots[i] == nil {
+			err = client.IgnoreNotFound(k8sClient.Delete(context.Background(), deployment.object))
+		} else {
+			err = restore(snapshots[i], k8sClient)
+		}
+		if err != nil {
+			logger.LoggerMapper.Errorf("Unable to restore %s %s: %v", deployment.kind, deployment.object.GetName(), err)
+			rollbackErrors = append(rollbackErrors, fmt.Errorf("%s/%s: %w", deployment.kind, deployment.object.GetName(), err))
+			continue
+		}
+		logger.LoggerMapper.Debugf("%s %s restored", deployment.kind, deployment.object.GetName())
+	}
+	return errors.Join(rollbackErrors...)
+}
+
+// restore writes the snapshot of a CR back to the cluster
+func restore(snapshot client.Object, k8sClient client.Client) error {
+	current := snapshot.DeepCopyObject().(client.Object)
+	if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snapshot), current); err != nil {
+		if !k8error.IsNotFound(err) {
+			return err
+		}
+		snapshot.SetResourceVersion("")
+		snapshot.SetUID("")
+		return k8sClient.Create(context.Background(), snapshot)
+	}
+	snapshot.SetResourceVersion(current.GetResourceVersion())
+	return k8sClient.Update(context.Background(), snapshot)
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/eventhub"
 	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
 	eventhubTypes "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
@@ -54,9 +55,22 @@ func handleKMConfiguration(c client.Client) {
 				Severity:  logging.CRITICAL,
 				ErrorCode: 2000,
 			})
-			return
+			msg.Nack(d, true)
+			continue
 		}
 		logger.LoggerMessaging.Infof("Event %s is received", notification.Event.PayloadData.EventType)
+		if !belongsToTenant(notification.Event.PayloadData.TenantDomain) {
+			logger.LoggerMessaging.Debugf("Key manager event for %s is dropped due to having non related tenantDomain : %s",
+				notification.Event.PayloadData.Name, notification.Event.PayloadData.TenantDomain)
+			msg.Ack(d)
+			continue
+		}
+		if !leaderelection.IsLeader() {
+			logger.LoggerMessaging.Debugf("Key manager event for %s is applied by the leader",
+				notification.Event.PayloadData.Name)
+			msg.Ack(d)
+			continue
+		}
 
 		var decodedByte, err = base64.StdEncoding.DecodeString(notification.Event.PayloadData.Value)
 
@@ -73,12 +87,13 @@ func handleKMConfiguration(c client.Client) {
 				Severity:  logging.CRITICAL,
 				ErrorCode: 2002,
 			})
-			return
+			msg.Nack(d, true)
+			continue
 		}
 
 		if strings.EqualFold(keyManagerConfigEvent, notification.Event.PayloadData.EventType) {
 			if strings.EqualFold(actionDelete, notification.Event.PayloadData.Action) {
-				k8sclient.DeleteTokenIssuersCR(c, notification.Event.PayloadData.Name, notification.Event.PayloadData.TenantDomain)
+				err = k8sclient.DeleteTokenIssuersCR(c, notification.Event.PayloadData.Name, notification.Event.PayloadData.TenantDomain)
 			} else if decodedByte != nil {
 				logger.LoggerMessaging.Infof("decoded stream %s", string(decodedByte))
 				kmConfigMapErr := json.Unmarshal([]byte(string(decodedByte)), &keyManager)
@@ -88,7 +103,8 @@ func handleKMConfiguration(c client.Client) {
 						Severity:  logging.CRITICAL,
 						ErrorCode: 2003,
 					})
-					return
+					msg.Nack(d, true)
+					continue
 				}
 				if strings.EqualFold(actionAdd, notification.Event.PayloadData.Action) ||
 					strings.EqualFold(actionUpdate, notification.Event.PayloadData.Action) {
@@ -96,17 +112,23 @@ func handleKMConfiguration(c client.Client) {
 					resolvedKeyManager := eventhub.MarshalKeyManager(&keyManager)
 					logger.LoggerMessaging.Infof("Resolved Key Managers received: %v", resolvedKeyManager)
 					if strings.EqualFold(actionAdd, notification.Event.PayloadData.Action) {
-						k8sclient.CreateAndUpdateTokenIssuersCR(resolvedKeyManager, c)
+						err = k8sclient.CreateAndUpdateTokenIssuersCR(resolvedKeyManager, c)
 					} else {
 						err = k8sclient.UpdateTokenIssuersCR(resolvedKeyManager, c)
 						if err != nil {
-							k8sclient.CreateAndUpdateTokenIssuersCR(resolvedKeyManager, c)
+							err = k8sclient.CreateAndUpdateTokenIssuersCR(resolvedKeyManager, c)
 						}
 					}
 				}
 			}
 		}
-		logger.LoggerMessaging.Info("handle: deliveries channel closed")
-		d.Ack(false)
+		if err != nil {
+			logger.LoggerMessaging.Errorf("Error while applying the key manager event %s: %v",
+				notification.Event.PayloadData.Name, err)
+			msg.Nack(d, false)
+			continue
+		}
+		msg.Ack(d)
 	}
+	logger.LoggerMessaging.Info("handle: deliveries channel closed")
 }
@@ -19,6 +19,8 @@
 package messaging
 
 import (
+	"time"
+
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
 	msg "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/messaging"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -26,8 +28,31 @@ import (
 
 // ProcessEvents to pass event consumption
 func ProcessEvents(config *config.Config, c client.Client) {
-	msg.InitiateJMSConnection(config.ControlPlane.BrokerConnectionParameters.EventListeningEndpoints)
+	msg.InitiateJMSConnection(config.ControlPlane.BrokerConnectionParameters.EventListeningEndpoints,
+		getDurableQueueOptions(config))
 
 	go handleNotification(c)
 	go handleKMConfiguration(c)
+	go handleTokenRevocation()
+	go handleThrottleData(c)
+}
+
+// ReconnectEvents reconnects the event consumers with the changed broker connection parameters
+func ReconnectEvents(config *config.Config) {
+	msg.Reconnect(config.ControlPlane.BrokerConnectionParameters.EventListeningEndpoints, getDurableQueueOptions(config))
+}
+
+// ValidateDurableQueue returns an error when the durable queue configurations cannot be used to consume the events
+func ValidateDurableQueue(config *config.Config) error {
+	return msg.ValidateDurableQueueOptions(getDurableQueueOptions(config))
+}
+
+func getDurableQueueOptions(config *config.Config) msg.DurableQueueOptions {
+	durableQueue := config.ControlPlane.BrokerConnectionParameters.DurableQueue
+	return msg.DurableQueueOptions{
+		Enabled:            durableQueue.Enabled,
+		AgentIdentity:      durableQueue.AgentIdentity,
+		DeadLetterExchange: durableQueue.DeadLetterExchange,
+		Expiry:             durableQueue.Expiry * time.Second,
+	}
 }
@@ -21,15 +21,19 @@ package messaging
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/wso2/apk/common-go-libs/constants"
 	event "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/eventhub"
 	internalk8sClient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
 	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/synchronizer"
 	internalutils "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/utils"
@@ -78,6 +82,8 @@ var (
 	apiListTimeStampMap          = make(map[string]int64, 0)
 	subsriptionsListTimeStampMap = make(map[string]int64, 0)
 	applicationListTimeStampMap  = make(map[string]int64, 0)
+	// errUndecodableEvent is returned for the events which can never be processed as they cannot be decoded
+	errUndecodableEvent = errors.New("event cannot be decoded")
 )
 
 // handleNotification to process
@@ -87,15 +93,20 @@ func handleNotification(c client.Client) {
 		var notification msg.EventNotification
 		notificationErr := parseNotificationJSONEvent([]byte(string(d.Body)), &notification)
 		if notificationErr != nil {
+			msg.Nack(d, true)
 			continue
 		}
 		logger.LoggerMessaging.Infof("Event %s is received", notification.Event.PayloadData.EventType)
 		logger.LoggerMessaging.Infof("Event %s is received with payload %s", notification.Event.PayloadData.EventType, notification.Event.PayloadData.Event)
 		err := processNotificationEvent(conf, &notification, c)
 		if err != nil {
+			logger.LoggerMessaging.Errorf("Error while processing the event %s: %v",
+				notification.Event.PayloadData.EventType, err)
+			// The events failed to apply are requeued while the events which cannot be decoded are dead lettered
+			msg.Nack(d, errors.Is(err, errUndecodableEvent))
 			continue
 		}
-		d.Ack(false)
+		msg.Ack(d)
 	}
 	logger.LoggerMessaging.Infof("handle: deliveries channel closed")
 }
@@ -109,33 +120,34 @@ func processNotificationEvent(conf *config.Config, notification *msg.EventNotifi
 		}
 		logger.LoggerMessaging.Errorf("Error occurred while decoding the notification event %v. "+
 			"Hence dropping the event", err)
-		return err
+		return fmt.Errorf("%w: %v", errUndecodableEvent, err)
 	}
 	AgentMode := conf.Agent.Mode
 	eventType = notification.Event.PayloadData.EventType
 	if strings.Contains(eventType, apiLifeCycleChange) {
 		if AgentMode == "CPtoDP" {
-			handleLifeCycleEvents(decodedByte)
+			return handleLifeCycleEvents(decodedByte, conf, c)
 		}
 	} else if strings.Contains(eventType, apiEventType) {
 		if AgentMode == "CPtoDP" {
-			handleAPIEvents(decodedByte, eventType, conf, c)
+			return handleAPIEvents(decodedByte, eventType, conf, c)
 		}
 	} else if strings.Contains(eventType, applicationEventType) {
-		handleApplicationEvents(decodedByte, eventType)
+		return handleApplicationEvents(decodedByte, eventType)
 	} else if strings.Contains(eventType, subscriptionEventType) {
-		handleSubscriptionEvents(decodedByte, eventType)
+		return handleSubscriptionEvents(decodedByte, eventType)
 	} else if strings.Contains(eventType, policyEventType) {
 		var policyEvent msg.PolicyInfo
 		policyEventErr := json.Unmarshal([]byte(string(decodedByte)), &policyEvent)
 		if policyEventErr != nil {
 			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Throttling Policy event data %v", policyEventErr)
+			return fmt.Errorf("%w: %v", errUndecodableEvent, policyEventErr)
 		}
 		if AgentMode == "CPtoDP" || strings.EqualFold(policyEvent.PolicyType, "SUBSCRIPTION") {
 			handlePolicyEvents(decodedByte, eventType, c)
 		}
 	} else if strings.Contains(eventType, aiProviderEventType) {
-		handleAIProviderEvents(decodedByte, eventType, c)
+		return handleAIProviderEvents(decodedByte, eventType, c)
 	}
 	// other events will ignore including HEALTH_CHECK event
 	return nil
@@ -147,24 +159,37 @@ func processNotificationEvent(conf *config.Config, notification *msg.EventNotifi
 // for it to get updated. However we need to redeploy the API when there is a default
 // version change. For that we call `/apis` endpoint to get updated API metadata (this
 // contains the updated `isDefaultVersion` field). Now we proceed with fetching runtime
-// artifact from the CP. When creating CC deployment objects we refer to updated `APIList`
-// map and update runtime artifact's `isDefaultVersion` field to correctly deploy default
-// versioned API.
-func handleDefaultVersionUpdate(event msg.APIEvent) {
-	// deployedEnvs := xds.GetDeployedEnvironments(event.UUID)
-	// for _, env := range deployedEnvs {
-	// 	query := make(map[string]string, 3)
-	// 	query[eh.GatewayLabelParam] = env
-	// 	query[eh.ContextParam] = event.APIContext
-	// 	query[eh.VersionParam] = event.APIVersion
-	// 	eh.UpdateAPIMetadataFromCP(query)
-	// }
-
-	// synchronizer.FetchAPIsFromControlPlane(event.UUID, deployedEnvs)
+// artifact from the CP. When creating the CRs we refer to the updated API metadata
+// and update runtime artifact's `isDefaultVersion` field to correctly deploy default
+// versioned API. All the versions of the API are redeployed as the previous default
+// version loses the unversioned route.
+func handleDefaultVersionUpdate(event msg.APIEvent, conf *config.Config, c client.Client) error {
+	if err := eventhub.LoadAPIMetadata(""); err != nil {
+		logger.LoggerMessaging.Errorf("Error while fetching the API metadata for the default version update of the API %s: %v",
+			event.UUID, err)
+		return err
+	}
+	apiUUIDs := internalutils.GetAPIVersionUUIDs(event.UUID)
+	isDefaultVersion := func(apiUUID string) bool {
+		api, _ := internalutils.GetAPIMetadata(apiUUID)
+		return api.IsDefaultVersion
+	}
+	// The versions which are no longer the default version are redeployed first to release the unversioned route
+	sort.SliceStable(apiUUIDs, func(i, j int) bool {
+		return !isDefaultVersion(apiUUIDs[i]) && isDefaultVersion(apiUUIDs[j])
+	})
+	for _, apiUUID := range apiUUIDs {
+		apiUUID := apiUUID
+		logger.LoggerMessaging.Infof("Redeploying the API %s due to the default version update", apiUUID)
+		if _, err := internalutils.FetchAPIsOnEvent(conf, &apiUUID, c); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // handleAPIEvents to process api related data
-func handleAPIEvents(data []byte, eventType string, conf *config.Config, c client.Client) {
+func handleAPIEvents(data []byte, eventType string, conf *config.Config, c client.Client) error {
 	var (
 		apiEvent         msg.APIEvent
 		currentTimeStamp int64 = apiEvent.Event.TimeStamp
@@ -177,7 +202,7 @@ func handleAPIEvents(data []byte, eventType string, conf *config.Config, c clien
 			Severity:  logging.MAJOR,
 			ErrorCode: 2004,
 		})
-		return
+		return fmt.Errorf("%w: %v", errUndecodableEvent, apiEventErr)
 	}
 
 	if !belongsToTenant(apiEvent.TenantDomain) {
@@ -191,7 +216,7 @@ func handleAPIEvents(data []byte, eventType string, conf *config.Config, c clien
 		}
 		logger.LoggerMessaging.Debugf("API event for the API %s:%s is dropped due to having non related tenantDomain : %s",
 			apiName, apiVersion, apiEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	apiEventObj := types.API{UUID: apiEvent.UUID, APIID: apiEvent.APIID, Name: apiEvent.APIName,
@@ -199,9 +224,20 @@ func handleAPIEvents(data []byte, eventType string, conf *config.Config, c clien
 
 	logger.LoggerMessaging.Infof("API event data %v", apiEventObj)
 
-	//Per each revision, synchronization should happen.
+	if !leaderelection.IsLeader() {
+		logger.LoggerMessaging.Debugf("API event of the API %s is applied by the leader", apiEvent.UUID)
+		return nil
+	}
+
+	if isDefaultVersionUpdate(apiEvent) {
+		return handleDefaultVersionUpdate(apiEvent, conf, c)
+	}
+
+	//Per each revision, synchronization should happen. The event is acknowledged only after the revision is applied.
 	if strings.EqualFold(deployAPIToGateway, apiEvent.Event.Type) {
-		go internalutils.FetchAPIsOnEvent(conf, &apiEvent.UUID, c)
+		if _, err := internalutils.FetchAPIsOnEvent(conf, &apiEvent.UUID, c); err != nil {
+			return err
+		}
 	}
 
 	for _, env := range apiEvent.GatewayLabels {
@@ -211,12 +247,11 @@ func handleAPIEvents(data []byte, eventType string, conf *config.Config, c clien
 		// removeFromGateway event with multiple labels could only appear when the API is subjected
 		// to delete. Hence we could simply delete after checking against just one iteration.
 		if strings.EqualFold(removeAPIFromGateway, apiEvent.Event.Type) {
-			internalk8sClient.UndeployAPICR(apiEvent.UUID, c)
-			break
+			return internalk8sClient.UndeployAPICR(apiEvent.UUID, c)
 		}
 		if strings.EqualFold(deployAPIToGateway, apiEvent.Event.Type) {
 			conf, _ := config.ReadConfigs()
-			configuredEnvs := conf.ControlPlane.EnvironmentLabels
+			configuredEnvs := conf.GetTenantEnvironmentLabels(apiEvent.TenantDomain)
 			if len(configuredEnvs) == 0 {
 				configuredEnvs = append(configuredEnvs, config.DefaultGatewayName)
 			}
@@ -237,19 +272,20 @@ func handleAPIEvents(data []byte, eventType string, conf *config.Config, c clien
 			// 	}
 		}
 	}
+	return nil
 }
 
-func handleLifeCycleEvents(data []byte) {
+func handleLifeCycleEvents(data []byte, conf *config.Config, c client.Client) error {
 	var apiEvent msg.APIEvent
 	apiLCEventErr := json.Unmarshal([]byte(string(data)), &apiEvent)
 	if apiLCEventErr != nil {
 		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Lifecycle event data %v", apiLCEventErr)
-		return
+		return fmt.Errorf("%w: %v", errUndecodableEvent, apiLCEventErr)
 	}
 	if !belongsToTenant(apiEvent.TenantDomain) {
 		logger.LoggerMessaging.Debugf("API Lifecycle event for the API %s:%s is dropped due to having non related tenantDomain : %s",
 			apiEvent.APIName, apiEvent.APIVersion, apiEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	apiEventObj := types.API{UUID: apiEvent.UUID, APIID: apiEvent.APIID, Name: apiEvent.APIName,
@@ -257,35 +293,40 @@ func handleLifeCycleEvents(data []byte) {
 
 	logger.LoggerMessaging.Infof("API event data %v", apiEventObj)
 
-	conf, _ := config.ReadConfigs()
-	configuredEnvs := conf.ControlPlane.EnvironmentLabels
 	logger.LoggerMessaging.Debugf("%s : %s API life cycle state change event triggered", apiEvent.APIName, apiEvent.APIVersion)
-	if len(configuredEnvs) == 0 {
-		configuredEnvs = append(configuredEnvs, config.DefaultGatewayName)
-	}
-	// for _, configuredEnv := range configuredEnvs {
-	// 	xdsAPIList := xds.MarshalAPIForLifeCycleChangeEventAndReturnList(apiEvent.UUID, apiEvent.APIStatus, configuredEnv)
-	// 	if xdsAPIList != nil {
-	// 		xds.UpdateEnforcerAPIList(configuredEnv, xdsAPIList)
-	// 	}
-	// }
+	previousStatus := internalutils.SetAPILifeCycleStatus(apiEvent.UUID, apiEvent.APIStatus)
+	if !leaderelection.IsLeader() {
+		logger.LoggerMessaging.Debugf("API life cycle event of the API %s is applied by the leader", apiEvent.UUID)
+		return nil
+	}
+	if internalutils.IsUndeployedLifeCycleStatus(apiEvent.APIStatus) {
+		logger.LoggerMessaging.Infof("API %s:%s is %s. Hence removing it from the data plane", apiEvent.APIName,
+			apiEvent.APIVersion, apiEvent.APIStatus)
+		return internalk8sClient.UndeployAPICR(apiEvent.UUID, c)
+	} else if internalutils.IsUndeployedLifeCycleStatus(previousStatus) {
+		logger.LoggerMessaging.Infof("API %s:%s is changed from %s to %s. Hence redeploying it", apiEvent.APIName,
+			apiEvent.APIVersion, previousStatus, apiEvent.APIStatus)
+		_, err := internalutils.FetchAPIsOnEvent(conf, &apiEvent.UUID, c)
+		return err
+	}
+	return nil
 }
 
 // handleApplicationEvents to process application related events
-func handleApplicationEvents(data []byte, eventType string) {
+func handleApplicationEvents(data []byte, eventType string) error {
 	if strings.EqualFold(applicationRegistration, eventType) ||
 		strings.EqualFold(removeApplicationKeyMapping, eventType) {
 		var applicationRegistrationEvent msg.ApplicationRegistrationEvent
 		appRegEventErr := json.Unmarshal([]byte(string(data)), &applicationRegistrationEvent)
 		if appRegEventErr != nil {
 			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Application Registration event data %v", appRegEventErr)
-			return
+			return fmt.Errorf("%w: %v", errUndecodableEvent, appRegEventErr)
 		}
 
 		if !belongsToTenant(applicationRegistrationEvent.TenantDomain) {
 			logger.LoggerMessaging.Debugf("Application Registration event for the Consumer Key : %s is dropped due to having non related tenantDomain : %s",
 				applicationRegistrationEvent.ConsumerKey, applicationRegistrationEvent.TenantDomain)
-			return
+			return nil
 		}
 		applicationKeyMappingEvent := event.ApplicationKeyMapping{ApplicationUUID: applicationRegistrationEvent.ApplicationUUID,
 			SecurityScheme:        "OAuth2",
@@ -318,19 +359,19 @@ func handleApplicationEvents(data []byte, eventType string) {
 		appEventErr := json.Unmarshal([]byte(string(data)), &applicationEvent)
 		if appEventErr != nil {
 			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Application event data %v", appEventErr)
-			return
+			return fmt.Errorf("%w: %v", errUndecodableEvent, appEventErr)
 		}
 
 		if !belongsToTenant(applicationEvent.TenantDomain) {
 			logger.LoggerMessaging.Debugf("Application event for the Application : %s (with uuid %s) is dropped due to having non related tenantDomain : %s",
 				applicationEvent.ApplicationName, applicationEvent.UUID, applicationEvent.TenantDomain)
-			return
+			return nil
 		}
 
 		logger.LoggerMessaging.Infof("Application event data %v", applicationEvent)
 
 		if isLaterEvent(applicationListTimeStampMap, fmt.Sprint(applicationEvent.ApplicationID), applicationEvent.TimeStamp) {
-			return
+			return nil
 		}
 
 		applicationGrpcEvent := event.Application{Uuid: applicationEvent.UUID,
@@ -354,9 +395,9 @@ func handleApplicationEvents(data []byte, eventType string) {
 		} else {
 			logger.LoggerMessaging.Warnf("Application Event Type is not recognized for the Event under "+
 				"Application UUID %s", applicationEvent.UUID)
-			return
 		}
 	}
+	return nil
 }
 func marshalAppAttributes(attributes interface{}) map[string]string {
 	attributesMap := make(map[string]string)
@@ -369,21 +410,21 @@ func marshalAppAttributes(attributes interface{}) map[string]string {
 }
 
 // handleSubscriptionRelatedEvents to process subscription related events
-func handleSubscriptionEvents(data []byte, eventType string) {
+func handleSubscriptionEvents(data []byte, eventType string) error {
 	var subscriptionEvent msg.SubscriptionEvent
 	subEventErr := json.Unmarshal([]byte(string(data)), &subscriptionEvent)
 	if subEventErr != nil {
 		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Subscription event data %v", subEventErr)
-		return
+		return fmt.Errorf("%w: %v", errUndecodableEvent, subEventErr)
 	}
 	if !belongsToTenant(subscriptionEvent.TenantDomain) {
 		logger.LoggerMessaging.Debugf("Subscription event for the Application : %s and API %s is dropped due to having non related tenantDomain : %s",
 			subscriptionEvent.ApplicationUUID, subscriptionEvent.APIUUID, subscriptionEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	if isLaterEvent(subsriptionsListTimeStampMap, fmt.Sprint(subscriptionEvent.SubscriptionID), subscriptionEvent.TimeStamp) {
-		return
+		return nil
 	}
 
 	subscription := event.Subscription{Uuid: subscriptionEvent.SubscriptionUUID,
@@ -416,15 +457,21 @@ func handleSubscriptionEvents(data []byte, eventType string) {
 		managementserver.DeleteApplicationMapping(applicationMappingEvent.Uuid)
 		go utils.SendEvent(&applicationMappingEvent)
 	}
+	return nil
 }
 
 // handleAIProviderEvents to process AI Provider related events
-func handleAIProviderEvents(data []byte, eventType string, c client.Client) {
+func handleAIProviderEvents(data []byte, eventType string, c client.Client) error {
 	var aiProviderEvent msg.AIProviderEvent
 	aiProviderEventErr := json.Unmarshal([]byte(string(data)), &aiProviderEvent)
 	if aiProviderEventErr != nil {
 		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling AI Provider event data %v", aiProviderEventErr)
-		return
+		return fmt.Errorf("%w: %v", errUndecodableEvent, aiProviderEventErr)
+	}
+	if !belongsToTenant(aiProviderEvent.Event.TenantDomain) {
+		logger.LoggerMessaging.Debugf("AI Provider event for %s is dropped due to having non related tenantDomain : %s",
+			aiProviderEvent.Name, aiProviderEvent.Event.TenantDomain)
+		return nil
 	}
 
 	if strings.EqualFold(aiProviderCreate, eventType) {
@@ -440,11 +487,13 @@ func handleAIProviderEvents(data []byte, eventType string, c client.Client) {
 	} else if strings.EqualFold(aiProviderDelete, eventType) {
 		logger.LoggerMessaging.Infof("Deletion for AI Provider: %s for tenant: %s", aiProviderEvent.Name, aiProviderEvent.Event.TenantDomain)
 		aiProvider := managementserver.GetAIProvider(aiProviderEvent.ID)
-		k8sclient.DeleteAIProviderCR(aiProvider.ID, c)
+		conf, _ := config.ReadConfigs()
+		k8sclient.DeleteAIProviderCR(aiProvider.ID, conf.GetTenantNamespace(aiProviderEvent.Event.TenantDomain), c)
 		managementserver.DeleteAIProvider(aiProviderEvent.ID)
 		aiProviders := managementserver.GetAllAIProviders()
 		logger.LoggerMessaging.Debugf("AI Providers Internal Map: %v", aiProviders)
 	}
+	return nil
 }
 
 // handlePolicyRelatedEvents to process policy related events
@@ -455,6 +504,11 @@ func handlePolicyEvents(data []byte, eventType string, c client.Client) {
 		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Throttling Policy event data %v", policyEventErr)
 		return
 	}
+	if !belongsToTenant(policyEvent.TenantDomain) {
+		logger.LoggerMessaging.Debugf("Policy event for %s is dropped due to having non related tenantDomain : %s",
+			policyEvent.PolicyName, policyEvent.TenantDomain)
+		return
+	}
 	// TODO: Handle policy events
 	if strings.EqualFold(eventType, policyCreate) {
 		if strings.EqualFold(policyEvent.PolicyType, "API") {
@@ -490,8 +544,10 @@ func handlePolicyEvents(data []byte, eventType string, c client.Client) {
 			logger.LoggerMessaging.Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
 			managementserver.DeleteSubscriptionPolicy(policyEvent.PolicyName, policyEvent.TenantDomain)
 			crName := k8sclient.PrepareSubscritionPolicyCRName(policyEvent.PolicyName, policyEvent.TenantDomain)
-			k8sclient.UnDeploySubscriptionRateLimitPolicyCR(crName, c)
-			k8sclient.UndeploySubscriptionAIRateLimitPolicyCR(crName, c)
+			conf, _ := config.ReadConfigs()
+			namespace := conf.GetTenantNamespace(policyEvent.TenantDomain)
+			k8sclient.UnDeploySubscriptionRateLimitPolicyCR(crName, namespace, c)
+			k8sclient.UndeploySubscriptionAIRateLimitPolicyCR(crName, namespace, c)
 			ratelimitPolicies := managementserver.GetAllRateLimitPolicies()
 			logger.LoggerMessaging.Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
 		}
@@ -563,10 +619,11 @@ func isDefaultVersionUpdate(event msg.APIEvent) bool {
 	return strings.EqualFold(apiUpdate, event.Event.Type) && strings.EqualFold("DEFAULT_VERSION", event.Action)
 }
 
+// belongsToTenant returns whether the events of the given tenant domain are served by the agent. Events of all
+// the tenants are served when no tenants are configured.
 func belongsToTenant(tenantDomain string) bool {
-	// TODO : enable this once the events are fixed in apim
-	// return config.GetControlPlaneConnectedTenantDomain() == tenantDomain
-	return true
+	conf, _ := config.ReadConfigs()
+	return conf.IsTenantServed(tenantDomain)
 }
 
 func parseNotificationJSONEvent(data []byte, notification *msg.EventNotification) error {
@@ -0,0 +1,103 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package messaging holds the implementation for event listeners functions
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	eventhubTypes "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	msg "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/messaging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ipConditionValue holds the condition value of IP and IPRANGE blocking conditions
+type ipConditionValue struct {
+	FixedIP    string `json:"fixedIp"`
+	StartingIP string `json:"startingIp"`
+	EndingIP   string `json:"endingIp"`
+	Invert     bool   `json:"invert"`
+}
+
+// handleThrottleData consumes the throttle data events and keeps the blocking conditions in sync with the data plane
+func handleThrottleData(c client.Client) {
+	for d := range msg.ThrottleDataChannel {
+		var notification msg.EventThrottleData
+		unmarshalErr := json.Unmarshal(d.Body, &notification)
+		if unmarshalErr != nil {
+			logger.LoggerMessaging.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Error occurred while unmarshalling throttle data event %v", unmarshalErr.Error()),
+				Severity:  logging.MAJOR,
+				ErrorCode: 2006,
+			})
+			d.Ack(false)
+			continue
+		}
+		if processThrottleDataEvent(&notification) {
+			k8sclient.DeployBlockingConditionsCR(managementserver.GetAllBlockingConditions(), c)
+		}
+		d.Ack(false)
+	}
+	logger.LoggerMessaging.Info("handle: throttle data deliveries channel closed")
+}
+
+// processThrottleDataEvent updates the internal blocking condition map and returns whether the map was changed
+func processThrottleDataEvent(notification *msg.EventThrottleData) bool {
+	payload := notification.Event.PayloadData
+	if payload.BlockingCondition == "" {
+		// Key template events are not applicable to the data plane
+		logger.LoggerMessaging.Debugf("Throttle data event without a blocking condition is dropped. Key template: %s",
+			payload.KeyTemplateValue)
+		return false
+	}
+	blockingCondition := eventhubTypes.BlockingCondition{
+		ID:             payload.ID,
+		ConditionType:  strings.ToUpper(payload.BlockingCondition),
+		ConditionValue: payload.ConditionValue,
+		TenantDomain:   payload.TenantDomain,
+	}
+	if blockingCondition.ConditionType == managementserver.BlockingConditionIP ||
+		blockingCondition.ConditionType == managementserver.BlockingConditionIPRange {
+		var ipCondition ipConditionValue
+		if err := json.Unmarshal([]byte(payload.ConditionValue), &ipCondition); err != nil {
+			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling IP blocking condition %s: %v", payload.ConditionValue, err)
+			return false
+		}
+		blockingCondition.ConditionValue = ""
+		blockingCondition.FixedIP = ipCondition.FixedIP
+		blockingCondition.StartingIP = ipCondition.StartingIP
+		blockingCondition.EndingIP = ipCondition.EndingIP
+		blockingCondition.Invert = ipCondition.Invert
+	}
+	if strings.EqualFold(payload.State, "true") {
+		logger.LoggerMessaging.Infof("Blocking condition %s added for tenant %s",
+			managementserver.GetBlockingConditionKey(blockingCondition), payload.TenantDomain)
+		managementserver.AddBlockingCondition(blockingCondition)
+	} else {
+		logger.LoggerMessaging.Infof("Blocking condition %s removed for tenant %s",
+			managementserver.GetBlockingConditionKey(blockingCondition), payload.TenantDomain)
+		managementserver.DeleteBlockingCondition(blockingCondition)
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package messaging holds the implementation for event listeners functions
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	msg "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/messaging"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
+)
+
+// handleTokenRevocation consumes the token revocation events, keeps them in the revoked token store
+// and pushes them to the connected common controllers.
+func handleTokenRevocation() {
+	for d := range msg.RevokedTokenChannel {
+		var notification msg.EventTokenRevocationNotification
+		unmarshalErr := json.Unmarshal(d.Body, &notification)
+		if unmarshalErr != nil {
+			logger.LoggerMessaging.ErrorC(logging.ErrorDetails{
+				Message:   fmt.Sprintf("Error occurred while unmarshalling revoked token event data %v", unmarshalErr.Error()),
+				Severity:  logging.MAJOR,
+				ErrorCode: 2005,
+			})
+			d.Ack(false)
+			continue
+		}
+		processTokenRevocationEvent(&notification)
+		d.Ack(false)
+	}
+	logger.LoggerMessaging.Info("handle: revoked token deliveries channel closed")
+}
+
+func processTokenRevocationEvent(notification *msg.EventTokenRevocationNotification) {
+	payload := notification.Event.PayloadData
+	if payload.RevokedToken == "" {
+		logger.LoggerMessaging.Warnf("Revoked token event %s does not contain a token. Hence dropping the event", payload.EventID)
+		return
+	}
+	logger.LoggerMessaging.Infof("Revoked token event %s is received for token type %s", payload.EventID, payload.Type)
+	revokedToken := managementserver.RevokedToken{
+		Token:      payload.RevokedToken,
+		ExpiryTime: payload.ExpiryTime,
+		TokenType:  payload.Type,
+	}
+	managementserver.AddRevokedToken(revokedToken)
+	utils.SendEvent(managementserver.CreateRevokedTokenEvent(revokedToken))
+}
@@ -18,26 +18,25 @@
 package notifier
 
 import (
-	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
-	"time"
 
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/auth"
-	logging "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
 )
 
 const (
 	deployedRevisionEP   string = "internal/data/v1/apis/deployed-revisions"
 	unDeployedRevisionEP string = "internal/data/v1/apis/undeployed-revision"
-	authBasic            string = "Basic "
-	authHeader           string = "Authorization"
-	contentTypeHeader    string = "Content-Type"
+	// failedRevisionEP is not exposed by API Manager 4.4.0 and needs a control plane extension
+	failedRevisionEP  string = "internal/data/v1/apis/failed-revisions"
+	contentTypeHeader string = "Content-Type"
+
+	// Types of the revision acknowledgements in the metrics
+	revisionDeployedAck   string = "deployed"
+	revisionUndeployedAck string = "undeployed"
+	revisionFailedAck     string = "failed"
 )
 
 // UpdateDeployedRevisions create the DeployedAPIRevision object
@@ -62,73 +61,23 @@ func SendRevisionUpdateAck(deployedRevisionList []*DeployedAPIRevision) {
 	conf, _ := config.ReadConfigs()
 	cpConfigs := conf.ControlPlane
 
-	if len(deployedRevisionList) < 1 || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate {
+	if len(deployedRevisionList) < 1 || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate || !leaderelection.IsLeader() {
 		return
 	}
 
-	logger.LoggerNotifier.Debugf("Revision deployed message is sending to Control plane")
-
-	revisionEP := cpConfigs.ServiceURL
-	if strings.HasSuffix(revisionEP, "/") {
-		revisionEP += deployedRevisionEP
-	} else {
-		revisionEP += "/" + deployedRevisionEP
-	}
-
 	jsonValue, _ := json.Marshal(deployedRevisionList)
-
-	// Setting authorization header
-	basicAuth := authBasic + auth.GetBasicAuth(cpConfigs.Username, cpConfigs.Password)
-
 	logger.LoggerNotifier.Debugf("Revision deployed message sending to Control plane: %v", string(jsonValue))
-
-	// Adding 3 retries for revision update sending
-	retries := 0
-	for retries < 3 {
-		retries++
-
-		req, _ := http.NewRequest("PATCH", revisionEP, bytes.NewBuffer(jsonValue))
-		req.Header.Set(authHeader, basicAuth)
-		req.Header.Set(contentTypeHeader, "application/json")
-		resp, err := tlsutils.InvokeControlPlane(req, cpConfigs.SkipSSLVerification)
-
-		success := true
-		if err != nil {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response from %v for attempt %v : %v", revisionEP, retries, err.Error()),
-				Severity:  logging.MAJOR,
-				ErrorCode: 2100,
-			})
-			success = false
-		}
-		if resp != nil && resp.StatusCode != http.StatusOK {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response status code %v from %v for attempt %v", resp.StatusCode, revisionEP, retries),
-				Severity:  logging.MINOR,
-				ErrorCode: 2101,
-			})
-			success = false
-		}
-		if success {
-			logger.LoggerNotifier.Infof("Revision deployed message sent to Control plane for attempt %v", retries)
-			break
-		}
-	}
+	queueRevisionAck(revisionDeployedAck, http.MethodPatch, deployedRevisionEP, jsonValue)
 }
 
 // SendRevisionUndeployAck - send the undeployed revision acknowledgement to control plane
 func SendRevisionUndeployAck(apiUUID string, revisionUUID string, environment string) {
 	conf, _ := config.ReadConfigs()
 	cpConfigs := conf.ControlPlane
-	if apiUUID == "" || revisionUUID == "" || environment == "" || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate {
+	if apiUUID == "" || revisionUUID == "" || environment == "" || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate ||
+		!leaderelection.IsLeader() {
 		return
 	}
-	revisionEP := cpConfigs.ServiceURL
-	if strings.HasSuffix(revisionEP, "/") {
-		revisionEP += unDeployedRevisionEP
-	} else {
-		revisionEP += "/" + unDeployedRevisionEP
-	}
 
 	removedRevision := UnDeployedAPIRevision{
 		APIUUID:      apiUUID,
@@ -137,36 +86,27 @@ func SendRevisionUndeployAck(apiUUID string, revisionUUID string, environment st
 	}
 
 	jsonValue, _ := json.Marshal(removedRevision)
-	basicAuth := authBasic + auth.GetBasicAuth(cpConfigs.Username, cpConfigs.Password)
-	retries := 0
-	for retries < 3 {
-		retries++
-		req, _ := http.NewRequest("POST", revisionEP, bytes.NewBuffer(jsonValue))
-		req.Header.Set(authHeader, basicAuth)
-		req.Header.Set(contentTypeHeader, "application/json")
-		resp, err := tlsutils.InvokeControlPlane(req, cpConfigs.SkipSSLVerification)
+	logger.LoggerNotifier.Debugf("Revision un-deployed message sending to Control plane: %v", string(jsonValue))
+	queueRevisionAck(revisionUndeployedAck, http.MethodPost, unDeployedRevisionEP, jsonValue)
+}
 
-		success := true
-		if err != nil {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response from %s for attempt %d : %v", revisionEP, retries, err.Error()),
-				Severity:  logging.MAJOR,
-				ErrorCode: 2100,
-			})
-			success = false
-		}
-		if resp != nil && resp.StatusCode != http.StatusOK {
-			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
-				Message:   fmt.Sprintf("Error response status code %v from %s for attempt %d", resp.StatusCode, revisionEP, retries),
-				Severity:  logging.MINOR,
-				ErrorCode: 2101,
-			})
-			success = false
-		}
-		if success {
-			logger.LoggerNotifier.Infof("Revision un-deployed message sent to Control plane for attempt %d", retries)
-			break
-		}
-		time.Sleep(2 * time.Second)
+// SendRevisionDeploymentFailureAck sends the failed revision deployment acknowledgement with the reasons of the
+// failures to the control plane. The failures are only logged when the control plane does not accept them.
+func SendRevisionDeploymentFailureAck(failedRevisionList []*FailedAPIRevision) {
+	conf, _ := config.ReadConfigs()
+	cpConfigs := conf.ControlPlane
+
+	if len(failedRevisionList) < 1 || !cpConfigs.Enabled || !cpConfigs.SendRevisionUpdate || !leaderelection.IsLeader() {
+		return
 	}
+	if !cpConfigs.SendRevisionFailure {
+		jsonValue, _ := json.Marshal(failedRevisionList)
+		logger.LoggerNotifier.Warnf("Revision deployment failed message is not sent to Control plane as "+
+			"sendRevisionFailure is disabled: %v", string(jsonValue))
+		return
+	}
+
+	jsonValue, _ := json.Marshal(failedRevisionList)
+	logger.LoggerNotifier.Debugf("Revision deployment failed message sending to Control plane: %v", string(jsonValue))
+	queueRevisionAck(revisionFailedAck, http.MethodPost, failedRevisionEP, jsonValue)
 }
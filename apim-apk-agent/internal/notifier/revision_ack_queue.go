@@ -0,0 +1,306 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
+	logging "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
+)
+
+// Maximum number of the revision acknowledgements kept while the control plane is unreachable. The oldest ones are
+// given up as dead letters when the limit is exceeded.
+const maxPendingRevisionAcks = 1000
+
+// Resource name of the revision acknowledgement requests of the control plane client
+const revisionAcksResource = "revisionacks"
+
+// revisionAck is a revision acknowledgement waiting to be delivered to the control plane
+type revisionAck struct {
+	managementserver.RevisionAck
+	// nextAttempt is the earliest time the acknowledgement is delivered again
+	nextAttempt time.Time
+}
+
+// dueRevisionAck is a queued acknowledgement along with a copy of it taken when its attempt started
+type dueRevisionAck struct {
+	ack     *revisionAck
+	attempt managementserver.RevisionAck
+}
+
+var (
+	pendingAcksLock   sync.Mutex
+	pendingAcks       []*revisionAck
+	pendingAckSignal  = make(chan struct{}, 1)
+	startAckProcessor sync.Once
+)
+
+// queueRevisionAck queues the acknowledgement to be delivered to the control plane. Each acknowledgement is retried
+// on its own with a growing backoff until it is delivered or the maximum number of attempts is reached, so that the
+// ones sent while the control plane is unreachable are delivered after it is back. The pending acknowledgements are
+// saved with the snapshots when the snapshots are enabled.
+func queueRevisionAck(ackType string, method string, path string, payload []byte) {
+	queueRevisionAcks([]managementserver.RevisionAck{{Type: ackType, Method: method, Path: path, Payload: payload}})
+}
+
+// RestoreRevisionAcks queues the acknowledgements which were not delivered before the restart of the agent, as
+// restored from the snapshot.
+func RestoreRevisionAcks() {
+	acks := managementserver.GetRevisionAcks()
+	if len(acks) == 0 {
+		return
+	}
+	logger.LoggerNotifier.Infof("Delivering %d revision acknowledgements restored from the snapshot", len(acks))
+	queueRevisionAcks(acks)
+}
+
+func queueRevisionAcks(acks []managementserver.RevisionAck) {
+	startAckProcessor.Do(func() {
+		go processRevisionAcks()
+	})
+	pendingAcksLock.Lock()
+	for _, ack := range acks {
+		pendingAcks = append(pendingAcks, &revisionAck{RevisionAck: ack})
+	}
+	for len(pendingAcks) > maxPendingRevisionAcks {
+		deadLetterRevisionAck(pendingAcks[0].RevisionAck,
+			fmt.Sprintf("%d acknowledgements are pending", maxPendingRevisionAcks))
+		pendingAcks = pendingAcks[1:]
+	}
+	savePendingRevisionAcks()
+	pendingAcksLock.Unlock()
+	select {
+	case pendingAckSignal <- struct{}{}:
+	default:
+	}
+}
+
+// getPendingRevisionAckCount returns the number of the acknowledgements which are not delivered yet
+func getPendingRevisionAckCount() int {
+	pendingAcksLock.Lock()
+	defer pendingAcksLock.Unlock()
+	return len(pendingAcks)
+}
+
+// processRevisionAcks delivers the queued acknowledgements as they become due. A failing acknowledgement does not
+// hold back the others. The acknowledgements are kept until this replica is elected as the leader.
+func processRevisionAcks() {
+	for {
+		conf, _ := config.ReadConfigs()
+		backoff := getRevisionAckBackoff(conf)
+		if !leaderelection.IsLeader() {
+			logger.LoggerNotifier.Debugf("Revision acknowledgements are kept until the agent is the leader")
+			waitForRevisionAcks(backoff.Initial)
+			continue
+		}
+		for _, due := range getDueRevisionAcks(time.Now()) {
+			completeRevisionAck(due.ack, deliverRevisionAck(due.attempt), backoff,
+				conf.ControlPlane.Retry.RevisionAckMaxAttempts)
+		}
+		waitForRevisionAcks(0)
+	}
+}
+
+// getDueRevisionAcks starts a new attempt of each acknowledgement due at the given time
+func getDueRevisionAcks(now time.Time) []dueRevisionAck {
+	pendingAcksLock.Lock()
+	defer pendingAcksLock.Unlock()
+	dueAcks := make([]dueRevisionAck, 0)
+	for _, ack := range pendingAcks {
+		if ack.nextAttempt.After(now) {
+			continue
+		}
+		ack.Attempts++
+		dueAcks = append(dueAcks, dueRevisionAck{ack: ack, attempt: ack.RevisionAck})
+	}
+	return dueAcks
+}
+
+// completeRevisionAck removes the acknowledgement from the queue when it is done with, or when it has used up the
+// maximum number of attempts. Otherwise its next attempt is scheduled after the backoff of its attempts, and not
+// before the control plane requests are resumed when they are paused by the circuit breaker.
+func completeRevisionAck(ack *revisionAck, done bool, backoff controlplane.Backoff, maxAttempts int) {
+	pause := controlplane.GetClient().RemainingPause()
+	pendingAcksLock.Lock()
+	defer pendingAcksLock.Unlock()
+	if !done && maxAttempts > 0 && ack.Attempts >= maxAttempts {
+		deadLetterRevisionAck(ack.RevisionAck, fmt.Sprintf("%d attempts failed", ack.Attempts))
+		done = true
+	}
+	if !done {
+		delay := backoff.Delay(ack.Attempts)
+		if pause > delay {
+			delay = pause
+		}
+		ack.nextAttempt = time.Now().Add(delay)
+		savePendingRevisionAcks()
+		return
+	}
+	for i, pendingAck := range pendingAcks {
+		// The acknowledgement could have been dropped while it was being delivered
+		if pendingAck == ack {
+			pendingAcks = append(pendingAcks[:i:i], pendingAcks[i+1:]...)
+			break
+		}
+	}
+	savePendingRevisionAcks()
+}
+
+// waitForRevisionAcks blocks until the earliest pending acknowledgement is due, but at least for the given duration,
+// or until a new acknowledgement is queued
+func waitForRevisionAcks(minimum time.Duration) {
+	pendingAcksLock.Lock()
+	if len(pendingAcks) == 0 {
+		pendingAcksLock.Unlock()
+		<-pendingAckSignal
+		return
+	}
+	nextAttempt := pendingAcks[0].nextAttempt
+	for _, ack := range pendingAcks[1:] {
+		if ack.nextAttempt.Before(nextAttempt) {
+			nextAttempt = ack.nextAttempt
+		}
+	}
+	pendingAcksLock.Unlock()
+	wait := time.Until(nextAttempt)
+	if wait < minimum {
+		wait = minimum
+	}
+	if wait <= 0 {
+		return
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-pendingAckSignal:
+	}
+}
+
+// savePendingRevisionAcks saves the pending acknowledgements with the snapshots. The caller must hold the
+// pendingAcksLock.
+func savePendingRevisionAcks() {
+	acks := make([]managementserver.RevisionAck, 0, len(pendingAcks))
+	for _, ack := range pendingAcks {
+		acks = append(acks, ack.RevisionAck)
+	}
+	managementserver.SetRevisionAcks(acks)
+}
+
+// deadLetterRevisionAck logs the acknowledgement given up without being delivered, along with its payload so that it
+// can be reported to the control plane manually
+func deadLetterRevisionAck(ack managementserver.RevisionAck, reason string) {
+	metrics.RecordRevisionAckDeadLetter(ack.Type)
+	logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+		Message: fmt.Sprintf("Revision %s acknowledgement to %s %s is given up as %s: %s", ack.Type, ack.Method,
+			ack.Path, reason, string(ack.Payload)),
+		Severity:  logging.MAJOR,
+		ErrorCode: 2102,
+	})
+}
+
+// getRevisionAckBackoff returns the backoff between the attempts of an acknowledgement, which starts from the retry
+// interval and grows up to the maximum retry interval of the control plane requests
+func getRevisionAckBackoff(conf *config.Config) controlplane.Backoff {
+	initialInterval := conf.ControlPlane.RetryInterval
+	if initialInterval <= 0 {
+		initialInterval = 5
+	}
+	backoff := controlplane.Backoff{
+		Initial: initialInterval * time.Second,
+		Max:     conf.ControlPlane.Retry.MaxInterval * time.Second,
+		Jitter:  conf.ControlPlane.Retry.Jitter,
+	}
+	if backoff.Max < backoff.Initial {
+		backoff.Max = backoff.Initial
+	}
+	return backoff
+}
+
+// deliverRevisionAck sends the acknowledgement to the control plane and returns whether it is done with, that is
+// whether it is delivered or rejected by the control plane
+func deliverRevisionAck(ack managementserver.RevisionAck) bool {
+	conf, _ := config.ReadConfigs()
+	cpConfigs := conf.ControlPlane
+	revisionEP := cpConfigs.ServiceURL
+	if strings.HasSuffix(revisionEP, "/") {
+		revisionEP += ack.Path
+	} else {
+		revisionEP += "/" + ack.Path
+	}
+	req, err := http.NewRequest(ack.Method, revisionEP, bytes.NewBuffer(ack.Payload))
+	if err != nil {
+		deadLetterRevisionAck(ack, fmt.Sprintf("the request cannot be created: %v", err))
+		return true
+	}
+	req.Header.Set(contentTypeHeader, "application/json")
+	resp, err := controlplane.GetClient().Do(revisionAcksResource, req)
+
+	success := true
+	if err != nil {
+		logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+			Message:   fmt.Sprintf("Error response from %s for attempt %d : %v", revisionEP, ack.Attempts, err.Error()),
+			Severity:  logging.MAJOR,
+			ErrorCode: 2100,
+		})
+		success = false
+	}
+	if resp != nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			logger.LoggerNotifier.ErrorC(logging.ErrorDetails{
+				Message: fmt.Sprintf("Error response status code %v from %s for attempt %d", resp.StatusCode, revisionEP,
+					ack.Attempts),
+				Severity:  logging.MINOR,
+				ErrorCode: 2101,
+			})
+			success = false
+		}
+	}
+	metrics.RecordRevisionAck(ack.Type, success)
+	if success {
+		logger.LoggerNotifier.Infof("Revision %s message sent to Control plane for attempt %d", ack.Type, ack.Attempts)
+		return true
+	}
+	if resp != nil && isRejectedRevisionAck(resp.StatusCode) {
+		deadLetterRevisionAck(ack, fmt.Sprintf("the control plane rejects it with the status code %d",
+			resp.StatusCode))
+		return true
+	}
+	return false
+}
+
+// isRejectedRevisionAck returns whether the status code indicates that the control plane rejects the
+// acknowledgement, in which case retrying it does not help. An unauthorized acknowledgement is retried as it is sent
+// with a new access token.
+func isRejectedRevisionAck(statusCode int) bool {
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError &&
+		statusCode != http.StatusUnauthorized && statusCode != http.StatusRequestTimeout &&
+		statusCode != http.StatusTooManyRequests
+}
@@ -0,0 +1,188 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+)
+
+func TestRevisionAcksAreRetriedUntilDelivered(t *testing.T) {
+	var lock sync.Mutex
+	controlPlaneUp := false
+	received := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if !controlPlaneUp {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf, _ := config.ReadConfigs()
+	cpConfigs := conf.ControlPlane
+	defer func() {
+		conf.ControlPlane = cpConfigs
+		controlplane.UpdateClient(conf)
+	}()
+	conf.ControlPlane.Enabled = true
+	conf.ControlPlane.SendRevisionUpdate = true
+	conf.ControlPlane.SendRevisionFailure = true
+	conf.ControlPlane.ServiceURL = server.URL
+	conf.ControlPlane.RetryInterval = 1
+	conf.ControlPlane.Retry.Jitter = 0
+	conf.ControlPlane.CircuitBreaker.FailureThreshold = 0
+	conf.ControlPlane.Authentication.Type = "Basic"
+	controlplane.UpdateClient(conf)
+
+	SendRevisionUpdateAck([]*DeployedAPIRevision{UpdateDeployedRevisions("api1", 1, []string{"Default"}, "gw.wso2.com")})
+	SendRevisionDeploymentFailureAck([]*FailedAPIRevision{{APIID: "api2", RevisionID: 3,
+		Errors: []DeploymentIssue{{Field: "crApply", Message: "conflict"}}}})
+	SendRevisionUndeployAck("api1", "revision1", "Default")
+
+	// The acknowledgements are kept while the control plane is down
+	time.Sleep(1500 * time.Millisecond)
+	if count := getPendingRevisionAckCount(); count != 3 {
+		t.Fatalf("Expected 3 pending acknowledgements, but got %d", count)
+	}
+	// The pending acknowledgements are saved with the snapshots
+	if acks := managementserver.GetRevisionAcks(); len(acks) != 3 || acks[0].Attempts < 1 {
+		t.Fatalf("Expected 3 pending acknowledgements to be saved, but got %v", acks)
+	}
+
+	lock.Lock()
+	controlPlaneUp = true
+	lock.Unlock()
+	deadline := time.Now().Add(10 * time.Second)
+	for getPendingRevisionAckCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(received) != 3 {
+		t.Fatalf("Expected 3 delivered acknowledgements, but got %v", received)
+	}
+	failedRevisions, _ := json.Marshal([]*FailedAPIRevision{{APIID: "api2", RevisionID: 3,
+		Errors: []DeploymentIssue{{Field: "crApply", Message: "conflict"}}}})
+	expected := []string{
+		"PATCH /" + deployedRevisionEP + ` [{"apiId":"api1","revisionId":1,"envInfo":[{"name":"Default","vhost":"gw.wso2.com"}]}]`,
+		"POST /" + failedRevisionEP + " " + string(failedRevisions),
+		"POST /" + unDeployedRevisionEP + ` {"apiUUID":"api1","revisionUUID":"revision1","environment":"Default"}`,
+	}
+	// The acknowledgements are retried independently of each other
+	slices.Sort(received)
+	slices.Sort(expected)
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("Expected %s, but got %s", expected[i], received[i])
+		}
+	}
+}
+
+func TestFailingRevisionAckDoesNotHoldBackOthers(t *testing.T) {
+	var lock sync.Mutex
+	received := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.URL.Path == "/"+failedRevisionEP {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf, _ := config.ReadConfigs()
+	cpConfigs := conf.ControlPlane
+	defer func() {
+		conf.ControlPlane = cpConfigs
+		controlplane.UpdateClient(conf)
+	}()
+	conf.ControlPlane.Enabled = true
+	conf.ControlPlane.SendRevisionUpdate = true
+	conf.ControlPlane.SendRevisionFailure = true
+	conf.ControlPlane.ServiceURL = server.URL
+	conf.ControlPlane.RetryInterval = 1
+	conf.ControlPlane.Retry.Jitter = 0
+	conf.ControlPlane.Retry.RevisionAckMaxAttempts = 2
+	conf.ControlPlane.CircuitBreaker.FailureThreshold = 0
+	conf.ControlPlane.Authentication.Type = "Basic"
+	controlplane.UpdateClient(conf)
+
+	SendRevisionDeploymentFailureAck([]*FailedAPIRevision{{APIID: "api2", RevisionID: 3,
+		Errors: []DeploymentIssue{{Field: "crApply", Message: "conflict"}}}})
+	SendRevisionUndeployAck("api1", "revision1", "Default")
+
+	// The undeployment is delivered while the failure report is failing
+	deadline := time.Now().Add(900 * time.Millisecond)
+	for getPendingRevisionAckCount() > 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	lock.Lock()
+	if len(received) != 1 || received[0] != "POST /"+unDeployedRevisionEP {
+		lock.Unlock()
+		t.Fatalf("Expected the undeployment to be delivered, but got %v", received)
+	}
+	lock.Unlock()
+
+	// The failure report is given up after the maximum number of attempts
+	deadline = time.Now().Add(5 * time.Second)
+	for getPendingRevisionAckCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if count := getPendingRevisionAckCount(); count != 0 {
+		t.Fatalf("Expected the failing acknowledgement to be given up, but %d are pending", count)
+	}
+	if acks := managementserver.GetRevisionAcks(); len(acks) != 0 {
+		t.Errorf("Expected no pending acknowledgements to be saved, but got %v", acks)
+	}
+}
+
+func TestRejectedRevisionAcks(t *testing.T) {
+	for statusCode, rejected := range map[int]bool{
+		http.StatusBadRequest:          true,
+		http.StatusNotFound:            true,
+		http.StatusUnauthorized:        false,
+		http.StatusTooManyRequests:     false,
+		http.StatusRequestTimeout:      false,
+		http.StatusInternalServerError: false,
+		http.StatusServiceUnavailable:  false,
+	} {
+		if isRejectedRevisionAck(statusCode) != rejected {
+			t.Errorf("Expected the rejection of the status code %d to be %v", statusCode, rejected)
+		}
+	}
+}
@@ -36,3 +36,18 @@ type UnDeployedAPIRevision struct {
 	RevisionUUID string `json:"revisionUUID"`
 	Environment  string `json:"environment"`
 }
+
+// FailedAPIRevision represents an API revision which is not deployed in the data plane and the reasons for it
+type FailedAPIRevision struct {
+	APIID      string            `json:"apiId"`
+	RevisionID int               `json:"revisionId"`
+	EnvInfo    []DeployedEnvInfo `json:"envInfo"`
+	Errors     []DeploymentIssue `json:"errors"`
+	Warnings   []DeploymentIssue `json:"warnings"`
+}
+
+// DeploymentIssue describes an error or a warning found while deploying an API revision
+type DeploymentIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
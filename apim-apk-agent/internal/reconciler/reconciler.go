@@ -0,0 +1,284 @@
+/*
+ * Copyright (c) 2024, WSO2 LLC. (https://www.wso2.com)
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+// Package reconciler periodically reconciles the CRs in the data plane with the control plane so that the
+// resources edited or deleted by hand and the events missed while the broker is down are repaired.
+package reconciler
+
+import (
+	"time"
+
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/leaderelection"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/synchronizer"
+	internalutils "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/utils"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	kindAPI               = "API"
+	kindTokenIssuer       = "TokenIssuer"
+	kindAIProvider        = "AIProvider"
+	kindRateLimitPolicy   = "RateLimitPolicy"
+	kindAIRateLimitPolicy = "AIRateLimitPolicy"
+
+	apiUUIDLabel    = "apiUUID"
+	revisionIDLabel = "revisionID"
+)
+
+// Start reconciles the data plane with the control plane in the configured interval until the agent stops.
+func Start(conf *config.Config, k8sClient client.Client) {
+	if conf.ControlPlane.ReconcileInterval <= 0 {
+		logger.LoggerReconciler.Info("Reconciliation is disabled")
+		return
+	}
+	logger.LoggerReconciler.Infof("Starting the reconciliation with the interval %v",
+		conf.ControlPlane.ReconcileInterval*time.Second)
+	ticker := time.NewTicker(conf.ControlPlane.ReconcileInterval * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !leaderelection.IsLeader() {
+			// The leader repairs the drift
+			continue
+		}
+		// The configurations are replaced when the configuration file is reloaded
+		current, _ := config.ReadConfigs()
+		Reconcile(current, k8sClient)
+	}
+}
+
+// Reconcile compares the CRs in the data plane with the control plane, redeploys the missing or modified
+// resources and deletes the orphaned resources created by the agent.
+func Reconcile(conf *config.Config, k8sClient client.Client) {
+	logger.LoggerReconciler.Debug("Reconciliation started")
+	if !reconcileAPIs(conf, k8sClient) {
+		// The other resources are not reconciled either as their fetchers keep retrying in the background
+		// when the control plane is unreachable.
+		metrics.RecordReconcileRun(false)
+		return
+	}
+	succeeded := reconcileKinds(map[string]retrieveGenerationsFunc{
+		kindTokenIssuer: func() (map[string]int64, error) {
+			tokenIssuers, _, err := synchronizer.RetrieveAllTokenIssuersFromK8s(k8sClient, "")
+			generations := make(map[string]int64, len(tokenIssuers))
+			for _, tokenIssuer := range tokenIssuers {
+				generations[tokenIssuer.Name] = tokenIssuer.Generation
+			}
+			return generations, err
+		},
+	}, func() {
+		synchronizer.FetchKeyManagersOnStartUp(k8sClient)
+	})
+	succeeded = reconcileKinds(map[string]retrieveGenerationsFunc{
+		kindAIProvider: func() (map[string]int64, error) {
+			aiProviders, _, err := k8sclient.RetrieveAllAIProvidersFromK8s(k8sClient, "")
+			generations := make(map[string]int64, len(aiProviders))
+			for _, aiProvider := range aiProviders {
+				generations[aiProvider.Name] = aiProvider.Generation
+			}
+			return generations, err
+		},
+	}, func() {
+		synchronizer.FetchAIProvidersOnEvent("", "", "", k8sClient, true)
+	}) && succeeded
+	succeeded = reconcileKinds(map[string]retrieveGenerationsFunc{
+		kindRateLimitPolicy: func() (map[string]int64, error) {
+			rateLimitPolicies, _, err := k8sclient.RetrieveAllRatelimitPoliciesSFromK8s(k8sClient, "")
+			generations := make(map[string]int64, len(rateLimitPolicies))
+			for _, rateLimitPolicy := range rateLimitPolicies {
+				generations[rateLimitPolicy.Name] = rateLimitPolicy.Generation
+			}
+			return generations, err
+		},
+		kindAIRateLimitPolicy: func() (map[string]int64, error) {
+			aiRateLimitPolicies, _, err := k8sclient.RetrieveAllAIRatelimitPoliciesSFromK8s(k8sClient, "")
+			generations := make(map[string]int64, len(aiRateLimitPolicies))
+			for _, aiRateLimitPolicy := range aiRateLimitPolicies {
+				generations[aiRateLimitPolicy.Name] = aiRateLimitPolicy.Generation
+			}
+			return generations, err
+		},
+	}, func() {
+		synchronizer.FetchRateLimitPoliciesOnEvent("", "", k8sClient)
+		synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent("", "", k8sClient, true)
+	}) && succeeded
+	metrics.RecordReconcileRun(succeeded)
+	logger.LoggerReconciler.Debugf("Reconciliation completed. Succeeded: %v", succeeded)
+}
+
+// reconcileAPIs redeploys the APIs which are missing in the cluster or deployed with a different revision or
+// with missing routes, and removes the APIs created by the agent which are no longer in the control plane.
+func reconcileAPIs(conf *config.Config, k8sClient client.Client) bool {
+	k8sAPIs, _, err := k8sclient.RetrieveAllAPISFromK8s(k8sClient, "")
+	if err != nil {
+		logger.LoggerReconciler.Errorf("Error while retrieving the APIs from K8s: %v", err)
+		return false
+	}
+	routeNames, err := retrieveRouteNames(k8sClient)
+	if err != nil {
+		logger.LoggerReconciler.Errorf("Error while retrieving the routes from K8s: %v", err)
+		return false
+	}
+	deployedAPIs := make(map[string]dpv1alpha3.API)
+	for _, k8sAPI := range k8sAPIs {
+		if apiUUID, exists := k8sAPI.ObjectMeta.Labels[apiUUIDLabel]; exists {
+			deployedAPIs[apiUUID] = k8sAPI
+		}
+	}
+
+	drift := make(map[string]int)
+	apis, err := internalutils.FetchAndDeployAPIs(conf, nil, k8sClient, func(apiUUID string, revisionID string) bool {
+		var deployedAPI *dpv1alpha3.API
+		if api, exists := deployedAPIs[apiUUID]; exists {
+			deployedAPI = &api
+		}
+		driftType := getAPIDrift(deployedAPI, revisionID, routeNames)
+		if driftType == "" {
+			return true
+		}
+		logger.LoggerReconciler.Infof("API %s is %s in the data plane. Hence redeploying it", apiUUID, driftType)
+		drift[driftType]++
+		return false
+	})
+	if apis == nil {
+		logger.LoggerReconciler.Errorf("Error while fetching the APIs from the control plane: %v", err)
+		recordDrift(kindAPI, drift)
+		return false
+	}
+	// The orphaned APIs are removed even when some of the drifted APIs fail to redeploy
+	if err != nil {
+		logger.LoggerReconciler.Errorf("Error while redeploying the drifted APIs: %v", err)
+	}
+
+	apisInControlPlane := make(map[string]struct{}, len(*apis))
+	for _, apiUUID := range *apis {
+		apisInControlPlane[apiUUID] = struct{}{}
+	}
+	for apiUUID, deployedAPI := range deployedAPIs {
+		if _, exists := apisInControlPlane[apiUUID]; exists || deployedAPI.Spec.SystemAPI {
+			continue
+		}
+		logger.LoggerReconciler.Infof("API %s is not found in the control plane. Hence removing it from the K8s", deployedAPI.Name)
+		drift[metrics.DriftOrphaned]++
+		k8sclient.UndeployK8sAPICR(k8sClient, deployedAPI)
+	}
+	recordDrift(kindAPI, drift)
+	return err == nil
+}
+
+// getAPIDrift returns the drift type of an API revision in the control plane compared to the API deployed in the
+// cluster, or an empty string if the API is in sync.
+func getAPIDrift(deployedAPI *dpv1alpha3.API, revisionID string, routeNames map[types.NamespacedName]struct{}) string {
+	if deployedAPI == nil {
+		return metrics.DriftMissing
+	}
+	if deployedAPI.ObjectMeta.Labels[revisionIDLabel] != revisionID {
+		return metrics.DriftModified
+	}
+	for _, envConfigs := range [][]dpv1alpha3.EnvConfig{deployedAPI.Spec.Production, deployedAPI.Spec.Sandbox} {
+		for _, envConfig := range envConfigs {
+			for _, routeRef := range envConfig.RouteRefs {
+				routeName := types.NamespacedName{Namespace: deployedAPI.Namespace, Name: routeRef}
+				if _, exists := routeNames[routeName]; !exists {
+					return metrics.DriftModified
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// retrieveRouteNames returns the namespaced names of all the HTTPRoutes and GQLRoutes in the data plane namespaces
+func retrieveRouteNames(k8sClient client.Client) (map[types.NamespacedName]struct{}, error) {
+	routeNames := make(map[types.NamespacedName]struct{})
+	httpRoutes, _, err := k8sclient.RetrieveAllHTTPRoutesFromK8s(k8sClient, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, httpRoute := range httpRoutes {
+		routeNames[types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}] = struct{}{}
+	}
+	gqlRoutes, _, err := k8sclient.RetrieveAllGQLRoutesFromK8s(k8sClient, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, gqlRoute := range gqlRoutes {
+		routeNames[types.NamespacedName{Namespace: gqlRoute.Namespace, Name: gqlRoute.Name}] = struct{}{}
+	}
+	return routeNames, nil
+}
+
+// retrieveGenerationsFunc returns the generations of the CRs of a kind in the cluster mapped by their names
+type retrieveGenerationsFunc func() (map[string]int64, error)
+
+// reconcileKinds syncs the CRs of the given kinds with the control plane and records the drift by comparing the
+// CRs in the cluster before and after the sync.
+func reconcileKinds(retrievers map[string]retrieveGenerationsFunc, sync func()) bool {
+	before := make(map[string]map[string]int64, len(retrievers))
+	for kind, retrieveGenerations := range retrievers {
+		generations, err := retrieveGenerations()
+		if err != nil {
+			logger.LoggerReconciler.Errorf("Error while retrieving %s CRs from K8s: %v", kind, err)
+			return false
+		}
+		before[kind] = generations
+	}
+	sync()
+	for kind, retrieveGenerations := range retrievers {
+		after, err := retrieveGenerations()
+		if err != nil {
+			logger.LoggerReconciler.Errorf("Error while retrieving %s CRs from K8s: %v", kind, err)
+			return false
+		}
+		recordDrift(kind, diffGenerations(before[kind], after))
+	}
+	return true
+}
+
+// diffGenerations counts the CRs created, updated and deleted between the two snapshots of CR generations
+func diffGenerations(before map[string]int64, after map[string]int64) map[string]int {
+	drift := make(map[string]int)
+	for name, generation := range after {
+		previousGeneration, exists := before[name]
+		if !exists {
+			drift[metrics.DriftMissing]++
+		} else if previousGeneration != generation {
+			drift[metrics.DriftModified]++
+		}
+	}
+	for name := range before {
+		if _, exists := after[name]; !exists {
+			drift[metrics.DriftOrphaned]++
+		}
+	}
+	return drift
+}
+
+// recordDrift logs and exposes the drift of a kind as metrics
+func recordDrift(kind string, drift map[string]int) {
+	for _, driftType := range []string{metrics.DriftMissing, metrics.DriftModified, metrics.DriftOrphaned} {
+		if drift[driftType] > 0 {
+			logger.LoggerReconciler.Infof("%d %s %s CRs repaired", drift[driftType], driftType, kind)
+		}
+		metrics.RecordDrift(kind, driftType, drift[driftType])
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024, WSO2 LLC. (https://www.wso2.com)
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetAPIDrift(t *testing.T) {
+	deployedAPI := &dpv1alpha3.API{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pizzashack",
+			Namespace: "apk-wso2",
+			Labels:    map[string]string{apiUUIDLabel: "api-uuid", revisionIDLabel: "2"},
+		},
+		Spec: dpv1alpha3.APISpec{
+			Production: []dpv1alpha3.EnvConfig{{RouteRefs: []string{"pizzashack-production-httproute-1"}}},
+			Sandbox:    []dpv1alpha3.EnvConfig{{RouteRefs: []string{"pizzashack-sandbox-httproute-1"}}},
+		},
+	}
+	productionRoute := types.NamespacedName{Namespace: "apk-wso2", Name: "pizzashack-production-httproute-1"}
+	sandboxRoute := types.NamespacedName{Namespace: "apk-wso2", Name: "pizzashack-sandbox-httproute-1"}
+	routeNames := map[types.NamespacedName]struct{}{productionRoute: {}, sandboxRoute: {}}
+
+	assert.Equal(t, "", getAPIDrift(deployedAPI, "2", routeNames))
+	assert.Equal(t, metrics.DriftMissing, getAPIDrift(nil, "2", routeNames))
+	assert.Equal(t, metrics.DriftModified, getAPIDrift(deployedAPI, "3", routeNames))
+	delete(routeNames, sandboxRoute)
+	assert.Equal(t, metrics.DriftModified, getAPIDrift(deployedAPI, "2", routeNames))
+
+	// The routes of the API are looked up in the namespace of the API
+	routeNames = map[types.NamespacedName]struct{}{
+		{Namespace: "apk", Name: productionRoute.Name}: {},
+		{Namespace: "apk", Name: sandboxRoute.Name}:    {},
+	}
+	assert.Equal(t, metrics.DriftModified, getAPIDrift(deployedAPI, "2", routeNames))
+}
+
+func TestDiffGenerations(t *testing.T) {
+	before := map[string]int64{"unchanged": 1, "updated": 1, "deleted": 3}
+	after := map[string]int64{"unchanged": 1, "updated": 2, "created": 1}
+
+	drift := diffGenerations(before, after)
+	assert.Equal(t, 1, drift[metrics.DriftMissing])
+	assert.Equal(t, 1, drift[metrics.DriftModified])
+	assert.Equal(t, 1, drift[metrics.DriftOrphaned])
+	assert.Empty(t, diffGenerations(after, after))
+}
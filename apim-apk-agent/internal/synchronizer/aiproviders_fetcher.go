@@ -31,22 +31,25 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
 	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
-	pkgAuth "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/auth"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
 	eventhubTypes "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
-	sync "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/synchronizer"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	aiProviderEndpoint string = "internal/data/v1/llm-providers"
+
+	// Resource names of the control plane requests in the metrics
+	aiProvidersResource string = "aiproviders"
 )
 
 // FetchAIProvidersOnEvent fetches the AI Providers from the control plane on the start up and notification event updates
@@ -71,13 +74,6 @@ func FetchAIProvidersOnEvent(aiProviderName string, aiProviderVersion string, or
 	}
 	logger.LoggerSynchronizer.Debugf("Fetching AI Providers from the URL %v: ", ehURL)
 
-	ehUname := ehConfigs.Username
-	ehPass := ehConfigs.Password
-	basicAuth := "Basic " + pkgAuth.GetBasicAuth(ehUname, ehPass)
-
-	// Check if TLS is enabled
-	skipSSL := ehConfigs.SkipSSLVerification
-
 	// Create a HTTP request
 	req, err := http.NewRequest("GET", ehURL, nil)
 	if err != nil {
@@ -103,8 +99,6 @@ func FetchAIProvidersOnEvent(aiProviderName string, aiProviderVersion string, or
 		}
 		req.URL.RawQuery = q.Encode()
 	}
-	// Setting authorization header
-	req.Header.Set(sync.Authorization, basicAuth)
 
 	if organization != "" {
 		logger.LoggerSynchronizer.Debugf("Setting the organization header for the request: %v", organization)
@@ -116,7 +110,9 @@ func FetchAIProvidersOnEvent(aiProviderName string, aiProviderVersion string, or
 
 	// Make the request
 	logger.LoggerSynchronizer.Debugf("Sending the control plane request" + req.RequestURI)
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
+	start := time.Now()
+	resp, err := controlplane.GetClient().Do(aiProvidersResource, req)
+	metrics.ObserveControlPlaneRequest(aiProvidersResource, start, err == nil && resp.StatusCode == http.StatusOK)
 	var errorMsg string
 	if err != nil {
 		errorMsg = "Error occurred while calling the REST API: " + aiProviderEndpoint
@@ -156,7 +152,7 @@ func FetchAIProvidersOnEvent(aiProviderName string, aiProviderVersion string, or
 						}
 						if !found {
 							// Delete the airatelimitpolicy
-							k8sclient.DeleteAIProviderCR(aiP.Name, c)
+							k8sclient.DeleteAIProviderCR(aiP.Name, aiP.Namespace, c)
 						}
 					}
 				}
@@ -225,7 +221,7 @@ func createAIProvider(aiProvider *eventhubTypes.AIProvider) dpv1alpha3.AIProvide
 	crAIProvider := dpv1alpha3.AIProvider{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      aiProvider.ID,
-			Namespace: conf.DataPlane.Namespace,
+			Namespace: conf.GetTenantNamespace(aiProvider.Organization),
 			Labels:    labelMap,
 		},
 		Spec: dpv1alpha3.AIProviderSpec{
@@ -0,0 +1,171 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+/*
+ * Package "synchronizer" contains artifacts relate to fetching APIs and
+ * API related updates from the control plane event-hub.
+ * This file contains functions to retrieve blocking conditions.
+ */
+
+package synchronizer
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
+	eventhubTypes "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	blockingConditionsEndpoint string = "internal/data/v1/block"
+
+	// Resource names of the control plane requests in the metrics
+	blockingConditionsResource string = "blockingconditions"
+)
+
+// FetchBlockingConditionsOnStartUp pulls the blocking conditions from the control plane, stores them in the
+// internal map and applies them to the data plane. The failed requests are retried in the background.
+func FetchBlockingConditionsOnStartUp(c client.Client) {
+	logger.LoggerSynchronizer.Info("Fetching Blocking Conditions from Control Plane.")
+	if errorMsg, err := fetchBlockingConditions(c); err != nil {
+		go retryBlockingConditionsFetchData(errorMsg, err, c)
+	}
+}
+
+// fetchBlockingConditions pulls and applies the blocking conditions. The error is returned along with its
+// description when the request should be retried.
+func fetchBlockingConditions(c client.Client) (string, error) {
+	// Read configurations and derive the eventHub details
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		// This has to be error. For debugging purpose info
+		logger.LoggerSynchronizer.Errorf("Error reading configs: %v", errReadConfig)
+	}
+	// Populate data from the config
+	ehConfigs := conf.ControlPlane
+	ehURL := ehConfigs.ServiceURL
+	// If the eventHub URL is configured with trailing slash
+	if strings.HasSuffix(ehURL, "/") {
+		ehURL += blockingConditionsEndpoint
+	} else {
+		ehURL += "/" + blockingConditionsEndpoint
+	}
+	logger.LoggerSynchronizer.Debugf("Fetching Blocking Conditions from the URL %v: ", ehURL)
+
+	// Create a HTTP request
+	req, err := http.NewRequest("GET", ehURL, nil)
+	if err != nil {
+		logger.LoggerSynchronizer.Errorf("Error while creating http request for Blocking Conditions Endpoint : %v", err)
+		return "", nil
+	}
+	req.Header.Set("xWSO2Tenant", "ALL")
+
+	// Make the request
+	logger.LoggerSynchronizer.Debug("Sending the control plane request")
+	start := time.Now()
+	resp, err := controlplane.GetClient().Do(blockingConditionsResource, req)
+	metrics.ObserveControlPlaneRequest(blockingConditionsResource, start, err == nil && resp.StatusCode == http.StatusOK)
+	if err != nil {
+		return "Error occurred while calling the REST API: " + blockingConditionsEndpoint, err
+	}
+	defer resp.Body.Close()
+	responseBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "Error occurred while reading the response received for: " + blockingConditionsEndpoint, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "Failed to fetch data! " + blockingConditionsEndpoint + " responded with " +
+			strconv.Itoa(resp.StatusCode), errors.New(string(responseBytes))
+	}
+
+	var blockConditions BlockConditions
+	if err := json.Unmarshal(responseBytes, &blockConditions); err != nil {
+		logger.LoggerSynchronizer.Errorf("Error occurred while unmarshelling Blocking Conditions data %v", err)
+		return "", nil
+	}
+	blockingConditions := marshalBlockConditions(blockConditions)
+	logger.LoggerSynchronizer.Infof("%d Blocking Conditions received", len(blockingConditions))
+	managementserver.AddAllBlockingConditions(blockingConditions)
+	k8sclient.DeployBlockingConditionsCR(managementserver.GetAllBlockingConditions(), c)
+	return "", nil
+}
+
+// retryBlockingConditionsFetchData retries fetching the blocking conditions until they are received or the retries
+// are exhausted
+func retryBlockingConditionsFetchData(errorMessage string, err error, c client.Client) {
+	for attempt := 1; attempt <= retryCount; attempt++ {
+		controlplane.GetClient().WaitBeforeRetry(blockingConditionsResource)
+		metrics.RecordControlPlaneRetry(blockingConditionsResource)
+		errorMessage, err = fetchBlockingConditions(c)
+		if err == nil {
+			return
+		}
+	}
+	logger.LoggerSynchronizer.Errorf("%s: %v", errorMessage, err)
+}
+
+// marshalBlockConditions converts the blocking conditions received from the control plane to the internal
+// representation.
+func marshalBlockConditions(blockConditions BlockConditions) []eventhubTypes.BlockingCondition {
+	blockingConditions := make([]eventhubTypes.BlockingCondition, 0)
+	conditionValues := map[string][]string{
+		managementserver.BlockingConditionAPI:          blockConditions.API,
+		managementserver.BlockingConditionApplication:  blockConditions.Application,
+		managementserver.BlockingConditionUser:         blockConditions.User,
+		managementserver.BlockingConditionSubscription: blockConditions.Subscription,
+		managementserver.BlockingConditionCustom:       blockConditions.Custom,
+	}
+	for conditionType, values := range conditionValues {
+		for _, value := range values {
+			blockingConditions = append(blockingConditions, eventhubTypes.BlockingCondition{
+				ConditionType:  conditionType,
+				ConditionValue: value,
+			})
+		}
+	}
+	for _, ipCondition := range blockConditions.IP {
+		if strings.EqualFold(ipCondition.State, "false") {
+			continue
+		}
+		conditionType := strings.ToUpper(ipCondition.Type)
+		if conditionType == "" {
+			conditionType = managementserver.BlockingConditionIP
+		}
+		blockingConditions = append(blockingConditions, eventhubTypes.BlockingCondition{
+			ID:            ipCondition.ID,
+			ConditionType: conditionType,
+			FixedIP:       ipCondition.FixedIP,
+			StartingIP:    ipCondition.StartingIP,
+			EndingIP:      ipCondition.EndingIP,
+			Invert:        ipCondition.Invert,
+			TenantDomain:  ipCondition.TenantDomain,
+		})
+	}
+	return blockingConditions
+}
@@ -38,10 +38,9 @@ import (
 	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/logging"
-	pkgAuth "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/auth"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
 	eventhubTypes "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
-	sync "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/synchronizer"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -49,6 +48,9 @@ import (
 const (
 	keyManagersEndpoint string = "internal/data/v1/keymanagers"
 	retryCount          int    = 5
+
+	// Resource names of the control plane requests in the metrics
+	keyManagersResource string = "keymanagers"
 )
 
 var retryAttempt int
@@ -76,13 +78,6 @@ func FetchKeyManagersOnStartUp(c client.Client) {
 	}
 	logger.LoggerSynchronizer.Debugf("Fetching KeyManagers from the URL %v: ", ehURL)
 
-	ehUname := ehConfigs.Username
-	ehPass := ehConfigs.Password
-	basicAuth := "Basic " + pkgAuth.GetBasicAuth(ehUname, ehPass)
-
-	// Check if TLS is enabled
-	skipSSL := ehConfigs.SkipSSLVerification
-
 	// Create a HTTP request
 	req, err := http.NewRequest("GET", ehURL, nil)
 	if err != nil {
@@ -99,15 +94,15 @@ func FetchKeyManagersOnStartUp(c client.Client) {
 		}
 		req.URL.RawQuery = q.Encode()
 	}
-	// Setting authorization header
-	req.Header.Set(sync.Authorization, basicAuth)
 
 	//Todo: Need to set ALL when APIM Fix is available
 	req.Header.Set("xWSO2Tenant", "ALL")
 
 	// Make the request
 	logger.LoggerSynchronizer.Debug("Sending the control plane request")
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
+	start := time.Now()
+	resp, err := controlplane.GetClient().Do(keyManagersResource, req)
+	metrics.ObserveControlPlaneRequest(keyManagersResource, start, err == nil && resp.StatusCode == http.StatusOK)
 	var errorMsg string
 	if err != nil {
 		errorMsg = "Error occurred while calling the REST API: " + keyManagersEndpoint
@@ -139,9 +134,8 @@ func FetchKeyManagersOnStartUp(c client.Client) {
 }
 
 func retryFetchData(conf *config.Config, errorMessage string, err error, c client.Client) {
-	logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
-		conf.ControlPlane.RetryInterval*time.Second)
-	time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
+	controlplane.GetClient().WaitBeforeRetry(keyManagersResource)
+	metrics.RecordControlPlaneRetry(keyManagersResource)
 	FetchKeyManagersOnStartUp(c)
 	retryAttempt++
 	if retryAttempt >= retryCount {
@@ -150,7 +144,7 @@ func retryFetchData(conf *config.Config, errorMessage string, err error, c clien
 	}
 }
 func applyAllKeymanagerConfifuration(c client.Client, resolvedKeyManagers []eventhubTypes.ResolvedKeyManager) error {
-	tokenIssuersFromK8s, _, err := retrieveAllTokenIssuers(c, "")
+	tokenIssuersFromK8s, _, err := RetrieveAllTokenIssuersFromK8s(c, "")
 	if err != nil {
 		return err
 	}
@@ -201,8 +195,24 @@ func applyAllKeymanagerConfifuration(c client.Client, resolvedKeyManagers []even
 	}
 	return nil
 }
-func retrieveAllTokenIssuers(c client.Client, nextToken string) ([]dpv1alpha2.TokenIssuer, string, error) {
+
+// RetrieveAllTokenIssuersFromK8s retrieves all the TokenIssuer CRs created by the agent from the data plane
+// namespaces of all the tenants
+func RetrieveAllTokenIssuersFromK8s(c client.Client, nextToken string) ([]dpv1alpha2.TokenIssuer, string, error) {
 	conf, _ := config.ReadConfigs()
+	resolvedTokenIssuerList := make([]dpv1alpha2.TokenIssuer, 0)
+	for _, namespace := range conf.GetDataPlaneNamespaces() {
+		tokenIssuerList, _, err := retrieveTokenIssuersFromK8sNamespace(c, namespace, nextToken)
+		if err != nil {
+			return nil, "", err
+		}
+		resolvedTokenIssuerList = append(resolvedTokenIssuerList, tokenIssuerList...)
+	}
+	return resolvedTokenIssuerList, "", nil
+}
+
+// retrieveTokenIssuersFromK8sNamespace retrieves all the TokenIssuer CRs created by the agent in the given namespace
+func retrieveTokenIssuersFromK8sNamespace(c client.Client, namespace string, nextToken string) ([]dpv1alpha2.TokenIssuer, string, error) {
 	tokenIssuerList := dpv1alpha2.TokenIssuerList{}
 	resolvedTokenIssuerList := make([]dpv1alpha2.TokenIssuer, 0)
 	var err error
@@ -210,7 +220,7 @@ func retrieveAllTokenIssuers(c client.Client, nextToken string) ([]dpv1alpha2.To
 	labelSelector := labels.SelectorFromSet(labels.Set{"InitiateFrom": "CP"})
 
 	opts := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     namespace,
 		LabelSelector: labelSelector,
 	}
 	if nextToken == "" {
@@ -225,7 +235,7 @@ func retrieveAllTokenIssuers(c client.Client, nextToken string) ([]dpv1alpha2.To
 	}
 	resolvedTokenIssuerList = append(resolvedTokenIssuerList, tokenIssuerList.Items...)
 	if tokenIssuerList.Continue != "" {
-		tempTokenIssuerList, _, err := retrieveAllTokenIssuers(c, tokenIssuerList.Continue)
+		tempTokenIssuerList, _, err := retrieveTokenIssuersFromK8sNamespace(c, namespace, tokenIssuerList.Continue)
 		if err != nil {
 			return nil, "", err
 		}
@@ -34,11 +34,10 @@ import (
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
 	k8sclient "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
-	pkgAuth "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/auth"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
 	eventhubTypes "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
-	sync "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/synchronizer"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -47,6 +46,10 @@ const (
 	policiesByNameEndpoint              string = "internal/data/v1/api-policies?policyName="
 	subscriptionsPoliciesEndpoint       string = "internal/data/v1/subscription-policies"
 	subscriptionsPoliciesByNameEndpoint string = "internal/data/v1/subscription-policies?policyName="
+
+	// Resource names of the control plane requests in the metrics
+	rateLimitPoliciesResource             string = "ratelimitpolicies"
+	subscriptionRateLimitPoliciesResource string = "subscriptionratelimitpolicies"
 )
 
 // FetchRateLimitPoliciesOnEvent fetches the policies from the control plane on the start up and notification event updates
@@ -79,13 +82,6 @@ func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string, c
 
 	logger.LoggerSynchronizer.Debugf("Fetching RateLimit Policies from the URL %v: ", ehURL)
 
-	ehUname := ehConfigs.Username
-	ehPass := ehConfigs.Password
-	basicAuth := "Basic " + pkgAuth.GetBasicAuth(ehUname, ehPass)
-
-	// Check if TLS is enabled
-	skipSSL := ehConfigs.SkipSSLVerification
-
 	// Create a HTTP request
 	req, err := http.NewRequest("GET", ehURL, nil)
 	if err != nil {
@@ -102,8 +98,6 @@ func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string, c
 		}
 		req.URL.RawQuery = q.Encode()
 	}
-	// Setting authorization header
-	req.Header.Set(sync.Authorization, basicAuth)
 
 	if organization != "" {
 		logger.LoggerSynchronizer.Debugf("Setting the organization header for the request: %v", organization)
@@ -115,7 +109,9 @@ func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string, c
 
 	// Make the request
 	logger.LoggerSynchronizer.Debug("Sending the control plane request")
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
+	start := time.Now()
+	resp, err := controlplane.GetClient().Do(rateLimitPoliciesResource, req)
+	metrics.ObserveControlPlaneRequest(rateLimitPoliciesResource, start, err == nil && resp.StatusCode == http.StatusOK)
 	var errorMsg string
 	if err != nil {
 		errorMsg = "Error occurred while calling the REST API: " + policiesEndpoint
@@ -191,22 +187,12 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 
 	logger.LoggerSynchronizer.Infof("Fetching Subscription RateLimit Policies from the URL %v: ", ehURL)
 
-	ehUname := ehConfigs.Username
-	ehPass := ehConfigs.Password
-	basicAuth := "Basic " + pkgAuth.GetBasicAuth(ehUname, ehPass)
-
-	// Check if TLS is enabled
-	skipSSL := ehConfigs.SkipSSLVerification
-
 	// Create a HTTP request
 	req, err := http.NewRequest("GET", ehURL, nil)
 	if err != nil {
 		logger.LoggerSynchronizer.Errorf("Error while creating http request for Subscription RateLimit Policies Endpoint : %v", err)
 	}
 
-	// Setting authorization header
-	req.Header.Set(sync.Authorization, basicAuth)
-
 	if organization != "" {
 		logger.LoggerSynchronizer.Debugf("Setting the organization header for the request: %v", organization)
 		req.Header.Set("xWSO2Tenant", organization)
@@ -217,7 +203,9 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 
 	// Make the request
 	logger.LoggerSynchronizer.Debug("Sending the control plane request")
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
+	start := time.Now()
+	resp, err := controlplane.GetClient().Do(subscriptionRateLimitPoliciesResource, req)
+	metrics.ObserveControlPlaneRequest(subscriptionRateLimitPoliciesResource, start, err == nil && resp.StatusCode == http.StatusOK)
 	var errorMsg string
 	if err != nil {
 		errorMsg = "Error occurred while calling the REST API: " + policiesEndpoint
@@ -259,7 +247,7 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 						}
 						if !found {
 							// Delete the airatelimitpolicy
-							k8sclient.UndeploySubscriptionAIRateLimitPolicyCR(airl.Name, c)
+							k8sclient.UndeploySubscriptionAIRateLimitPolicyCR(airl.Name, airl.Namespace, c)
 						}
 					}
 				}
@@ -278,7 +266,7 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 						}
 						if !found {
 							// Delete the airatelimitpolicy
-							k8sclient.UnDeploySubscriptionRateLimitPolicyCR(rl.Name, c)
+							k8sclient.UnDeploySubscriptionRateLimitPolicyCR(rl.Name, rl.Namespace, c)
 						}
 					}
 				}
@@ -338,9 +326,8 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 }
 
 func retryRLPFetchData(conf *config.Config, errorMessage string, err error, c client.Client) {
-	logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
-		conf.ControlPlane.RetryInterval*time.Second)
-	time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
+	controlplane.GetClient().WaitBeforeRetry(rateLimitPoliciesResource)
+	metrics.RecordControlPlaneRetry(rateLimitPoliciesResource)
 	FetchRateLimitPoliciesOnEvent("", "", c)
 	retryAttempt++
 	if retryAttempt >= retryCount {
@@ -350,9 +337,8 @@ func retryRLPFetchData(conf *config.Config, errorMessage string, err error, c cl
 }
 
 func retrySubscriptionRLPFetchData(conf *config.Config, errorMessage string, err error, c client.Client) {
-	logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
-		conf.ControlPlane.RetryInterval*time.Second)
-	time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
+	controlplane.GetClient().WaitBeforeRetry(subscriptionRateLimitPoliciesResource)
+	metrics.RecordControlPlaneRetry(subscriptionRateLimitPoliciesResource)
 	FetchSubscriptionRateLimitPoliciesOnEvent("", "", c, false)
 	retryAttempt++
 	if retryAttempt >= retryCount {
@@ -0,0 +1,93 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+)
+
+// Lifecycle statuses of the APIs which are not deployed to the data plane
+const (
+	blockedLifeCycleStatus = "BLOCKED"
+	retiredLifeCycleStatus = "RETIRED"
+)
+
+var (
+	apiMetadataLock sync.RWMutex
+	// apiMetadata holds the metadata of the APIs received from the control plane mapped by their UUIDs. The runtime
+	// artifact of an API revision does not reflect the lifecycle and default version changes done after the revision
+	// is created, hence they are taken from the metadata when deploying the API.
+	apiMetadata = make(map[string]types.API)
+)
+
+// UpdateAPIMetadata adds or replaces the metadata of the given APIs.
+func UpdateAPIMetadata(apis []types.API) {
+	apiMetadataLock.Lock()
+	defer apiMetadataLock.Unlock()
+	for _, api := range apis {
+		apiMetadata[api.UUID] = api
+	}
+}
+
+// SetAPILifeCycleStatus updates the lifecycle status of an API and returns its previous status, which is empty if
+// the API is not known.
+func SetAPILifeCycleStatus(apiUUID string, status string) string {
+	apiMetadataLock.Lock()
+	defer apiMetadataLock.Unlock()
+	api, exists := apiMetadata[apiUUID]
+	previousStatus := api.APIStatus
+	if !exists {
+		api = types.API{UUID: apiUUID}
+	}
+	api.APIStatus = status
+	apiMetadata[apiUUID] = api
+	return previousStatus
+}
+
+// GetAPIMetadata returns the metadata of an API if it is available.
+func GetAPIMetadata(apiUUID string) (types.API, bool) {
+	apiMetadataLock.RLock()
+	defer apiMetadataLock.RUnlock()
+	api, exists := apiMetadata[apiUUID]
+	return api, exists
+}
+
+// GetAPIVersionUUIDs returns the UUIDs of all the known versions of the API with the given UUID including itself.
+func GetAPIVersionUUIDs(apiUUID string) []string {
+	apiMetadataLock.RLock()
+	defer apiMetadataLock.RUnlock()
+	apiUUIDs := []string{apiUUID}
+	api, exists := apiMetadata[apiUUID]
+	if !exists || api.Name == "" {
+		return apiUUIDs
+	}
+	for uuid, version := range apiMetadata {
+		if uuid != apiUUID && version.Name == api.Name && version.Provider == api.Provider {
+			apiUUIDs = append(apiUUIDs, uuid)
+		}
+	}
+	return apiUUIDs
+}
+
+// IsUndeployedLifeCycleStatus returns whether the APIs in the given lifecycle status are removed from the data plane.
+func IsUndeployedLifeCycleStatus(status string) bool {
+	return strings.EqualFold(status, blockedLifeCycleStatus) || strings.EqualFold(status, retiredLifeCycleStatus)
+}
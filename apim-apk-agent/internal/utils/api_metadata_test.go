@@ -0,0 +1,57 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+)
+
+func TestAPIMetadata(t *testing.T) {
+	UpdateAPIMetadata([]types.API{
+		{UUID: "pizza-v1", Name: "PizzaShack", Provider: "admin", Version: "1.0.0", APIStatus: "PUBLISHED"},
+		{UUID: "pizza-v2", Name: "PizzaShack", Provider: "admin", Version: "2.0.0", APIStatus: "PUBLISHED",
+			IsDefaultVersion: true},
+		{UUID: "books-v1", Name: "Books", Provider: "admin", Version: "1.0.0", APIStatus: "PUBLISHED"},
+	})
+
+	apiUUIDs := GetAPIVersionUUIDs("pizza-v2")
+	sort.Strings(apiUUIDs)
+	if len(apiUUIDs) != 2 || apiUUIDs[0] != "pizza-v1" || apiUUIDs[1] != "pizza-v2" {
+		t.Errorf("Expected the versions of PizzaShack, but got %v", apiUUIDs)
+	}
+	if apiUUIDs := GetAPIVersionUUIDs("unknown"); len(apiUUIDs) != 1 || apiUUIDs[0] != "unknown" {
+		t.Errorf("Expected only the given API for an unknown API, but got %v", apiUUIDs)
+	}
+
+	if previousStatus := SetAPILifeCycleStatus("pizza-v1", "BLOCKED"); previousStatus != "PUBLISHED" {
+		t.Errorf("Expected the previous status PUBLISHED, but got %s", previousStatus)
+	}
+	api, exists := GetAPIMetadata("pizza-v1")
+	if !exists || !IsUndeployedLifeCycleStatus(api.APIStatus) || api.Name != "PizzaShack" {
+		t.Errorf("Expected PizzaShack 1.0.0 to be BLOCKED with its metadata retained, but got %+v", api)
+	}
+	if previousStatus := SetAPILifeCycleStatus("new-api", "RETIRED"); previousStatus != "" {
+		t.Errorf("Expected no previous status for an unknown API, but got %s", previousStatus)
+	}
+	if IsUndeployedLifeCycleStatus("DEPRECATED") {
+		t.Error("Expected the DEPRECATED APIs to remain deployed")
+	}
+}
@@ -26,6 +26,7 @@ package synchronizer
 import (
 	"crypto/sha1"
 	"encoding/hex"
+	"errors"
 	"fmt"
 
 	"archive/zip"
@@ -33,14 +34,24 @@ import (
 	"strings"
 
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/constants"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/loggers"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
 	sync "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/synchronizer"
 	transformer "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/transformer"
+	"gopkg.in/yaml.v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	k8sclientUtil "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/k8sClient"
 	mapperUtil "github.com/wso2/product-apim-tooling/apim-apk-agent/internal/mapper"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/notifier"
+)
+
+// Stages of the deployment of an API revision reported in the failed revision acknowledgements
+const (
+	apkConfGenerationStage = "apkConfGeneration"
+	crGenerationStage      = "crGeneration"
+	crApplyStage           = "crApply"
 )
 
 func init() {
@@ -48,14 +59,29 @@ func init() {
 	sync.InitializeWorkerPool(conf.ControlPlane.RequestWorkerPool.PoolSize, conf.ControlPlane.RequestWorkerPool.QueueSizePerPool,
 		conf.ControlPlane.RequestWorkerPool.PauseTimeAfterFailure, conf.Agent.TrustStore.Location,
 		conf.ControlPlane.SkipSSLVerification, conf.ControlPlane.HTTPClient.RequestTimeOut, conf.ControlPlane.RetryInterval,
-		conf.ControlPlane.ServiceURL, conf.ControlPlane.Username, conf.ControlPlane.Password)
+		conf.ControlPlane.ServiceURL)
+}
+
+// UpdateWorkerPool applies the changed control plane configurations to the worker pool fetching the APIs
+func UpdateWorkerPool(conf *config.Config) {
+	sync.UpdateWorkerPool(conf.ControlPlane.RequestWorkerPool.PoolSize, conf.ControlPlane.RequestWorkerPool.PauseTimeAfterFailure,
+		conf.Agent.TrustStore.Location, conf.ControlPlane.SkipSSLVerification, conf.ControlPlane.HTTPClient.RequestTimeOut,
+		conf.ControlPlane.RetryInterval, conf.ControlPlane.ServiceURL)
 }
 
 // FetchAPIsOnEvent  will fetch API from control plane during the API Notification Event
 func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Client) (*[]string, error) {
+	return FetchAndDeployAPIs(conf, apiUUID, k8sClient, nil)
+}
+
+// FetchAndDeployAPIs fetches the APIs from the control plane and deploys them in the data plane. The deployment
+// of an API revision is skipped when skipDeployment returns true for it. UUIDs of all the fetched APIs are returned
+// regardless of whether they were deployed or not, along with an error when the CRs of a revision are not applied.
+func FetchAndDeployAPIs(conf *config.Config, apiUUID *string, k8sClient client.Client,
+	skipDeployment func(apiUUID string, revisionID string) bool) (*[]string, error) {
 	// Populate data from config.
 	apis := make([]string, 0)
-	envs := conf.ControlPlane.EnvironmentLabels
+	envs := conf.GetEnvironmentLabels()
 
 	// Create a channel for the byte slice (response from the APIs from control plane)
 	c := make(chan sync.SyncAPIResponse)
@@ -102,7 +128,25 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 			}
 			apiDeployments := deploymentDescriptor.Data.Deployments
 			if apiDeployments != nil {
+				deployedRevisions := make([]*notifier.DeployedAPIRevision, 0)
+				failedRevisions := make([]*notifier.FailedAPIRevision, 0)
+				deployErrs := make([]error, 0)
 				for _, apiDeployment := range *apiDeployments {
+					if !conf.IsTenantServed(apiDeployment.OrganizationID) {
+						logger.LoggerUtils.Debugf("API file %s is skipped as the organization %s is not served",
+							apiDeployment.APIFile, apiDeployment.OrganizationID)
+						continue
+					}
+					environments := apiDeployment.Environments
+					if len(conf.Tenants) > 0 {
+						environments = filterEnvironments(apiDeployment.Environments,
+							conf.GetTenantEnvironmentLabels(apiDeployment.OrganizationID))
+						if len(*environments) == 0 {
+							logger.LoggerUtils.Debugf("API file %s is skipped as it is not deployed in the environments of the organization %s",
+								apiDeployment.APIFile, apiDeployment.OrganizationID)
+							continue
+						}
+					}
 					apiZip, exists := apiFiles[apiDeployment.APIFile]
 					if exists {
 						artifact, decodingError := transformer.DecodeAPIArtifact(apiZip)
@@ -110,19 +154,67 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 							logger.LoggerUtils.Errorf("Error while decoding the API Project Artifact: %v", decodingError)
 							return nil, err
 						}
+						validationResult := transformer.ValidateAPIArtifact(artifact, apiDeployment.OrganizationID)
+						for _, warning := range validationResult.Warnings {
+							logger.LoggerUtils.Warnf("API file %s: %s: %s", apiDeployment.APIFile, warning.Field, warning.Message)
+						}
+						if validationResult.HasErrors() {
+							logger.LoggerUtils.Errorf("API file %s is not deployed as it is not valid: %v", apiDeployment.APIFile,
+								validationResult.Err())
+							if validationResult.APIUUID != "" {
+								// The API is kept in the data plane with its previous revision
+								apis = append(apis, validationResult.APIUUID)
+								failedRevisions = append(failedRevisions, getFailedAPIRevision(validationResult, environments))
+							}
+							continue
+						}
 
 						apkConf, apiUUID, revisionID, configuredRateLimitPoliciesMap, endpointSecurityData, api, prodAIRL, sandAIRL, apkErr := transformer.GenerateAPKConf(artifact.APIJson, artifact.CertArtifact, apiDeployment.OrganizationID)
-						if prodAIRL == nil {
+						if apkErr != nil {
+							logger.LoggerUtils.Errorf("Error while generating APK-Conf: %v", apkErr)
+							apis = append(apis, validationResult.APIUUID)
+							failedRevisions = append(failedRevisions, getFailedAPIRevisionOnError(validationResult.APIUUID,
+								validationResult.RevisionID, environments, apkConfGenerationStage, apkErr))
+							continue
+						}
+						renderMode := conf.Agent.Mode == constants.RenderMode
+						if prodAIRL == nil && !renderMode {
 							// Try to delete production AI ratelimit for this api
-							k8sclientUtil.DeleteAIRatelimitPolicy(generateSHA1HexHash(api.Name, api.Version, "production"), k8sClient)
+							k8sclientUtil.DeleteAIRatelimitPolicy(generateSHA1HexHash(api.Name, api.Version, "production"),
+								conf.GetTenantNamespace(apiDeployment.OrganizationID), k8sClient)
 						}
-						if sandAIRL == nil {
+						if sandAIRL == nil && !renderMode {
 							// Try to delete production AI ratelimit for this api
-							k8sclientUtil.DeleteAIRatelimitPolicy(generateSHA1HexHash(api.Name, api.Version, "sandbox"), k8sClient)
+							k8sclientUtil.DeleteAIRatelimitPolicy(generateSHA1HexHash(api.Name, api.Version, "sandbox"),
+								conf.GetTenantNamespace(apiDeployment.OrganizationID), k8sClient)
 						}
-						if apkErr != nil {
-							logger.LoggerUtils.Errorf("Error while generating APK-Conf: %v", apkErr)
-							return nil, err
+						if metadata, exists := GetAPIMetadata(apiUUID); exists {
+							if IsUndeployedLifeCycleStatus(metadata.APIStatus) {
+								logger.LoggerUtils.Infof("API %s is not deployed as it is %s", apiUUID, metadata.APIStatus)
+								if !renderMode {
+									if err := k8sclientUtil.UndeployAPICR(apiUUID, k8sClient); err != nil {
+										deployErrs = append(deployErrs, fmt.Errorf("API %s is not undeployed: %w", apiUUID, err))
+									}
+								}
+								apis = append(apis, apiUUID)
+								continue
+							}
+							if metadata.Name != "" && metadata.IsDefaultVersion != api.DefaultVersion {
+								logger.LoggerUtils.Infof("Default version of the API %s is changed to %v after the revision %v",
+									apiUUID, metadata.IsDefaultVersion, revisionID)
+								api.DefaultVersion = metadata.IsDefaultVersion
+								apkConfContent, marshalErr := yaml.Marshal(api)
+								if marshalErr != nil {
+									logger.LoggerUtils.Errorf("Error while marshalling the APK-Conf: %v", marshalErr)
+									return nil, marshalErr
+								}
+								apkConf = string(apkConfContent)
+							}
+						}
+						if skipDeployment != nil && skipDeployment(apiUUID, fmt.Sprint(revisionID)) {
+							logger.LoggerUtils.Debugf("Deployment of the API %s revision %v is skipped", apiUUID, revisionID)
+							apis = append(apis, apiUUID)
+							continue
 						}
 						logger.LoggerUtils.Debugf("APK Conf: %v", apkConf)
 						certContainer := transformer.CertContainer{
@@ -130,19 +222,47 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 							EndpointCertObj: artifact.EndpointCertMeta,
 							SecretData:      endpointSecurityData,
 						}
-						k8ResourceEndpoint := conf.DataPlane.K8ResourceEndpoint
-						crResponse, err := transformer.GenerateCRs(apkConf, artifact.Schema, certContainer, k8ResourceEndpoint, apiDeployment.OrganizationID)
+						crResponse, err := generateCRs(conf, apkConf, api, artifact.Schema, certContainer,
+							apiDeployment.OrganizationID, *environments)
 						if err != nil {
 							logger.LoggerUtils.Errorf("Error occured in receiving the updated CRDs: %v", err)
-							return nil, err
+							apis = append(apis, apiUUID)
+							failedRevisions = append(failedRevisions, getFailedAPIRevisionOnError(apiUUID, revisionID,
+								environments, crGenerationStage, err))
+							continue
+						}
+						transformer.UpdateCRS(crResponse, environments, apiDeployment.OrganizationID, apiUUID, fmt.Sprint(revisionID),
+							conf.GetTenantNamespace(apiDeployment.OrganizationID), configuredRateLimitPoliciesMap)
+						if renderMode {
+							if err := mapperUtil.RenderCRs(*crResponse, conf.Agent.RenderDirectory); err != nil {
+								logger.LoggerUtils.Errorf("Error while rendering the CRs of the API %s: %v", apiUUID, err)
+								return nil, err
+							}
+							apis = append(apis, apiUUID)
+							continue
 						}
-						transformer.UpdateCRS(crResponse, apiDeployment.Environments, apiDeployment.OrganizationID, apiUUID, fmt.Sprint(revisionID), "namespace", configuredRateLimitPoliciesMap)
-						mapperUtil.MapAndCreateCR(*crResponse, k8sClient)
+						deploymentResult := mapperUtil.MapAndCreateCR(*crResponse, k8sClient)
 						apis = append(apis, apiUUID)
+						if deployErr := deploymentResult.Err(); deployErr != nil {
+							logger.LoggerUtils.Errorf("API %s revision %v is not applied. Rolled back: %v, Rollback error: %v, Error: %v",
+								apiUUID, revisionID, deploymentResult.RolledBack, deploymentResult.RollbackError, deployErr)
+							failedRevisions = append(failedRevisions, getFailedAPIRevisionOnError(apiUUID, revisionID,
+								environments, crApplyStage, deployErr))
+							deployErrs = append(deployErrs, fmt.Errorf("API %s revision %v is not applied: %w", apiUUID,
+								revisionID, deployErr))
+							continue
+						}
+						deployedRevisions = append(deployedRevisions,
+							getDeployedAPIRevision(apiUUID, revisionID, environments))
 						logger.LoggerUtils.Info("API applied successfully.\n")
 					}
 				}
-				return &apis, nil
+				// The CRs rendered to files are applied outside of the agent, hence the revisions are not acknowledged
+				if conf.Agent.Mode != constants.RenderMode {
+					notifier.SendRevisionUpdateAck(deployedRevisions)
+					notifier.SendRevisionDeploymentFailureAck(failedRevisions)
+				}
+				return &apis, errors.Join(deployErrs...)
 			}
 		} else {
 			logger.LoggerUtils.Info("API not found.")
@@ -168,11 +288,101 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 		})
 		//health.SetControlPlaneRestAPIStatus(false)
 		sync.RetryFetchingAPIs(c, data, sync.RuntimeArtifactEndpoint, true)
+		return nil, data.Err
 	}
 	logger.LoggerUtils.Info("Fetching API for an event is completed...")
 	return nil, nil
 }
 
+// getDeployedAPIRevision returns the acknowledgement of an API revision deployed in the given environments
+func getDeployedAPIRevision(apiUUID string, revisionID uint32, environments *[]transformer.Environment) *notifier.DeployedAPIRevision {
+	deployedRevision := &notifier.DeployedAPIRevision{
+		APIID:      apiUUID,
+		RevisionID: int(revisionID),
+		EnvInfo:    []notifier.DeployedEnvInfo{},
+	}
+	if environments == nil {
+		return deployedRevision
+	}
+	for _, environment := range *environments {
+		deployedRevision.EnvInfo = append(deployedRevision.EnvInfo, notifier.DeployedEnvInfo{
+			Name:  environment.Name,
+			VHost: environment.Vhost,
+		})
+	}
+	return deployedRevision
+}
+
+// getFailedAPIRevision returns the acknowledgement of an API revision which is not deployed in the given environments
+// due to the errors found while validating it
+func getFailedAPIRevision(validationResult *transformer.ValidationResult,
+	environments *[]transformer.Environment) *notifier.FailedAPIRevision {
+	deployedRevision := getDeployedAPIRevision(validationResult.APIUUID, validationResult.RevisionID, environments)
+	failedRevision := &notifier.FailedAPIRevision{
+		APIID:      deployedRevision.APIID,
+		RevisionID: deployedRevision.RevisionID,
+		EnvInfo:    deployedRevision.EnvInfo,
+		Errors:     make([]notifier.DeploymentIssue, 0, len(validationResult.Errors)),
+		Warnings:   make([]notifier.DeploymentIssue, 0, len(validationResult.Warnings)),
+	}
+	for _, validationError := range validationResult.Errors {
+		failedRevision.Errors = append(failedRevision.Errors, notifier.DeploymentIssue(validationError))
+	}
+	for _, validationWarning := range validationResult.Warnings {
+		failedRevision.Warnings = append(failedRevision.Warnings, notifier.DeploymentIssue(validationWarning))
+	}
+	return failedRevision
+}
+
+// getFailedAPIRevisionOnError returns the acknowledgement of an API revision which is not deployed in the given
+// environments due to the error at the given stage of the deployment
+func getFailedAPIRevisionOnError(apiUUID string, revisionID uint32, environments *[]transformer.Environment,
+	stage string, err error) *notifier.FailedAPIRevision {
+	return getFailedAPIRevision(&transformer.ValidationResult{
+		APIUUID:    apiUUID,
+		RevisionID: revisionID,
+		Errors:     []transformer.ValidationIssue{{Field: stage, Message: err.Error()}},
+	}, environments)
+}
+
+// filterEnvironments returns the environments whose names are in the given environment labels
+func filterEnvironments(environments *[]transformer.Environment, environmentLabels []string) *[]transformer.Environment {
+	filteredEnvironments := make([]transformer.Environment, 0)
+	if environments == nil {
+		return &filteredEnvironments
+	}
+	for _, environment := range *environments {
+		for _, environmentLabel := range environmentLabels {
+			if environment.Name == environmentLabel {
+				filteredEnvironments = append(filteredEnvironments, environment)
+				break
+			}
+		}
+	}
+	return &filteredEnvironments
+}
+
+// generateCRs generates the CRs for an API within the agent when local CR generation is enabled and uses the config
+// generator service at K8ResourceEndpoint otherwise or when the API has configurations the agent cannot generate.
+func generateCRs(conf *config.Config, apkConf string, api *transformer.API, apiDefinition string,
+	certContainer transformer.CertContainer, organizationID string,
+	environments []transformer.Environment) (*transformer.K8sArtifacts, error) {
+	k8ResourceEndpoint := conf.DataPlane.K8ResourceEndpoint
+	if conf.DataPlane.LocalCRGeneration {
+		crResponse, err := transformer.GenerateCRsLocally(api, apiDefinition, certContainer, organizationID,
+			environments)
+		if err == nil {
+			return crResponse, nil
+		}
+		if k8ResourceEndpoint == "" || !errors.Is(err, transformer.ErrUnsupportedByLocalGenerator) {
+			return nil, err
+		}
+		logger.LoggerUtils.Warnf("Unable to generate the CRs locally for the API %s:%s, hence using the config generator service: %v",
+			api.Name, api.Version, err)
+	}
+	return transformer.GenerateCRs(apkConf, apiDefinition, certContainer, k8ResourceEndpoint, organizationID)
+}
+
 // generateSHA1HexHash hashes the concatenated strings and returns the SHA-1 hash in base16 (hex) encoding.
 func generateSHA1HexHash(name, version, env string) string {
 	data := name + version + env
@@ -0,0 +1,72 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/transformer"
+)
+
+func TestGetFailedAPIRevision(t *testing.T) {
+	validationResult := &transformer.ValidationResult{
+		APIUUID:    "pizza-v1",
+		RevisionID: 3,
+		Errors:     []transformer.ValidationIssue{{Field: "securityScheme", Message: "security scheme \"basic_auth\" is not supported"}},
+		Warnings:   []transformer.ValidationIssue{},
+	}
+	environments := []transformer.Environment{{Name: "Default", Vhost: "gw.wso2.com"}}
+
+	failedRevision := getFailedAPIRevision(validationResult, &environments)
+	if failedRevision.APIID != "pizza-v1" || failedRevision.RevisionID != 3 {
+		t.Errorf("Expected the revision 3 of pizza-v1, but got the revision %d of %s", failedRevision.RevisionID,
+			failedRevision.APIID)
+	}
+	if len(failedRevision.EnvInfo) != 1 || failedRevision.EnvInfo[0].VHost != "gw.wso2.com" {
+		t.Errorf("Expected the Default environment, but got %v", failedRevision.EnvInfo)
+	}
+	if len(failedRevision.Errors) != 1 || failedRevision.Errors[0].Field != "securityScheme" {
+		t.Errorf("Expected the security scheme error, but got %v", failedRevision.Errors)
+	}
+	if failedRevision.Warnings == nil || len(failedRevision.Warnings) != 0 {
+		t.Errorf("Expected no warnings, but got %v", failedRevision.Warnings)
+	}
+}
+
+func TestGetFailedAPIRevisionOnError(t *testing.T) {
+	failedRevision := getFailedAPIRevisionOnError("pizza-v1", 4, nil, crApplyStage, errors.New("conflict"))
+	if failedRevision.APIID != "pizza-v1" || failedRevision.RevisionID != 4 || len(failedRevision.EnvInfo) != 0 {
+		t.Errorf("Expected the revision 4 of pizza-v1 without environments, but got %v", failedRevision)
+	}
+	if len(failedRevision.Errors) != 1 || failedRevision.Errors[0].Field != crApplyStage ||
+		failedRevision.Errors[0].Message != "conflict" {
+		t.Errorf("Expected the CR apply error, but got %v", failedRevision.Errors)
+	}
+}
+
+func TestGenerateCRsFallsBackOnlyForUnsupportedConfigurations(t *testing.T) {
+	conf := &config.Config{}
+	conf.DataPlane.LocalCRGeneration = true
+	conf.DataPlane.K8ResourceEndpoint = "http://127.0.0.1:1/generate-k8s-resources"
+	_, err := generateCRs(conf, "", &transformer.API{Name: "TestAPI"}, "", transformer.CertContainer{}, "default", nil)
+	if err == nil || err.Error() != "Error: API Definition can't be empty" {
+		t.Errorf("Expected the error of the local CR generator, but got %v", err)
+	}
+}
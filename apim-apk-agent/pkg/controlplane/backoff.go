@@ -0,0 +1,46 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package controlplane
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the intervals between the retries of a failing request. The interval starts from Initial, doubles
+// after each failure up to Max and is randomized by the Jitter fraction.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Jitter  float64
+}
+
+// Delay returns the interval before the next retry after the given number of consecutive failures
+func (b Backoff) Delay(failures int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < failures && (b.Max <= 0 || delay < b.Max); i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	if b.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * b.Jitter * float64(delay))
+	}
+	return delay
+}
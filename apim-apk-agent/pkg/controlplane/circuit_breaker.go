@@ -0,0 +1,142 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package controlplane
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/health"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+)
+
+// circuitBreaker stops sending the requests to the control plane for a while after consecutive failures so that an
+// unavailable control plane is not overloaded. Its state is reported through the health service.
+type circuitBreaker struct {
+	lock             sync.Mutex
+	failureThreshold int
+	openDuration     time.Duration
+	state            string
+	failures         int
+	openedAt         time.Time
+	trialInFlight    bool
+	now              func() time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+		state:            health.CircuitClosed,
+		now:              time.Now,
+	}
+}
+
+// configure changes the failure threshold and the open duration of the circuit breaker. The circuit is closed when
+// the circuit breaker is disabled.
+func (cb *circuitBreaker) configure(failureThreshold int, openDuration time.Duration) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	cb.failureThreshold = failureThreshold
+	cb.openDuration = openDuration
+	if failureThreshold <= 0 {
+		cb.failures = 0
+		cb.trialInFlight = false
+		cb.setState(health.CircuitClosed)
+	}
+}
+
+// allow returns whether a request can be sent. Only a single trial request is allowed at a time once the open
+// duration has elapsed.
+func (cb *circuitBreaker) allow() bool {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if cb.failureThreshold <= 0 {
+		return true
+	}
+	switch cb.state {
+	case health.CircuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.openDuration {
+			return false
+		}
+		cb.setState(health.CircuitHalfOpen)
+		cb.trialInFlight = true
+		return true
+	case health.CircuitHalfOpen:
+		if cb.trialInFlight {
+			return false
+		}
+		cb.trialInFlight = true
+		return true
+	}
+	return true
+}
+
+// record updates the state of the circuit with the result of a request which was allowed
+func (cb *circuitBreaker) record(succeeded bool) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if cb.failureThreshold <= 0 {
+		return
+	}
+	cb.trialInFlight = false
+	if succeeded {
+		cb.failures = 0
+		cb.setState(health.CircuitClosed)
+		return
+	}
+	cb.failures++
+	if cb.state == health.CircuitHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.openedAt = cb.now()
+		cb.setState(health.CircuitOpen)
+	}
+}
+
+// remainingOpenDuration returns the time until a trial request is allowed
+func (cb *circuitBreaker) remainingOpenDuration() time.Duration {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	if cb.state != health.CircuitOpen {
+		return 0
+	}
+	remaining := cb.openDuration - cb.now().Sub(cb.openedAt)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (cb *circuitBreaker) getState() string {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	return cb.state
+}
+
+func (cb *circuitBreaker) setState(state string) {
+	if cb.state == state {
+		return
+	}
+	if state == health.CircuitOpen {
+		logger.LoggerControlPlane.Warnf("Control plane requests are paused for %v after %d consecutive failures",
+			cb.openDuration, cb.failures)
+	} else if state == health.CircuitClosed {
+		logger.LoggerControlPlane.Info("Control plane requests are resumed")
+	}
+	cb.state = state
+	health.SetControlPlaneCircuitState(state)
+}
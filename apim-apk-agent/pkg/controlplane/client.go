@@ -0,0 +1,303 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package controlplane contains the client shared by the requests sent to the control plane. It backs off the
+// retries of the failing requests, limits the retries of each endpoint and stops sending the requests for a while
+// when the control plane keeps failing.
+package controlplane
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit breaker of the control plane is open
+var ErrCircuitOpen = errors.New("control plane requests are paused as the control plane is unavailable")
+
+// Window of the retry budgets of the endpoints
+const retryBudgetWindow = time.Minute
+
+// Client sends the requests to the control plane through a circuit breaker and keeps track of the failures and the
+// retries of each control plane endpoint, which is identified by its resource name.
+type Client struct {
+	skipSSL         bool
+	backoff         Backoff
+	breaker         *circuitBreaker
+	budgetPerMinute int
+	lock            sync.Mutex
+	endpoints       map[string]*endpointState
+	tokens          *tokenProvider
+}
+
+// endpointState holds the consecutive failures and the recent retries of the requests to an endpoint
+type endpointState struct {
+	failures int
+	retries  []time.Time
+}
+
+var (
+	client           *Client
+	onceClientLoaded sync.Once
+)
+
+// GetClient returns the control plane client configured with the control plane configurations
+func GetClient() *Client {
+	onceClientLoaded.Do(func() {
+		conf, _ := config.ReadConfigs()
+		client = NewClient(getClientConfigs(conf))
+		setCredentials(client, conf)
+	})
+	return client
+}
+
+// UpdateClient applies the changed control plane configurations to the shared client. The failures and the
+// retries recorded so far are kept.
+func UpdateClient(conf *config.Config) {
+	GetClient().Configure(getClientConfigs(conf))
+	setCredentials(GetClient(), conf)
+}
+
+// setCredentials sets the credentials of the control plane configurations to the client. The requests of the client
+// fail when the credentials are not configured.
+func setCredentials(c *Client, conf *config.Config) {
+	credentials, err := getCredentials(conf)
+	if err != nil {
+		logger.LoggerControlPlane.Errorf("The control plane requests are not sent as the credentials are invalid: %v",
+			err)
+		c.tokens.setCredentialsError(err)
+		return
+	}
+	c.SetCredentials(credentials)
+}
+
+// getClientConfigs returns the arguments of NewClient from the control plane configurations
+func getClientConfigs(conf *config.Config) (bool, Backoff, int, int, time.Duration) {
+	cpConfigs := conf.ControlPlane
+	initialInterval := cpConfigs.RetryInterval
+	if initialInterval <= 0 {
+		initialInterval = 5
+	}
+	backoff := Backoff{
+		Initial: initialInterval * time.Second,
+		Max:     cpConfigs.Retry.MaxInterval * time.Second,
+		Jitter:  cpConfigs.Retry.Jitter,
+	}
+	return cpConfigs.SkipSSLVerification, backoff, cpConfigs.Retry.BudgetPerMinute,
+		cpConfigs.CircuitBreaker.FailureThreshold, cpConfigs.CircuitBreaker.OpenDuration * time.Second
+}
+
+// getCredentials returns the credentials of the control plane requests from the control plane configurations. The
+// basic authentication is only used when it is configured or the fallback to it is enabled.
+func getCredentials(conf *config.Config) (Credentials, error) {
+	cpConfigs := conf.ControlPlane
+	authConfigs := cpConfigs.Authentication
+	authType := authConfigs.Type
+	if authType == "" {
+		if cpConfigs.ClientID != "" && cpConfigs.ClientSecret != "" {
+			authType = ClientCredentialsAuthentication
+		} else if authConfigs.BasicAuthFallback {
+			authType = BasicAuthentication
+			logger.LoggerControlPlane.Warn("The basic authentication is used for the control plane requests as the " +
+				"client ID and the client secret are not configured")
+		} else {
+			return Credentials{}, errors.New("the client ID and the client secret are not configured, set the " +
+				"authentication type to Basic or enable basicAuthFallback to use the username and the password")
+		}
+	}
+	tokenEndpoint := authConfigs.TokenEndpoint
+	if tokenEndpoint == "" {
+		tokenEndpoint = strings.TrimSuffix(cpConfigs.ServiceURL, "/") + "/oauth2/token"
+	}
+	return Credentials{
+		Type:                authType,
+		Username:            cpConfigs.Username,
+		Password:            cpConfigs.Password,
+		ClientID:            cpConfigs.ClientID,
+		ClientSecret:        cpConfigs.ClientSecret,
+		TokenEndpoint:       tokenEndpoint,
+		Scopes:              authConfigs.Scopes,
+		AssertionPath:       authConfigs.AssertionPath,
+		RefreshBeforeExpiry: authConfigs.RefreshBeforeExpiry * time.Second,
+		BasicAuthFallback:   authConfigs.BasicAuthFallback,
+	}, nil
+}
+
+// NewClient creates a control plane client. The retries of an endpoint are not limited when budgetPerMinute is 0 and
+// the circuit breaker is disabled when failureThreshold is 0. The requests are not authenticated until the
+// credentials are set.
+func NewClient(skipSSL bool, backoff Backoff, budgetPerMinute int, failureThreshold int,
+	openDuration time.Duration) *Client {
+	c := &Client{
+		breaker:   newCircuitBreaker(failureThreshold, openDuration),
+		endpoints: make(map[string]*endpointState),
+	}
+	c.tokens = newTokenProvider(func(req *http.Request) (*http.Response, error) {
+		return c.send(tokenResource, req, c.invokeControlPlane)
+	})
+	c.Configure(skipSSL, backoff, budgetPerMinute, failureThreshold, openDuration)
+	return c
+}
+
+// Configure changes the configurations of the client. The arguments are the same as of NewClient.
+func (c *Client) Configure(skipSSL bool, backoff Backoff, budgetPerMinute int, failureThreshold int,
+	openDuration time.Duration) {
+	if backoff.Max < backoff.Initial {
+		backoff.Max = backoff.Initial
+	}
+	c.lock.Lock()
+	c.skipSSL = skipSSL
+	c.backoff = backoff
+	c.budgetPerMinute = budgetPerMinute
+	c.lock.Unlock()
+	c.breaker.configure(failureThreshold, openDuration)
+}
+
+// SetCredentials changes the credentials the requests are authenticated with. The cached access token is dropped
+// when they change.
+func (c *Client) SetCredentials(credentials Credentials) {
+	c.tokens.setCredentials(credentials)
+}
+
+// Do authenticates and sends the request for the resource to the control plane
+func (c *Client) Do(resource string, req *http.Request) (*http.Response, error) {
+	return c.sendAuthenticated(resource, req, c.invokeControlPlane)
+}
+
+// DoWithHTTPClient authenticates and sends the request for the resource to the control plane with the given HTTP
+// client
+func (c *Client) DoWithHTTPClient(resource string, req *http.Request, httpClient *http.Client) (*http.Response, error) {
+	return c.sendAuthenticated(resource, req, httpClient.Do)
+}
+
+func (c *Client) invokeControlPlane(req *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	skipSSL := c.skipSSL
+	c.lock.Unlock()
+	return tlsutils.InvokeControlPlane(req, skipSSL)
+}
+
+// sendAuthenticated sets the Authorization header of the request before sending it. The cached access token is
+// dropped when the control plane rejects it.
+func (c *Client) sendAuthenticated(resource string, req *http.Request,
+	invoke func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if err := c.tokens.authorize(req); err != nil {
+		c.recordResult(resource, false)
+		return nil, err
+	}
+	resp, err := c.send(resource, req, invoke)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		c.tokens.invalidate()
+	}
+	return resp, err
+}
+
+func (c *Client) send(resource string, req *http.Request,
+	invoke func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if !c.breaker.allow() {
+		c.recordResult(resource, false)
+		return nil, ErrCircuitOpen
+	}
+	resp, err := invoke(req)
+	// Only the unavailability of the control plane opens the circuit. The other error responses are specific to
+	// the requests.
+	c.breaker.record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+	c.recordResult(resource, err == nil && resp.StatusCode < http.StatusBadRequest)
+	return resp, err
+}
+
+func (c *Client) recordResult(resource string, succeeded bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	endpoint := c.getEndpoint(resource)
+	if succeeded {
+		endpoint.failures = 0
+	} else {
+		endpoint.failures++
+	}
+}
+
+// WaitBeforeRetry blocks until the request for the resource can be retried
+func (c *Client) WaitBeforeRetry(resource string) {
+	delay := c.RetryDelay(resource)
+	logger.LoggerControlPlane.Debugf("Retrying the request for %s in %v", resource, delay)
+	time.Sleep(delay)
+}
+
+// RemainingPause returns the time the control plane requests are failed without being sent as the circuit is open
+func (c *Client) RemainingPause() time.Duration {
+	return c.breaker.remainingOpenDuration()
+}
+
+// RetryDelay reserves a retry of the request for the resource and returns the time to wait before it. The delay
+// grows exponentially with the consecutive failures of the resource, lasts at least until the circuit breaker allows
+// a trial request and is extended until the next minute when the retry budget of the resource is used up.
+func (c *Client) RetryDelay(resource string) time.Duration {
+	delay := c.breaker.remainingOpenDuration()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	endpoint := c.getEndpoint(resource)
+	if backoffDelay := c.backoff.Delay(endpoint.failures); backoffDelay > delay {
+		delay = backoffDelay
+	}
+	if c.budgetPerMinute <= 0 {
+		return delay
+	}
+	retryAt := time.Now().Add(delay)
+	// Drop the retries which are out of the window of the reserved one
+	for len(endpoint.retries) > 0 && !endpoint.retries[0].After(retryAt.Add(-retryBudgetWindow)) {
+		endpoint.retries = endpoint.retries[1:]
+	}
+	if len(endpoint.retries) >= c.budgetPerMinute {
+		budgetRetryAt := endpoint.retries[len(endpoint.retries)-c.budgetPerMinute].Add(retryBudgetWindow)
+		logger.LoggerControlPlane.Debugf("Retry budget of %s is used up until %v", resource, budgetRetryAt)
+		delay += budgetRetryAt.Sub(retryAt)
+		retryAt = budgetRetryAt
+	}
+	i := sort.Search(len(endpoint.retries), func(i int) bool { return endpoint.retries[i].After(retryAt) })
+	endpoint.retries = slices.Insert(endpoint.retries, i, retryAt)
+	return delay
+}
+
+// GetBackoff returns the backoff of the retries of the control plane requests
+func (c *Client) GetBackoff() Backoff {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.backoff
+}
+
+// GetCircuitState returns the state of the circuit breaker of the control plane requests
+func (c *Client) GetCircuitState() string {
+	return c.breaker.getState()
+}
+
+func (c *Client) getEndpoint(resource string) *endpointState {
+	endpoint, found := c.endpoints[resource]
+	if !found {
+		endpoint = &endpointState{}
+		c.endpoints[resource] = endpoint
+	}
+	return endpoint
+}
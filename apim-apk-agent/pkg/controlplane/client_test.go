@@ -0,0 +1,444 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package controlplane
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/health"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{Initial: time.Second, Max: 10 * time.Second}
+	assert.Equal(t, time.Second, backoff.Delay(0))
+	assert.Equal(t, time.Second, backoff.Delay(1))
+	assert.Equal(t, 2*time.Second, backoff.Delay(2))
+	assert.Equal(t, 8*time.Second, backoff.Delay(4))
+	assert.Equal(t, 10*time.Second, backoff.Delay(5))
+	assert.Equal(t, 10*time.Second, backoff.Delay(100))
+
+	backoff.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		delay := backoff.Delay(3)
+		assert.GreaterOrEqual(t, delay, 3200*time.Millisecond)
+		assert.LessOrEqual(t, delay, 4800*time.Millisecond)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker(2, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	assert.True(t, breaker.allow())
+	breaker.record(false)
+	assert.Equal(t, health.CircuitClosed, breaker.getState())
+	assert.True(t, breaker.allow())
+	breaker.record(false)
+	assert.Equal(t, health.CircuitOpen, breaker.getState())
+	assert.Equal(t, health.CircuitOpen, health.GetControlPlaneCircuitState())
+	assert.False(t, breaker.allow())
+	assert.Equal(t, 30*time.Second, breaker.remainingOpenDuration())
+
+	// A single trial request is allowed once the open duration has elapsed
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, time.Duration(0), breaker.remainingOpenDuration())
+	assert.True(t, breaker.allow())
+	assert.Equal(t, health.CircuitHalfOpen, breaker.getState())
+	assert.False(t, breaker.allow())
+	breaker.record(false)
+	assert.Equal(t, health.CircuitOpen, breaker.getState())
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, breaker.allow())
+	breaker.record(true)
+	assert.Equal(t, health.CircuitClosed, breaker.getState())
+	assert.Equal(t, health.CircuitClosed, health.GetControlPlaneCircuitState())
+	assert.True(t, breaker.allow())
+}
+
+func TestDisabledCircuitBreaker(t *testing.T) {
+	breaker := newCircuitBreaker(0, 30*time.Second)
+	for i := 0; i < 10; i++ {
+		assert.True(t, breaker.allow())
+		breaker.record(false)
+	}
+	assert.Equal(t, health.CircuitClosed, breaker.getState())
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := NewClient(false, Backoff{Initial: time.Second, Max: 4 * time.Second}, 2, 0, 0)
+	client.recordResult("apis", false)
+	assert.Equal(t, time.Second, client.RetryDelay("apis"))
+	client.recordResult("apis", false)
+	assert.Equal(t, 2*time.Second, client.RetryDelay("apis"))
+
+	// The retry budget of the minute is used up
+	client.recordResult("apis", false)
+	delay := client.RetryDelay("apis")
+	assert.Greater(t, delay, 55*time.Second)
+	assert.LessOrEqual(t, delay, 61*time.Second)
+
+	// The budgets and the backoffs of the endpoints are separate
+	assert.Equal(t, time.Second, client.RetryDelay("keymanagers"))
+	client.recordResult("apis", true)
+	assert.Equal(t, 0, client.endpoints["apis"].failures)
+}
+
+func TestClientOpensCircuitWhenControlPlaneIsUnavailable(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(false, Backoff{Initial: time.Second}, 0, 3, time.Minute)
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.DoWithHTTPClient("apis", req, server.Client())
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		resp.Body.Close()
+	}
+	assert.Equal(t, health.CircuitOpen, client.GetCircuitState())
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.DoWithHTTPClient("apis", req, server.Client())
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, requests)
+	assert.Greater(t, client.RemainingPause(), 55*time.Second)
+	assert.Greater(t, client.RetryDelay("apis"), 55*time.Second)
+}
+
+func TestClientErrorResponsesDoNotOpenCircuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(false, Backoff{Initial: time.Second}, 0, 1, time.Minute)
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.DoWithHTTPClient("apis", req, server.Client())
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, health.CircuitClosed, client.GetCircuitState())
+	assert.Equal(t, 3, client.endpoints["apis"].failures)
+}
+
+func TestClientConfigure(t *testing.T) {
+	client := NewClient(false, Backoff{Initial: time.Second}, 0, 1, time.Minute)
+	client.recordResult("apis", false)
+	client.breaker.record(false)
+	assert.Equal(t, health.CircuitOpen, client.GetCircuitState())
+
+	// The failures are kept while the new backoff applies to the next retries
+	client.Configure(true, Backoff{Initial: 2 * time.Second, Max: time.Second}, 0, 1, time.Minute)
+	assert.Equal(t, 2*time.Second, client.GetBackoff().Max)
+	assert.Equal(t, 1, client.endpoints["apis"].failures)
+	assert.Equal(t, health.CircuitOpen, client.GetCircuitState())
+
+	// Disabling the circuit breaker resumes the requests
+	client.Configure(true, Backoff{Initial: 2 * time.Second}, 0, 0, time.Minute)
+	assert.Equal(t, health.CircuitClosed, client.GetCircuitState())
+	assert.True(t, client.breaker.allow())
+	assert.Equal(t, 2*time.Second, client.RetryDelay("apis"))
+}
+
+// newTokenServer returns a control plane which issues numbered access tokens and accepts the requests with the last
+// issued token. The form of each token request is passed to checkTokenRequest.
+func newTokenServer(t *testing.T, expiresIn int, checkTokenRequest func(*http.Request)) (*httptest.Server, *int) {
+	issued := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token" {
+			assert.NoError(t, r.ParseForm())
+			checkTokenRequest(r)
+			issued++
+			fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, issued, expiresIn)
+			return
+		}
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", issued) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	return server, &issued
+}
+
+func sendToControlPlane(t *testing.T, client *Client, url string) int {
+	req, _ := http.NewRequest(http.MethodGet, url+"/apis", nil)
+	resp, err := client.Do("apis", req)
+	if !assert.NoError(t, err) {
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestClientCredentialsToken(t *testing.T) {
+	server, issued := newTokenServer(t, 120, func(r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		assert.Equal(t, clientCredentialsGrant, r.PostForm.Get("grant_type"))
+		assert.Equal(t, "apim:admin apim:api_import_export", r.PostForm.Get("scope"))
+	})
+	defer server.Close()
+
+	now := time.Now()
+	client := NewClient(true, Backoff{Initial: time.Second}, 0, 0, time.Minute)
+	client.tokens.now = func() time.Time { return now }
+	client.SetCredentials(Credentials{
+		Type:                ClientCredentialsAuthentication,
+		ClientID:            "client",
+		ClientSecret:        "secret",
+		TokenEndpoint:       server.URL + "/oauth2/token",
+		Scopes:              []string{"apim:admin", "apim:api_import_export"},
+		RefreshBeforeExpiry: time.Minute,
+	})
+
+	// The token is cached until it is about to expire
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, 1, *issued)
+	now = now.Add(59 * time.Second)
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, 1, *issued)
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, 2, *issued)
+
+	// Changing the credentials drops the cached token
+	client.SetCredentials(Credentials{
+		Type:                ClientCredentialsAuthentication,
+		ClientID:            "client",
+		ClientSecret:        "secret",
+		TokenEndpoint:       server.URL + "/oauth2/token",
+		Scopes:              []string{"apim:admin", "apim:api_import_export"},
+		RefreshBeforeExpiry: 2 * time.Minute,
+	})
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, 3, *issued)
+}
+
+func TestJWTBearerToken(t *testing.T) {
+	assertionPath := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(assertionPath, []byte("assertion-1\n"), 0600))
+	var assertions []string
+	server, issued := newTokenServer(t, 0, func(r *http.Request) {
+		assert.Equal(t, jwtBearerGrant, r.PostForm.Get("grant_type"))
+		assertions = append(assertions, r.PostForm.Get("assertion"))
+	})
+	defer server.Close()
+
+	client := NewClient(true, Backoff{Initial: time.Second}, 0, 0, time.Minute)
+	client.SetCredentials(Credentials{
+		Type:          JWTBearerAuthentication,
+		TokenEndpoint: server.URL + "/oauth2/token",
+		AssertionPath: assertionPath,
+	})
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+
+	// A token without an expiry is used until it is rejected, and the rotated assertion is read for the next token
+	assert.NoError(t, os.WriteFile(assertionPath, []byte("assertion-2"), 0600))
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, 1, *issued)
+	*issued++
+	assert.Equal(t, http.StatusUnauthorized, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, []string{"assertion-1", "assertion-2"}, assertions)
+}
+
+func TestBasicAuthFallback(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	credentials := Credentials{
+		Type:          ClientCredentialsAuthentication,
+		Username:      "admin",
+		Password:      "admin",
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		TokenEndpoint: server.URL + "/oauth2/token",
+	}
+	client := NewClient(true, Backoff{Initial: time.Second}, 0, 0, time.Minute)
+	client.SetCredentials(credentials)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/apis", nil)
+	resp, err := client.Do("apis", req)
+	assert.Nil(t, resp)
+	assert.Error(t, err)
+	assert.Empty(t, authorization)
+	assert.Equal(t, 1, client.endpoints["apis"].failures)
+
+	credentials.BasicAuthFallback = true
+	client.SetCredentials(credentials)
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, "Basic YWRtaW46YWRtaW4=", authorization)
+}
+
+func TestConcurrentRequestsShareTokenRequest(t *testing.T) {
+	var issued atomic.Int32
+	tokenRequested := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token" {
+			issued.Add(1)
+			select {
+			case tokenRequested <- struct{}{}:
+			default:
+			}
+			<-release
+			fmt.Fprint(w, `{"access_token":"token-1","token_type":"Bearer","expires_in":3600}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	credentials := Credentials{
+		Type:          ClientCredentialsAuthentication,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		TokenEndpoint: server.URL + "/oauth2/token",
+	}
+	client := NewClient(true, Backoff{Initial: time.Second}, 0, 0, time.Minute)
+	client.SetCredentials(credentials)
+	var wg sync.WaitGroup
+	statusCodes := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statusCodes <- sendToControlPlane(t, client, server.URL)
+		}()
+	}
+
+	// The lock is not held while the token is requested
+	<-tokenRequested
+	client.SetCredentials(credentials)
+	close(release)
+	wg.Wait()
+	close(statusCodes)
+	for statusCode := range statusCodes {
+		assert.Equal(t, http.StatusOK, statusCode)
+	}
+	assert.Equal(t, int32(1), issued.Load())
+}
+
+func TestTokenRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(true, Backoff{Initial: time.Second}, 0, 0, time.Minute)
+	client.tokens.requestTimeout = 50 * time.Millisecond
+	client.SetCredentials(Credentials{
+		Type:          ClientCredentialsAuthentication,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		TokenEndpoint: server.URL + "/oauth2/token",
+	})
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/apis", nil)
+	start := time.Now()
+	_, err := client.Do("apis", req)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestGetCredentials(t *testing.T) {
+	conf := &config.Config{}
+	conf.ControlPlane.ServiceURL = "https://apim:9443/"
+	conf.ControlPlane.Authentication.RefreshBeforeExpiry = 60
+	// The basic authentication is not used unless it is configured
+	_, err := getCredentials(conf)
+	assert.Error(t, err)
+
+	conf.ControlPlane.Authentication.BasicAuthFallback = true
+	credentials, err := getCredentials(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, BasicAuthentication, credentials.Type)
+	assert.Equal(t, "https://apim:9443/oauth2/token", credentials.TokenEndpoint)
+	assert.Equal(t, time.Minute, credentials.RefreshBeforeExpiry)
+
+	conf.ControlPlane.Authentication.BasicAuthFallback = false
+	conf.ControlPlane.Authentication.Type = BasicAuthentication
+	credentials, err = getCredentials(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, BasicAuthentication, credentials.Type)
+
+	conf.ControlPlane.Authentication.Type = ""
+	conf.ControlPlane.ClientID = "client"
+	conf.ControlPlane.ClientSecret = "secret"
+	credentials, err = getCredentials(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, ClientCredentialsAuthentication, credentials.Type)
+
+	conf.ControlPlane.Authentication.Type = JWTBearerAuthentication
+	conf.ControlPlane.Authentication.TokenEndpoint = "https://idp/token"
+	credentials, err = getCredentials(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, JWTBearerAuthentication, credentials.Type)
+	assert.Equal(t, "https://idp/token", credentials.TokenEndpoint)
+}
+
+func TestClientFailsWithInvalidCredentials(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := &config.Config{}
+	conf.ControlPlane.ServiceURL = server.URL
+	client := NewClient(false, Backoff{Initial: time.Second}, 0, 0, 0)
+	setCredentials(client, conf)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := client.Do("apis", req)
+	assert.Error(t, err)
+	assert.Equal(t, 0, requests)
+
+	conf.ControlPlane.Authentication.Type = BasicAuthentication
+	setCredentials(client, conf)
+	assert.Equal(t, http.StatusOK, sendToControlPlane(t, client, server.URL))
+	assert.Equal(t, 1, requests)
+}
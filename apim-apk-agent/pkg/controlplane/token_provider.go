@@ -0,0 +1,282 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package controlplane
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+)
+
+// Types of the authentication of the control plane requests
+const (
+	// ClientCredentialsAuthentication sends the access tokens obtained with the client credentials grant
+	ClientCredentialsAuthentication = "ClientCredentials"
+	// JWTBearerAuthentication sends the access tokens obtained by exchanging a JWT with the JWT bearer grant
+	JWTBearerAuthentication = "JWTBearer"
+	// BasicAuthentication sends the username and the password of the control plane user
+	BasicAuthentication = "Basic"
+)
+
+const (
+	tokenResource          = "token"
+	authorizationHeader    = "Authorization"
+	clientCredentialsGrant = "client_credentials"
+	jwtBearerGrant         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	// tokenRequestTimeout is the time a token request is waited for before it fails
+	tokenRequestTimeout = 30 * time.Second
+)
+
+// Credentials holds how the requests to the control plane are authenticated. The requests are not authenticated
+// when the type is empty.
+type Credentials struct {
+	Type         string
+	Username     string
+	Password     string
+	ClientID     string
+	ClientSecret string
+	// TokenEndpoint is the OAuth2 token endpoint the access tokens are obtained from
+	TokenEndpoint string
+	// Scopes are requested for the access tokens
+	Scopes []string
+	// AssertionPath is the file of the JWT exchanged with the JWT bearer grant. It is read for each token request
+	// so that the rotated assertions are picked up.
+	AssertionPath string
+	// RefreshBeforeExpiry is the time before the expiry of an access token it is refreshed
+	RefreshBeforeExpiry time.Duration
+	// BasicAuthFallback sends the username and the password when an access token cannot be obtained
+	BasicAuthFallback bool
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// tokenProvider authenticates the control plane requests. The access tokens are cached and refreshed before they
+// expire. The cached tokens are read under a read lock and the new tokens are obtained outside the lock, with a
+// single token request shared by the concurrent requests which need a new token.
+type tokenProvider struct {
+	lock        sync.RWMutex
+	credentials Credentials
+	// credentialsErr fails the requests when the credentials cannot be determined from the configurations
+	credentialsErr error
+	token          string
+	// expiresAt is zero when the lifetime of the token is unknown, in which case it is used until it is rejected
+	expiresAt time.Time
+	// inFlight is the token request in progress, which the requests needing a new token wait for
+	inFlight *tokenRequest
+	now      func() time.Time
+	// requestToken sends the token requests to the control plane
+	requestToken   func(*http.Request) (*http.Response, error)
+	requestTimeout time.Duration
+}
+
+// tokenRequest is a token request shared by the requests which need a new token. The result is set before done is
+// closed.
+type tokenRequest struct {
+	done      chan struct{}
+	token     string
+	expiresAt time.Time
+	err       error
+}
+
+func newTokenProvider(requestToken func(*http.Request) (*http.Response, error)) *tokenProvider {
+	return &tokenProvider{now: time.Now, requestToken: requestToken, requestTimeout: tokenRequestTimeout}
+}
+
+// setCredentials changes the credentials of the requests. The cached token is dropped when they change.
+func (p *tokenProvider) setCredentials(credentials Credentials) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.credentialsErr = nil
+	if reflect.DeepEqual(p.credentials, credentials) {
+		return
+	}
+	p.credentials = credentials
+	p.resetToken()
+}
+
+// setCredentialsError fails the requests with the error until valid credentials are set
+func (p *tokenProvider) setCredentialsError(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.credentialsErr = err
+	p.credentials = Credentials{}
+	p.resetToken()
+}
+
+// resetToken drops the cached token and detaches the token request in progress, whose token is obtained with the
+// previous credentials. It must be called while holding the lock.
+func (p *tokenProvider) resetToken() {
+	p.token = ""
+	p.expiresAt = time.Time{}
+	p.inFlight = nil
+}
+
+// authorize sets the Authorization header of the request
+func (p *tokenProvider) authorize(req *http.Request) error {
+	p.lock.RLock()
+	credentialsErr := p.credentialsErr
+	credentials := p.credentials
+	token := p.token
+	expiresAt := p.expiresAt
+	p.lock.RUnlock()
+	if credentialsErr != nil {
+		return credentialsErr
+	}
+	switch credentials.Type {
+	case "":
+		return nil
+	case BasicAuthentication:
+		req.Header.Set(authorizationHeader, basicAuthorization(credentials))
+		return nil
+	}
+	if token == "" || p.isExpiring(expiresAt, credentials.RefreshBeforeExpiry) {
+		newToken, err := p.obtainToken()
+		if err == nil {
+			token = newToken
+		} else if token != "" && p.now().Before(expiresAt) {
+			logger.LoggerControlPlane.Warnf("Using the current access token until it expires as it cannot be "+
+				"refreshed: %v", err)
+		} else if credentials.BasicAuthFallback {
+			logger.LoggerControlPlane.Warnf("Falling back to the basic authentication as an access token cannot "+
+				"be obtained: %v", err)
+			req.Header.Set(authorizationHeader, basicAuthorization(credentials))
+			return nil
+		} else {
+			return fmt.Errorf("unable to obtain an access token for the control plane: %w", err)
+		}
+	}
+	req.Header.Set(authorizationHeader, "Bearer "+token)
+	return nil
+}
+
+// obtainToken returns a new access token. The token request in progress is waited for if there is one, otherwise
+// a new token request is sent without holding the lock.
+func (p *tokenProvider) obtainToken() (string, error) {
+	p.lock.Lock()
+	if request := p.inFlight; request != nil {
+		p.lock.Unlock()
+		<-request.done
+		return request.token, request.err
+	}
+	request := &tokenRequest{done: make(chan struct{})}
+	p.inFlight = request
+	credentials := p.credentials
+	p.lock.Unlock()
+
+	request.token, request.expiresAt, request.err = p.refresh(credentials)
+	p.lock.Lock()
+	// The token is dropped when the credentials changed while it was requested
+	if p.inFlight == request {
+		p.inFlight = nil
+		if request.err == nil {
+			p.token = request.token
+			p.expiresAt = request.expiresAt
+		}
+	}
+	p.lock.Unlock()
+	close(request.done)
+	return request.token, request.err
+}
+
+// invalidate drops the cached token so that a new token is obtained for the next request
+func (p *tokenProvider) invalidate() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.token = ""
+	p.expiresAt = time.Time{}
+}
+
+func (p *tokenProvider) isExpiring(expiresAt time.Time, refreshBeforeExpiry time.Duration) bool {
+	return !expiresAt.IsZero() && !p.now().Before(expiresAt.Add(-refreshBeforeExpiry))
+}
+
+func basicAuthorization(credentials Credentials) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials.Username+":"+credentials.Password))
+}
+
+// refresh obtains a new access token with the grant of the authentication type and returns it with its expiry. The
+// token request fails when it does not complete within the request timeout.
+func (p *tokenProvider) refresh(credentials Credentials) (string, time.Time, error) {
+	form := url.Values{}
+	switch credentials.Type {
+	case ClientCredentialsAuthentication:
+		form.Set("grant_type", clientCredentialsGrant)
+	case JWTBearerAuthentication:
+		assertion, err := os.ReadFile(credentials.AssertionPath)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("error reading the JWT assertion: %w", err)
+		}
+		form.Set("grant_type", jwtBearerGrant)
+		form.Set("assertion", strings.TrimSpace(string(assertion)))
+	default:
+		return "", time.Time{}, fmt.Errorf("unknown authentication type %q", credentials.Type)
+	}
+	if len(credentials.Scopes) > 0 {
+		form.Set("scope", strings.Join(credentials.Scopes, " "))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, credentials.TokenEndpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if credentials.ClientID != "" {
+		req.SetBasicAuth(credentials.ClientID, credentials.ClientSecret)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := p.requestToken(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("unexpected response status %s from the token endpoint", resp.Status)
+	}
+	var response tokenResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", time.Time{}, fmt.Errorf("error parsing the token response: %w", err)
+	}
+	if response.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("access_token not found in the token response")
+	}
+	var expiresAt time.Time
+	if response.ExpiresIn > 0 {
+		expiresAt = p.now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	logger.LoggerControlPlane.Debugf("Obtained an access token for the control plane which expires at %v", expiresAt)
+	return response.AccessToken, expiresAt, nil
+}
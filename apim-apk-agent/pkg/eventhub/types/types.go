@@ -287,3 +287,15 @@ type Claim struct {
 	RemoteClaim string `json:"remoteClaim"`
 	LocalClaim  string `json:"localClaim"`
 }
+
+// BlockingCondition for struct blocking condition received from the control plane
+type BlockingCondition struct {
+	ID             int32  `json:"id,omitempty"`
+	ConditionType  string `json:"conditionType"`
+	ConditionValue string `json:"conditionValue,omitempty"`
+	FixedIP        string `json:"fixedIp,omitempty"`
+	StartingIP     string `json:"startingIp,omitempty"`
+	EndingIP       string `json:"endingIp,omitempty"`
+	Invert         bool   `json:"invert,omitempty"`
+	TenantDomain   string `json:"tenantDomain,omitempty"`
+}
@@ -18,16 +18,48 @@
 package health
 
 import (
+	"sync"
+
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
 )
 
+// States of the circuit breaker of the control plane requests
+const (
+	// CircuitClosed is the state in which the control plane requests are sent
+	CircuitClosed = "CLOSED"
+	// CircuitOpen is the state in which the control plane requests fail without being sent
+	CircuitOpen = "OPEN"
+	// CircuitHalfOpen is the state in which a trial control plane request is sent to check whether it is back
+	CircuitHalfOpen = "HALF_OPEN"
+)
+
 var (
 	controlPlaneBrokerStatusChan  = make(chan bool)
 	controlPlaneRestAPIStatusChan = make(chan bool)
 	controlPlaneStarted           = false
 	controlPlaneUnhealthy         = false
+	controlPlaneCircuitState      = CircuitClosed
+	controlPlaneCircuitLock       sync.RWMutex
 )
 
+// SetControlPlaneCircuitState sets the state of the circuit breaker of the control plane requests
+func SetControlPlaneCircuitState(state string) {
+	controlPlaneCircuitLock.Lock()
+	defer controlPlaneCircuitLock.Unlock()
+	if controlPlaneCircuitState != state {
+		logger.LoggerHealth.Infof("Update the state of the control plane circuit breaker from %s to %s",
+			controlPlaneCircuitState, state)
+	}
+	controlPlaneCircuitState = state
+}
+
+// GetControlPlaneCircuitState returns the state of the circuit breaker of the control plane requests
+func GetControlPlaneCircuitState() string {
+	controlPlaneCircuitLock.RLock()
+	defer controlPlaneCircuitLock.RUnlock()
+	return controlPlaneCircuitState
+}
+
 // SetControlPlaneBrokerStatus sets the given status to the internal channel controlPlaneBrokerStatusChan
 func SetControlPlaneBrokerStatus(status bool) {
 	// check for controlPlaneStarted, to non block call
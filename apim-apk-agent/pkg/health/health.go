@@ -39,6 +39,9 @@ const (
 	RestService                 service = "apk.apim.agent.internal.RestService"
 	NotificationListenerService service = "apk.apim.agent.internal.NotificationListenerService"
 	CommonControllerGrpcService service = "apk.apim.agent.internal.CommonControllerGrpcService"
+	// ControlPlaneService reports the state of the circuit breaker of the control plane requests. It does not
+	// affect the overall health of the agent as the agent keeps serving while the control plane is unreachable.
+	ControlPlaneService service = "apk.apim.agent.internal.ControlPlaneService"
 )
 
 type service string
@@ -76,6 +79,14 @@ func (s Server) Check(ctx context.Context, request *healthservice.HealthCheckReq
 		return &healthservice.HealthCheckResponse{Status: healthservice.HealthCheckResponse_NOT_SERVING}, nil
 	}
 
+	if request.Service == string(ControlPlaneService) {
+		if GetControlPlaneCircuitState() == CircuitClosed {
+			return &healthservice.HealthCheckResponse{Status: healthservice.HealthCheckResponse_SERVING}, nil
+		}
+		logger.LoggerHealth.Debugf("Responding health state of APIM APK Agent service \"%s\" as NOT_HEALTHY", request.Service)
+		return &healthservice.HealthCheckResponse{Status: healthservice.HealthCheckResponse_NOT_SERVING}, nil
+	}
+
 	// health of the component of a server
 	if isHealthy, ok := serviceHealthStatus[request.Service]; ok {
 		if isHealthy {
@@ -0,0 +1,95 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	packageLoggersLock sync.RWMutex
+	// packageLoggers are the package loggers mapped by their package names so that their levels can be changed
+	// at runtime
+	packageLoggers = make(map[string]Log)
+	logLevels      = map[string]logrus.Level{
+		panicLevel: logrus.PanicLevel,
+		fatalLevel: logrus.FatalLevel,
+		errorLevel: logrus.ErrorLevel,
+		warnLevel:  logrus.WarnLevel,
+		infoLevel:  logrus.InfoLevel,
+		debugLevel: logrus.DebugLevel,
+	}
+)
+
+// PackageLogLevel is the log level of a package logger
+type PackageLogLevel struct {
+	Name     string `json:"name"`
+	LogLevel string `json:"logLevel"`
+}
+
+// registerPackageLogger keeps the latest logger of a package to change its level at runtime
+func registerPackageLogger(pkgName string, logger Log) {
+	packageLoggersLock.Lock()
+	defer packageLoggersLock.Unlock()
+	packageLoggers[pkgName] = logger
+}
+
+// GetPackageLogLevels returns the current log levels of the package loggers sorted by the package names
+func GetPackageLogLevels() []PackageLogLevel {
+	packageLoggersLock.RLock()
+	defer packageLoggersLock.RUnlock()
+	levels := make([]PackageLogLevel, 0, len(packageLoggers))
+	for pkgName, logger := range packageLoggers {
+		levels = append(levels, PackageLogLevel{Name: pkgName, LogLevel: getLogLevelName(logger.GetLevel())})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Name < levels[j].Name
+	})
+	return levels
+}
+
+// SetPackageLogLevel changes the level of a package logger until the agent restarts. The level is one of the
+// levels used in the log config, i.e. PANC, FATL, ERRO, WARN, INFO or DEBG.
+func SetPackageLogLevel(pkgName string, level string) error {
+	logLevel, found := logLevels[strings.ToUpper(level)]
+	if !found {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+	packageLoggersLock.RLock()
+	defer packageLoggersLock.RUnlock()
+	logger, found := packageLoggers[pkgName]
+	if !found {
+		return fmt.Errorf("no logger found for the package %q", pkgName)
+	}
+	logger.SetLevel(logLevel)
+	return nil
+}
+
+func getLogLevelName(level logrus.Level) string {
+	for name, logLevel := range logLevels {
+		if logLevel == level {
+			return name
+		}
+	}
+	return level.String()
+}
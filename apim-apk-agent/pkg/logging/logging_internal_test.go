@@ -143,3 +143,15 @@ func TestInitGlobalLogger(t *testing.T) {
 		t.Error(e)
 	}
 }
+
+func TestSetPackageLogLevel(t *testing.T) {
+	logger := InitPackageLogger("sample.package3")
+	assert.Contains(t, GetPackageLogLevels(), PackageLogLevel{Name: "sample.package3", LogLevel: infoLevel})
+
+	assert.Nil(t, SetPackageLogLevel("sample.package3", "debg"))
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel(), "Log level of the package logger is not changed")
+	assert.Contains(t, GetPackageLogLevels(), PackageLogLevel{Name: "sample.package3", LogLevel: debugLevel})
+
+	assert.NotNil(t, SetPackageLogLevel("sample.package3", "verbose"), "Invalid log level is accepted")
+	assert.NotNil(t, SetPackageLogLevel("sample.unknown", infoLevel), "Unknown package is accepted")
+}
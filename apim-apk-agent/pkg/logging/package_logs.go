@@ -110,5 +110,6 @@ func InitPackageLogger(pkgName string) Log {
 	}
 
 	logger.SetLevel(pkgLogLevel)
+	registerPackageLogger(pkgName, logger)
 	return logger
 }
@@ -0,0 +1,99 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// API types of the APIs defined in APK
+const (
+	apkAPITypeREST      = "REST"
+	apkAPITypeGraphQL   = "GRAPHQL"
+	apkAPITypeGRPC      = "GRPC"
+	apkAPITypeWS        = "WS"
+	apkAPITypeWebSocket = "WEBSOCKET"
+)
+
+// apiTypeMapping maps an APK API type to the API type, the definition file and the transports of the API project
+// imported to API Manager
+type apiTypeMapping struct {
+	apimType       string
+	definitionFile string
+	transports     []string
+	// endpointType overrides the endpoint protocol of the API as the endpoint type when it is set
+	endpointType string
+}
+
+var (
+	httpTransports = []string{"http", "https"}
+
+	apiTypeMappings = map[string]apiTypeMapping{
+		apkAPITypeREST:      {apimType: "HTTP", definitionFile: "swagger.yaml", transports: httpTransports},
+		apkAPITypeGraphQL:   {apimType: "GRAPHQL", definitionFile: "schema.graphql", transports: httpTransports},
+		apkAPITypeGRPC:      {apimType: "GRPC", definitionFile: "schema.proto", transports: httpTransports},
+		apkAPITypeWS:        {apimType: "WS", definitionFile: "asyncapi.yaml", transports: []string{"ws", "wss"}, endpointType: "ws"},
+		apkAPITypeWebSocket: {apimType: "WS", definitionFile: "asyncapi.yaml", transports: []string{"ws", "wss"}, endpointType: "ws"},
+	}
+)
+
+// getAPITypeMapping returns the mapping of an APK API type. APIs without a type are REST APIs.
+func getAPITypeMapping(apiType string) (apiTypeMapping, error) {
+	if apiType == "" {
+		return apiTypeMappings[apkAPITypeREST], nil
+	}
+	mapping, found := apiTypeMappings[strings.ToUpper(apiType)]
+	if !found {
+		return apiTypeMapping{}, fmt.Errorf("unsupported API type %s", apiType)
+	}
+	return mapping, nil
+}
+
+// isWebSocketAPI returns whether the APK API type is a WebSocket API
+func isWebSocketAPI(apiType string) bool {
+	return strings.EqualFold(apiType, apkAPITypeWS) || strings.EqualFold(apiType, apkAPITypeWebSocket)
+}
+
+// createDefaultAsyncAPI creates the AsyncAPI definition of a WebSocket API which has no definition in APK with a
+// channel for each topic of the operations
+func createDefaultAsyncAPI(api API, operations []APIOperation) string {
+	channels := make(map[string]interface{})
+	for _, operation := range operations {
+		channel, ok := channels[operation.Target].(map[string]interface{})
+		if !ok {
+			channel = make(map[string]interface{})
+			channels[operation.Target] = channel
+		}
+		channel[strings.ToLower(operation.Verb)] = map[string]interface{}{
+			"x-auth-type": operation.AuthType,
+		}
+	}
+	asyncAPI := map[string]interface{}{
+		"asyncapi": "2.0.0",
+		"info": map[string]interface{}{
+			"title":   api.APIName,
+			"version": api.APIVersion,
+		},
+		"channels": channels,
+	}
+	yamlBytes, _ := yaml.Marshal(asyncAPI)
+	return string(yamlBytes)
+}
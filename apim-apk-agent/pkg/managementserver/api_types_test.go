@@ -0,0 +1,176 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	sampleOpenAPI = `{"openapi": "3.0.1", "info": {"title": "PetStore", "version": "1.0.0"},
+"paths": {"/pets": {"get": {"responses": {"200": {"description": "OK"}}}}}}`
+	sampleGraphQLSchema = `type Query {
+  pets: [Pet]
+}
+type Pet {
+  name: String
+}`
+	sampleProto = `syntax = "proto3";
+package org.example.petstore;
+service PetService {
+  rpc GetPet (PetRequest) returns (Pet);
+}`
+)
+
+// importedAPIProject holds the attributes of an imported API project verified by the tests
+type importedAPIProject struct {
+	Data struct {
+		Name           string                 `yaml:"name"`
+		Type           string                 `yaml:"type"`
+		Transport      []string               `yaml:"transport"`
+		EndpointConfig map[string]interface{} `yaml:"endpointConfig"`
+		Operations     []struct {
+			Target string   `yaml:"target"`
+			Verb   string   `yaml:"verb"`
+			Scopes []string `yaml:"scopes"`
+		} `yaml:"operations"`
+	} `yaml:"data"`
+}
+
+// readAPIProject zips the files of the API project and reads them back as they are read by API Manager
+func readAPIProject(t *testing.T, zipFiles []utils.ZipFile) map[string]string {
+	var buf bytes.Buffer
+	assert.NoError(t, utils.CreateZipFile(&buf, zipFiles))
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	files := make(map[string]string)
+	for _, file := range zipReader.File {
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		reader.Close()
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestCreateAPIProjectRoundTrip(t *testing.T) {
+	tests := []struct {
+		name               string
+		api                API
+		expectedType       string
+		expectedTransports []string
+		expectedEndpoint   string
+		definitionFile     string
+		expectedDefinition string
+		expectedOperations map[string]string
+	}{
+		{
+			name: "REST",
+			api: API{APIName: "PetStore", APIVersion: "1.0.0", APIType: "REST", BasePath: "/petstore/1.0.0",
+				Definition: sampleOpenAPI, EndpointProtocol: "http", ProdEndpoint: "petstore.svc:8080",
+				Operations: []OperationFromDP{{Path: "/pets", Verb: "GET", Scopes: []string{"read:pets"}}}},
+			expectedType:       "HTTP",
+			expectedTransports: []string{"http", "https"},
+			expectedEndpoint:   "http",
+			definitionFile:     "PetStore-1.0.0/Definitions/swagger.yaml",
+			expectedDefinition: "/pets:",
+			expectedOperations: map[string]string{"/pets": "GET"},
+		},
+		{
+			name: "GraphQL",
+			api: API{APIName: "PetGraph", APIVersion: "1.0.0", APIType: "GraphQL", BasePath: "/petgraph/1.0.0",
+				Definition: sampleGraphQLSchema, EndpointProtocol: "http", ProdEndpoint: "petgraph.svc:8080",
+				Operations: []OperationFromDP{{Path: "pets", Verb: "QUERY"}}},
+			expectedType:       "GRAPHQL",
+			expectedTransports: []string{"http", "https"},
+			expectedEndpoint:   "http",
+			definitionFile:     "PetGraph-1.0.0/Definitions/schema.graphql",
+			expectedDefinition: sampleGraphQLSchema,
+			expectedOperations: map[string]string{"pets": "QUERY"},
+		},
+		{
+			name: "gRPC",
+			api: API{APIName: "PetService", APIVersion: "1.0.0", APIType: "GRPC", BasePath: "/org.example.petstore",
+				Definition: sampleProto, EndpointProtocol: "http", ProdEndpoint: "petservice.svc:9090",
+				Operations: []OperationFromDP{{Path: "org.example.petstore.PetService", Verb: "GetPet"}}},
+			expectedType:       "GRPC",
+			expectedTransports: []string{"http", "https"},
+			expectedEndpoint:   "http",
+			definitionFile:     "PetService-1.0.0/Definitions/schema.proto",
+			expectedDefinition: sampleProto,
+			expectedOperations: map[string]string{"org.example.petstore.PetService": "GetPet"},
+		},
+		{
+			name: "WebSocket",
+			api: API{APIName: "PetNotifications", APIVersion: "1.0.0", APIType: "WS", BasePath: "/notifications/1.0.0",
+				EndpointProtocol: "ws", ProdEndpoint: "notifications.svc:8080"},
+			expectedType:       "WS",
+			expectedTransports: []string{"ws", "wss"},
+			expectedEndpoint:   "ws",
+			definitionFile:     "PetNotifications-1.0.0/Definitions/asyncapi.yaml",
+			expectedDefinition: "asyncapi: 2.0.0",
+			expectedOperations: map[string]string{"/*": "SUBSCRIBE"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := &APICPEvent{Event: CreateEvent, API: test.api}
+			zipFiles, err := createAPIProject(event)
+			assert.NoError(t, err)
+			files := readAPIProject(t, zipFiles)
+			assert.Len(t, files, 3)
+
+			var project importedAPIProject
+			apiYaml, found := files[test.api.APIName+"-"+test.api.APIVersion+"/api.yaml"]
+			assert.True(t, found, "api.yaml is missing")
+			assert.NoError(t, yaml.Unmarshal([]byte(apiYaml), &project))
+			assert.Equal(t, test.api.APIName, project.Data.Name)
+			assert.Equal(t, test.expectedType, project.Data.Type)
+			assert.Equal(t, test.expectedTransports, project.Data.Transport)
+			assert.Equal(t, test.expectedEndpoint, project.Data.EndpointConfig["endpoint_type"])
+			for target, verb := range test.expectedOperations {
+				found := false
+				for _, operation := range project.Data.Operations {
+					if operation.Target == target && strings.EqualFold(operation.Verb, verb) {
+						found = true
+					}
+				}
+				assert.True(t, found, "Operation %s %s is missing", verb, target)
+			}
+
+			definition, found := files[test.definitionFile]
+			assert.True(t, found, "Definition %s is missing", test.definitionFile)
+			assert.Contains(t, definition, test.expectedDefinition)
+		})
+	}
+}
+
+func TestCreateAPIProjectWithUnsupportedType(t *testing.T) {
+	_, err := createAPIProject(&APICPEvent{Event: CreateEvent, API: API{APIName: "Legacy", APIVersion: "1.0.0", APIType: "SOAP"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	eventHub "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+)
+
+// Blocking condition types sent by the control plane
+const (
+	BlockingConditionAPI          = "API"
+	BlockingConditionApplication  = "APPLICATION"
+	BlockingConditionUser         = "USER"
+	BlockingConditionSubscription = "SUBSCRIPTION"
+	BlockingConditionCustom       = "CUSTOM"
+	BlockingConditionIP           = "IP"
+	BlockingConditionIPRange      = "IPRANGE"
+)
+
+var (
+	blockingConditionMap   map[string]eventHub.BlockingCondition
+	blockingConditionMutex sync.RWMutex
+)
+
+func init() {
+	blockingConditionMap = make(map[string]eventHub.BlockingCondition)
+}
+
+// GetBlockingConditionKey returns the key used to store a blocking condition. IP conditions are identified
+// by their ID while the other conditions are identified by the condition value.
+func GetBlockingConditionKey(blockingCondition eventHub.BlockingCondition) string {
+	conditionType := strings.ToUpper(blockingCondition.ConditionType)
+	if conditionType == BlockingConditionIP || conditionType == BlockingConditionIPRange {
+		return fmt.Sprintf("%s:%d", BlockingConditionIP, blockingCondition.ID)
+	}
+	return conditionType + ":" + blockingCondition.ConditionValue
+}
+
+// AddBlockingCondition adds a blocking condition to the blockingConditionMap
+func AddBlockingCondition(blockingCondition eventHub.BlockingCondition) {
+	blockingConditionMutex.Lock()
+	defer blockingConditionMutex.Unlock()
+	blockingConditionMap[GetBlockingConditionKey(blockingCondition)] = blockingCondition
+}
+
+// DeleteBlockingCondition deletes a blocking condition from the blockingConditionMap
+func DeleteBlockingCondition(blockingCondition eventHub.BlockingCondition) {
+	blockingConditionMutex.Lock()
+	defer blockingConditionMutex.Unlock()
+	delete(blockingConditionMap, GetBlockingConditionKey(blockingCondition))
+}
+
+// AddAllBlockingConditions replaces the blocking conditions in the blockingConditionMap
+func AddAllBlockingConditions(blockingConditions []eventHub.BlockingCondition) {
+	blockingConditionMapTemp := make(map[string]eventHub.BlockingCondition)
+	for _, blockingCondition := range blockingConditions {
+		blockingConditionMapTemp[GetBlockingConditionKey(blockingCondition)] = blockingCondition
+	}
+	blockingConditionMutex.Lock()
+	defer blockingConditionMutex.Unlock()
+	blockingConditionMap = blockingConditionMapTemp
+}
+
+// GetAllBlockingConditions returns all the blocking conditions in the blockingConditionMap sorted by their key
+func GetAllBlockingConditions() []eventHub.BlockingCondition {
+	blockingConditionMutex.RLock()
+	defer blockingConditionMutex.RUnlock()
+	keys := make([]string, 0, len(blockingConditionMap))
+	for key := range blockingConditionMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	blockingConditions := make([]eventHub.BlockingCondition, 0, len(keys))
+	for _, key := range keys {
+		blockingConditions = append(blockingConditions, blockingConditionMap[key])
+	}
+	return blockingConditions
+}
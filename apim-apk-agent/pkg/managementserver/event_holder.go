@@ -17,6 +17,8 @@
 package managementserver
 
 import (
+	"sync"
+
 	eventHub "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
@@ -30,6 +32,9 @@ var (
 	rateLimitPolicyMap       map[string]eventHub.RateLimitPolicy
 	aiProviderMap            map[string]eventHub.AIProvider
 	subscriptionPolicyMap    map[string]eventHub.SubscriptionPolicy
+	// subscriptionDataMutex guards the applicationMap, subscriptionMap, applicationMappingMap and
+	// applicationKeyMappingMap
+	subscriptionDataMutex sync.RWMutex
 )
 
 func init() {
@@ -112,28 +117,42 @@ func UpdateRateLimitPolicy(name string, tenantDomain string, rateLimitPolicy eve
 
 // AddApplication adds an application to the applicationMap
 func AddApplication(application Application) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMap[application.UUID] = application
+	snapshotChanged()
 }
 
 // AddSubscription adds a subscription to the subscriptionMap
 func AddSubscription(subscription Subscription) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	subscriptionMap[subscription.UUID] = subscription
+	snapshotChanged()
 }
 
 // AddApplicationMapping adds an application mapping to the applicationMappingMap
 func AddApplicationMapping(applicationMapping ApplicationMapping) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMappingMap[applicationMapping.UUID] = applicationMapping
+	snapshotChanged()
 }
 
 // AddApplicationKeyMapping adds an application key mapping to the applicationKeyMappingMap
 func AddApplicationKeyMapping(applicationKeyMapping ApplicationKeyMapping) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	uuid := utils.GetUniqueIDOfApplicationKeyMapping(applicationKeyMapping.ApplicationUUID, applicationKeyMapping.KeyType, applicationKeyMapping.SecurityScheme, applicationKeyMapping.EnvID, applicationKeyMapping.Organization)
 	loggers.LoggerMgtServer.Infof("Adding application key mapping with uuid: %v", uuid)
 	applicationKeyMappingMap[uuid] = applicationKeyMapping
+	snapshotChanged()
 }
 
 // GetAllApplications returns all the applications in the applicationMap
 func GetAllApplications() []ResolvedApplication {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	var applications []ResolvedApplication
 	for _, application := range applicationMap {
 		resolvedApplication := marshalApplication(application)
@@ -154,6 +173,8 @@ func marshalApplication(application Application) ResolvedApplication {
 
 // GetAllSubscriptions returns all the subscriptions in the subscriptionMap
 func GetAllSubscriptions() []Subscription {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	var subscriptions []Subscription
 	for _, subscription := range subscriptionMap {
 		subscriptions = append(subscriptions, subscription)
@@ -163,6 +184,8 @@ func GetAllSubscriptions() []Subscription {
 
 // GetAllApplicationMappings returns all the application mappings in the applicationMappingMap
 func GetAllApplicationMappings() []ApplicationMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	var applicationMappings []ApplicationMapping
 	for _, applicationMapping := range applicationMappingMap {
 		applicationMappings = append(applicationMappings, applicationMapping)
@@ -170,69 +193,171 @@ func GetAllApplicationMappings() []ApplicationMapping {
 	return applicationMappings
 }
 
+// GetAllApplicationsOfOrganization returns the applications of the given organization in the applicationMap
+func GetAllApplicationsOfOrganization(organization string) []ResolvedApplication {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
+	var applications []ResolvedApplication
+	for _, application := range applicationMap {
+		if application.Organization == organization {
+			applications = append(applications, marshalApplication(application))
+		}
+	}
+	return applications
+}
+
+// GetAllSubscriptionsOfOrganization returns the subscriptions of the given organization in the subscriptionMap
+func GetAllSubscriptionsOfOrganization(organization string) []Subscription {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
+	var subscriptions []Subscription
+	for _, subscription := range subscriptionMap {
+		if subscription.Organization == organization {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions
+}
+
+// GetAllApplicationMappingsOfOrganization returns the application mappings of the given organization in the
+// applicationMappingMap
+func GetAllApplicationMappingsOfOrganization(organization string) []ApplicationMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
+	var applicationMappings []ApplicationMapping
+	for _, applicationMapping := range applicationMappingMap {
+		if applicationMapping.Organization == organization {
+			applicationMappings = append(applicationMappings, applicationMapping)
+		}
+	}
+	return applicationMappings
+}
+
+// DeleteAllOfOrganization deletes the applications, subscriptions, application mappings and application key
+// mappings of the given organization
+func DeleteAllOfOrganization(organization string) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
+	for uuid, application := range applicationMap {
+		if application.Organization == organization {
+			delete(applicationMap, uuid)
+		}
+	}
+	for uuid, subscription := range subscriptionMap {
+		if subscription.Organization == organization {
+			delete(subscriptionMap, uuid)
+		}
+	}
+	for uuid, applicationMapping := range applicationMappingMap {
+		if applicationMapping.Organization == organization {
+			delete(applicationMappingMap, uuid)
+		}
+	}
+	for uuid, applicationKeyMapping := range applicationKeyMappingMap {
+		if applicationKeyMapping.Organization == organization {
+			delete(applicationKeyMappingMap, uuid)
+		}
+	}
+	snapshotChanged()
+}
+
 // GetApplication returns an application from the applicationMap
 func GetApplication(uuid string) Application {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	return applicationMap[uuid]
 }
 
 // GetSubscription returns a subscription from the subscriptionMap
 func GetSubscription(uuid string) Subscription {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	return subscriptionMap[uuid]
 }
 
 // GetApplicationMapping returns an application mapping from the applicationMappingMap
 func GetApplicationMapping(uuid string) ApplicationMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	return applicationMappingMap[uuid]
 }
 
 // GetApplicationKeyMapping returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMapping(uuid string) ApplicationKeyMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	return applicationKeyMappingMap[uuid]
 }
 
 // DeleteApplication deletes an application from the applicationMap
 func DeleteApplication(uuid string) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	delete(applicationMap, uuid)
+	snapshotChanged()
 }
 
 // DeleteSubscription deletes a subscription from the subscriptionMap
 func DeleteSubscription(uuid string) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	delete(subscriptionMap, uuid)
+	snapshotChanged()
 }
 
 // DeleteApplicationMapping deletes an application mapping from the applicationMappingMap
 func DeleteApplicationMapping(uuid string) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	delete(applicationMappingMap, uuid)
+	snapshotChanged()
 }
 
 // DeleteApplicationKeyMapping deletes an application key mapping from the applicationKeyMappingMap
 func DeleteApplicationKeyMapping(uuid string) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	loggers.LoggerMgtServer.Infof("Deleting application key mapping with uuid: %v", uuid)
 	delete(applicationKeyMappingMap, uuid)
+	snapshotChanged()
 }
 
 // UpdateApplication updates an application in the applicationMap
 func UpdateApplication(uuid string, application Application) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMap[uuid] = application
+	snapshotChanged()
 }
 
 // UpdateSubscription updates a subscription in the subscriptionMap
 func UpdateSubscription(uuid string, subscription Subscription) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	subscriptionMap[uuid] = subscription
+	snapshotChanged()
 }
 
 // UpdateApplicationMapping updates an application mapping in the applicationMappingMap
 func UpdateApplicationMapping(uuid string, applicationMapping ApplicationMapping) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMappingMap[uuid] = applicationMapping
+	snapshotChanged()
 }
 
 // UpdateApplicationKeyMapping updates an application key mapping in the applicationKeyMappingMap
 func UpdateApplicationKeyMapping(uuid string, applicationKeyMapping ApplicationKeyMapping) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationKeyMappingMap[uuid] = applicationKeyMapping
+	snapshotChanged()
 }
 
 // GetApplicationKeyMappingByApplicationUUID returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUID(uuid string) ApplicationKeyMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid {
 			return applicationKeyMapping
@@ -243,6 +368,8 @@ func GetApplicationKeyMappingByApplicationUUID(uuid string) ApplicationKeyMappin
 
 // GetApplicationKeyMappingByApplicationUUIDAndEnvID returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUIDAndEnvID(uuid string, envID string) ApplicationKeyMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid && applicationKeyMapping.EnvID == envID {
 			return applicationKeyMapping
@@ -253,6 +380,8 @@ func GetApplicationKeyMappingByApplicationUUIDAndEnvID(uuid string, envID string
 
 // GetApplicationKeyMappingByApplicationUUIDAndSecurityScheme returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUIDAndSecurityScheme(uuid string, securityScheme string) ApplicationKeyMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid && applicationKeyMapping.SecurityScheme == securityScheme {
 			return applicationKeyMapping
@@ -263,6 +392,8 @@ func GetApplicationKeyMappingByApplicationUUIDAndSecurityScheme(uuid string, sec
 
 // GetApplicationKeyMappingByApplicationUUIDAndSecuritySchemeAndEnvID returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUIDAndSecuritySchemeAndEnvID(uuid string, securityScheme string, envID string) ApplicationKeyMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid && applicationKeyMapping.SecurityScheme == securityScheme && applicationKeyMapping.EnvID == envID {
 			return applicationKeyMapping
@@ -273,6 +404,8 @@ func GetApplicationKeyMappingByApplicationUUIDAndSecuritySchemeAndEnvID(uuid str
 
 // GetApplicationMappingByApplicationUUID returns an application mapping from the applicationMappingMap
 func GetApplicationMappingByApplicationUUID(uuid string) ApplicationMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	for _, applicationMapping := range applicationMappingMap {
 		if applicationMapping.ApplicationRef == uuid {
 			return applicationMapping
@@ -283,6 +416,8 @@ func GetApplicationMappingByApplicationUUID(uuid string) ApplicationMapping {
 
 // GetApplicationMappingByApplicationUUIDAndSubscriptionUUID returns an application mapping from the applicationMappingMap
 func GetApplicationMappingByApplicationUUIDAndSubscriptionUUID(uuid string, subscriptionUUID string) ApplicationMapping {
+	subscriptionDataMutex.RLock()
+	defer subscriptionDataMutex.RUnlock()
 	for _, applicationMapping := range applicationMappingMap {
 		if applicationMapping.ApplicationRef == uuid && applicationMapping.SubscriptionRef == subscriptionUUID {
 			return applicationMapping
@@ -293,58 +428,88 @@ func GetApplicationMappingByApplicationUUIDAndSubscriptionUUID(uuid string, subs
 
 // DeleteAllApplications deletes all the applications in the applicationMap
 func DeleteAllApplications() {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMap = make(map[string]Application)
+	snapshotChanged()
 }
 
 // DeleteAllSubscriptions deletes all the subscriptions in the subscriptionMap
 func DeleteAllSubscriptions() {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	subscriptionMap = make(map[string]Subscription)
+	snapshotChanged()
 }
 
 // DeleteAllApplicationMappings deletes all the application mappings in the applicationMappingMap
 func DeleteAllApplicationMappings() {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMappingMap = make(map[string]ApplicationMapping)
+	snapshotChanged()
 }
 
 // DeleteAllApplicationKeyMappings deletes all the application key mappings in the applicationKeyMappingMap
 func DeleteAllApplicationKeyMappings() {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationKeyMappingMap = make(map[string]ApplicationKeyMapping)
+	snapshotChanged()
 }
 
 // AddAllSubscriptions adds all the subscriptions in the subscriptionMap
 func AddAllSubscriptions(subscriptionMapTemp map[string]Subscription) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	subscriptionMap = subscriptionMapTemp
+	snapshotChanged()
 }
 
 // AddAllApplications adds all the applications in the applicationMap
 func AddAllApplications(applicationMapTemp map[string]Application) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMap = applicationMapTemp
+	snapshotChanged()
 }
 
 // AddAllApplicationMappings adds all the application mappings in the applicationMappingMap
 func AddAllApplicationMappings(applicationMappingMapTemp map[string]ApplicationMapping) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationMappingMap = applicationMappingMapTemp
+	snapshotChanged()
 }
 
 // AddAllApplicationKeyMappings adds all the application key mappings in the applicationKeyMappingMap
 func AddAllApplicationKeyMappings(applicationKeyMappingMapTemp map[string]ApplicationKeyMapping) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	applicationKeyMappingMap = applicationKeyMappingMapTemp
+	snapshotChanged()
 }
 
 // DeleteAllSubscriptionsByApplicationsUUID deletes all the subscriptions in the subscriptionMap
 func DeleteAllSubscriptionsByApplicationsUUID(uuid string) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	for _, subscription := range subscriptionMap {
 		if subscription.Organization == uuid {
 			delete(subscriptionMap, subscription.UUID)
 		}
 	}
+	snapshotChanged()
 }
 
 // DeleteAllApplicationMappingsByApplicationsUUID deletes all the application mappings in the applicationMappingMap
 func DeleteAllApplicationMappingsByApplicationsUUID(uuid string) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
 	for _, applicationMapping := range applicationMappingMap {
 		if applicationMapping.UUID == uuid {
 			delete(applicationMappingMap, applicationMapping.UUID)
 		}
 	}
+	snapshotChanged()
 }
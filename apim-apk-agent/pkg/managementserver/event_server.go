@@ -17,12 +17,22 @@
 package managementserver
 
 import (
+	"strconv"
+
 	apkmgt "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/service/apkmgt"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
 	"google.golang.org/grpc/metadata"
 )
 
+const (
+	// eventStreamIDMetadata is the metadata key of the ID of the event stream. It is sent to the client in the
+	// header and returned by the client when it reconnects.
+	eventStreamIDMetadata = "event-stream-id"
+	// lastEventSequenceMetadata is the metadata key of the sequence number of the last event received by the client
+	lastEventSequenceMetadata = "last-event-sequence"
+)
+
 // EventServer struct use to hold event server
 type EventServer struct {
 	apkmgt.UnimplementedEventStreamServiceServer
@@ -39,10 +49,54 @@ func (s EventServer) StreamEvents(req *apkmgt.Request, srv apkmgt.EventStreamSer
 	}
 	commonControllerID := md.Get("common-controller-uuid")
 	logger.LoggerMgtServer.Debugf("Enforcer ID : %v", commonControllerID[0])
-	utils.AddClientConnection(commonControllerID[0], srv)
-	utils.SendInitialEvent(srv)
-	<-srv.Context().Done()
-	logger.LoggerMgtServer.Infof("Connection closed by the client : %v", commonControllerID[0])
+	if err := srv.SendHeader(metadata.Pairs(eventStreamIDMetadata, utils.GetEventStreamID())); err != nil {
+		logger.LoggerMgtServer.Errorf("Error sending the event stream ID to the client: %v", err)
+	}
+	stopped, resumed := resumeEvents(commonControllerID[0], md, srv)
+	if !resumed {
+		stopped = utils.AddClientConnection(commonControllerID[0], srv)
+		utils.SendInitialEvent(commonControllerID[0])
+		sendRevokedTokens(commonControllerID[0])
+	}
+	select {
+	case <-srv.Context().Done():
+		logger.LoggerMgtServer.Infof("Connection closed by the client : %v", commonControllerID[0])
+	case <-stopped:
+		// The client resumes the events it missed after it reconnects
+		logger.LoggerMgtServer.Infof("Closing the connection of the client as the events cannot be sent : %v",
+			commonControllerID[0])
+	}
 	utils.DeleteClientConnection(commonControllerID[0])
 	return nil // Client closed the connection
 }
+
+// resumeEvents sends only the missed events to a client which reconnects with the stream ID and the sequence number
+// of the last event it received. The returned channel is closed when the events can no longer be sent to the client.
+// It returns false when the client has to be sent the full state instead.
+func resumeEvents(clientID string, md metadata.MD,
+	srv apkmgt.EventStreamService_StreamEventsServer) (<-chan struct{}, bool) {
+	streamID := md.Get(eventStreamIDMetadata)
+	lastSequence := md.Get(lastEventSequenceMetadata)
+	if len(streamID) == 0 || len(lastSequence) == 0 {
+		return nil, false
+	}
+	sequence, err := strconv.ParseUint(lastSequence[0], 10, 64)
+	if err != nil {
+		logger.LoggerMgtServer.Warnf("Invalid last event sequence %q from client %s", lastSequence[0], clientID)
+		return nil, false
+	}
+	stopped, resumed := utils.ResumeClientConnection(clientID, srv, streamID[0], sequence)
+	if !resumed {
+		logger.LoggerMgtServer.Infof("Events of client %s cannot be resumed from sequence %d, hence sending the full state",
+			clientID, sequence)
+		return nil, false
+	}
+	return stopped, true
+}
+
+// sendRevokedTokens replays the currently known revoked tokens to a newly connected client
+func sendRevokedTokens(clientID string) {
+	for _, revokedToken := range GetAllRevokedTokens() {
+		utils.SendEventToClient(clientID, CreateRevokedTokenEvent(revokedToken))
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	eventHub "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/utils"
+)
+
+const (
+	managementBasePath = "/management"
+	apiUUIDPathParam   = "apiUUID"
+)
+
+// AgentController provides the state of the running agent which is not held by the management server and the
+// operations to control the agent to the management endpoints
+type AgentController interface {
+	// GetAPIs returns the APIs deployed to the data plane by the agent
+	GetAPIs() ([]DeployedAPI, error)
+	// GetKeyManagers returns the key managers applied to the data plane by the agent
+	GetKeyManagers() ([]DeployedKeyManager, error)
+	// Resync starts syncing all the resources of the data plane and the subscription data with the control plane
+	Resync() error
+	// ResyncAPI redeploys an API from the control plane
+	ResyncAPI(apiUUID string) error
+}
+
+var (
+	agentControllerLock sync.RWMutex
+	agentController     AgentController
+)
+
+// SetAgentController sets the controller the management endpoints inspect and control the agent through
+func SetAgentController(controller AgentController) {
+	agentControllerLock.Lock()
+	defer agentControllerLock.Unlock()
+	agentController = controller
+}
+
+func getAgentController() AgentController {
+	agentControllerLock.RLock()
+	defer agentControllerLock.RUnlock()
+	return agentController
+}
+
+// registerManagementEndpoints registers the endpoints to inspect and control the running agent. The endpoints are
+// protected with basic authentication in addition to the read and write authentication rules of the server.
+func registerManagementEndpoints(r *gin.Engine, conf *config.Config) {
+	if !conf.Agent.ManagementAPI.Enabled {
+		return
+	}
+	if conf.Agent.ManagementAPI.Username == "" || conf.Agent.ManagementAPI.Password == "" {
+		logger.LoggerMgtServer.Error("Management endpoints are not exposed as the credentials to access them are not configured")
+		return
+	}
+	management := r.Group(managementBasePath, gin.BasicAuth(gin.Accounts{
+		conf.Agent.ManagementAPI.Username: conf.Agent.ManagementAPI.Password,
+	}))
+	management.GET("/apis", withAgentController(func(c *gin.Context, controller AgentController) {
+		apis, err := controller.GetAPIs()
+		if err != nil {
+			logger.LoggerMgtServer.Errorf("Error while retrieving the deployed APIs: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, DeployedAPIList{List: apis})
+	}))
+	management.POST("/apis/:"+apiUUIDPathParam+"/resync", withAgentController(func(c *gin.Context, controller AgentController) {
+		apiUUID := c.Param(apiUUIDPathParam)
+		logger.LoggerMgtServer.Infof("Resync of API %s is requested", apiUUID)
+		if err := controller.ResyncAPI(apiUUID); err != nil {
+			logger.LoggerMgtServer.Errorf("Error while resyncing API %s: %v", apiUUID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	}))
+	management.GET("/keymanagers", withAgentController(func(c *gin.Context, controller AgentController) {
+		keyManagers, err := controller.GetKeyManagers()
+		if err != nil {
+			logger.LoggerMgtServer.Errorf("Error while retrieving the deployed key managers: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, DeployedKeyManagerList{List: keyManagers})
+	}))
+	management.GET("/ratelimitpolicies", func(c *gin.Context) {
+		subscriptionPolicies := make([]eventHub.SubscriptionPolicy, 0, len(GetSubscriptionPolicies()))
+		for _, subscriptionPolicy := range GetSubscriptionPolicies() {
+			subscriptionPolicies = append(subscriptionPolicies, subscriptionPolicy)
+		}
+		c.JSON(http.StatusOK, RateLimitPolicyState{
+			RateLimitPolicies:    nonNilSlice(GetAllRateLimitPolicies()),
+			SubscriptionPolicies: subscriptionPolicies,
+		})
+	})
+	management.GET("/aiproviders", func(c *gin.Context) {
+		c.JSON(http.StatusOK, AIProviderList{List: nonNilSlice(GetAllAIProviders())})
+	})
+	management.GET("/clients", func(c *gin.Context) {
+		c.JSON(http.StatusOK, ClientConnectionState{
+			EventStreamID:       utils.GetEventStreamID(),
+			LatestEventSequence: utils.GetLatestEventSequence(),
+			Clients:             utils.GetClientConnectionIDs(),
+		})
+	})
+	management.POST("/resync", withAgentController(func(c *gin.Context, controller AgentController) {
+		logger.LoggerMgtServer.Info("Full resync is requested")
+		if err := controller.Resync(); err != nil {
+			logger.LoggerMgtServer.Errorf("Error while starting the resync: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": "Resync started"})
+	}))
+	management.GET("/loglevels", func(c *gin.Context) {
+		c.JSON(http.StatusOK, LogLevelList{List: logging.GetPackageLogLevels()})
+	})
+	management.PUT("/loglevels", func(c *gin.Context) {
+		var logLevel logging.PackageLogLevel
+		if err := c.ShouldBindJSON(&logLevel); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := logging.SetPackageLogLevel(logLevel.Name, logLevel.LogLevel); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.LoggerMgtServer.Infof("Log level of %s is changed to %s", logLevel.Name, logLevel.LogLevel)
+		c.JSON(http.StatusOK, LogLevelList{List: logging.GetPackageLogLevels()})
+	})
+}
+
+// withAgentController responds with 503 until the agent controller is set
+func withAgentController(handler func(*gin.Context, AgentController)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		controller := getAgentController()
+		if controller == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent is not ready"})
+			return
+		}
+		handler(c, controller)
+	}
+}
+
+// nonNilSlice returns an empty slice instead of nil so that an empty list is serialized as [] instead of null
+func nonNilSlice[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
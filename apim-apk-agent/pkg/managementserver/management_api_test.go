@@ -0,0 +1,137 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	eventHub "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
+)
+
+type fakeAgentController struct {
+	resynced    bool
+	resyncedAPI string
+}
+
+func (controller *fakeAgentController) GetAPIs() ([]DeployedAPI, error) {
+	return []DeployedAPI{{UUID: "api1", Name: "Test API", Version: "v1", RevisionID: "rev1", Status: "Accepted", Accepted: true}}, nil
+}
+
+func (controller *fakeAgentController) GetKeyManagers() ([]DeployedKeyManager, error) {
+	return nil, errors.New("cache is not started")
+}
+
+func (controller *fakeAgentController) Resync() error {
+	controller.resynced = true
+	return nil
+}
+
+func (controller *fakeAgentController) ResyncAPI(apiUUID string) error {
+	controller.resyncedAPI = apiUUID
+	return nil
+}
+
+func newManagementTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	conf := &config.Config{}
+	conf.Agent.ManagementAPI.Enabled = true
+	conf.Agent.ManagementAPI.Username = "admin"
+	conf.Agent.ManagementAPI.Password = "secret"
+	r := gin.New()
+	registerManagementEndpoints(r, conf)
+	return r
+}
+
+func serveManagementRequest(r *gin.Engine, method string, path string, body string, authenticated bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if authenticated {
+		req.SetBasicAuth("admin", "secret")
+	}
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestManagementEndpoints(t *testing.T) {
+	r := newManagementTestRouter()
+	SetAgentController(nil)
+	assert.Equal(t, http.StatusUnauthorized, serveManagementRequest(r, http.MethodGet, "/management/apis", "", false).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serveManagementRequest(r, http.MethodGet, "/management/apis", "", true).Code)
+
+	controller := &fakeAgentController{}
+	SetAgentController(controller)
+	defer SetAgentController(nil)
+
+	response := serveManagementRequest(r, http.MethodGet, "/management/apis", "", true)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var apis DeployedAPIList
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &apis))
+	assert.Equal(t, "rev1", apis.List[0].RevisionID)
+
+	assert.Equal(t, http.StatusInternalServerError, serveManagementRequest(r, http.MethodGet, "/management/keymanagers", "", true).Code)
+
+	assert.Equal(t, http.StatusAccepted, serveManagementRequest(r, http.MethodPost, "/management/resync", "", true).Code)
+	assert.True(t, controller.resynced)
+	assert.Equal(t, http.StatusOK, serveManagementRequest(r, http.MethodPost, "/management/apis/api1/resync", "", true).Code)
+	assert.Equal(t, "api1", controller.resyncedAPI)
+
+	AddAIProvider(eventHub.AIProvider{ID: "provider1", Name: "OpenAI"})
+	defer DeleteAIProvider("provider1")
+	response = serveManagementRequest(r, http.MethodGet, "/management/aiproviders", "", true)
+	var aiProviders AIProviderList
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &aiProviders))
+	assert.Contains(t, aiProviders.List, eventHub.AIProvider{ID: "provider1", Name: "OpenAI"})
+
+	response = serveManagementRequest(r, http.MethodGet, "/management/clients", "", true)
+	var clients ClientConnectionState
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &clients))
+	assert.NotEmpty(t, clients.EventStreamID)
+	assert.NotNil(t, clients.Clients)
+}
+
+func TestManagementLogLevels(t *testing.T) {
+	r := newManagementTestRouter()
+	pkgName := "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+	body := `{"name": "` + pkgName + `", "logLevel": "DEBG"}`
+	response := serveManagementRequest(r, http.MethodPut, "/management/loglevels", body, true)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var logLevels LogLevelList
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &logLevels))
+	assert.Contains(t, logLevels.List, logging.PackageLogLevel{Name: pkgName, LogLevel: "DEBG"})
+	assert.NoError(t, logging.SetPackageLogLevel(pkgName, "INFO"))
+
+	body = `{"name": "` + pkgName + `", "logLevel": "TRACE"}`
+	assert.Equal(t, http.StatusBadRequest, serveManagementRequest(r, http.MethodPut, "/management/loglevels", body, true).Code)
+}
+
+func TestManagementEndpointsWithoutCredentials(t *testing.T) {
+	conf := &config.Config{}
+	conf.Agent.ManagementAPI.Enabled = true
+	r := gin.New()
+	registerManagementEndpoints(r, conf)
+	assert.Equal(t, http.StatusNotFound, serveManagementRequest(r, http.MethodGet, "/management/apis", "", true).Code)
+}
@@ -17,9 +17,12 @@
 package managementserver
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	eventHub "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
 )
 
 func TestAddApplication(t *testing.T) {
@@ -343,3 +346,119 @@ func TestDeleteAllApplicationMappingsByApplicationsUUID(t *testing.T) {
 		assert.NotEqual(t, uuid, appMapping.ApplicationRef)
 	}
 }
+
+func TestRevokedTokens(t *testing.T) {
+	DeleteAllRevokedTokens()
+	now := time.Now()
+	AddRevokedToken(RevokedToken{Token: "jti1", ExpiryTime: now.Add(time.Minute).UnixMilli(), TokenType: "JWT"})
+	AddRevokedToken(RevokedToken{Token: "jti2", ExpiryTime: now.Add(-time.Minute).UnixMilli(), TokenType: "JWT"})
+	AddRevokedToken(RevokedToken{Token: "jti3", TokenType: "JWT"})
+
+	assert.True(t, IsTokenRevoked("jti1"))
+	assert.False(t, IsTokenRevoked("jti2"))
+	assert.True(t, IsTokenRevoked("jti3"))
+	assert.False(t, IsTokenRevoked("unknown"))
+
+	revokedTokens := GetAllRevokedTokens()
+	assert.Len(t, revokedTokens, 2)
+	for _, revokedToken := range revokedTokens {
+		assert.NotEqual(t, "jti2", revokedToken.Token)
+	}
+
+	event := CreateRevokedTokenEvent(RevokedToken{Token: "jti1", ExpiryTime: 123456789})
+	assert.Equal(t, TokenRevokedEventType, event.Type)
+	assert.Equal(t, "jti1", event.Uuid)
+	assert.Equal(t, int64(123456789), event.TimeStamp)
+	DeleteAllRevokedTokens()
+}
+
+func TestBlockingConditions(t *testing.T) {
+	AddAllBlockingConditions([]eventHub.BlockingCondition{
+		{ConditionType: BlockingConditionAPI, ConditionValue: "/pizzashack/1.0.0"},
+		{ID: 1, ConditionType: BlockingConditionIP, FixedIP: "10.0.0.1"},
+	})
+	AddBlockingCondition(eventHub.BlockingCondition{ID: 2, ConditionType: BlockingConditionIPRange, StartingIP: "10.0.0.2", EndingIP: "10.0.0.9"})
+	AddBlockingCondition(eventHub.BlockingCondition{ConditionType: BlockingConditionUser, ConditionValue: "admin@carbon.super"})
+	assert.Len(t, GetAllBlockingConditions(), 4)
+
+	DeleteBlockingCondition(eventHub.BlockingCondition{ID: 1, ConditionType: BlockingConditionIP})
+	DeleteBlockingCondition(eventHub.BlockingCondition{ConditionType: BlockingConditionAPI, ConditionValue: "/pizzashack/1.0.0"})
+	blockingConditions := GetAllBlockingConditions()
+	assert.Len(t, blockingConditions, 2)
+	assert.Equal(t, "IP:2", GetBlockingConditionKey(blockingConditions[0]))
+	assert.Equal(t, "USER:admin@carbon.super", GetBlockingConditionKey(blockingConditions[1]))
+	AddAllBlockingConditions(nil)
+}
+
+func TestOrganizationPartitions(t *testing.T) {
+	applicationMap = map[string]Application{
+		"app1": {UUID: "app1", Organization: "Org1"},
+		"app2": {UUID: "app2", Organization: "Org2"},
+	}
+	subscriptionMap = map[string]Subscription{
+		"sub1": {UUID: "sub1", Organization: "Org1"},
+		"sub2": {UUID: "sub2", Organization: "Org2"},
+	}
+	applicationMappingMap = map[string]ApplicationMapping{
+		"map1": {UUID: "map1", Organization: "Org1"},
+		"map2": {UUID: "map2", Organization: "Org2"},
+	}
+	applicationKeyMappingMap = map[string]ApplicationKeyMapping{
+		"key1": {ApplicationUUID: "app1", Organization: "Org1"},
+		"key2": {ApplicationUUID: "app2", Organization: "Org2"},
+	}
+
+	applications := GetAllApplicationsOfOrganization("Org1")
+	assert.Len(t, applications, 1)
+	assert.Equal(t, "app1", applications[0].UUID)
+	subscriptions := GetAllSubscriptionsOfOrganization("Org2")
+	assert.Len(t, subscriptions, 1)
+	assert.Equal(t, "sub2", subscriptions[0].UUID)
+	applicationMappings := GetAllApplicationMappingsOfOrganization("Org1")
+	assert.Len(t, applicationMappings, 1)
+	assert.Equal(t, "map1", applicationMappings[0].UUID)
+
+	DeleteAllOfOrganization("Org1")
+	assert.Empty(t, GetAllApplicationsOfOrganization("Org1"))
+	assert.Len(t, applicationMap, 1)
+	assert.Len(t, subscriptionMap, 1)
+	assert.Len(t, applicationMappingMap, 1)
+	assert.Len(t, applicationKeyMappingMap, 1)
+	assert.Equal(t, "Org2", applicationKeyMappingMap["key2"].Organization)
+}
+
+func TestLoadSubscriptionDataWhileReading(t *testing.T) {
+	var wg sync.WaitGroup
+	started := make(chan struct{})
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(started)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				GetAllApplications()
+				GetAllSubscriptionsOfOrganization("Org1")
+				GetAllApplicationMappings()
+				GetApplicationKeyMappingByApplicationUUID("app1")
+			}
+		}
+	}()
+	<-started
+	for i := 0; i < 1000; i++ {
+		AddAllApplications(map[string]Application{"app1": {UUID: "app1", Organization: "Org1"}})
+		AddAllSubscriptions(map[string]Subscription{"sub1": {UUID: "sub1", Organization: "Org1"}})
+		AddAllApplicationMappings(map[string]ApplicationMapping{"map1": {UUID: "map1", Organization: "Org1"}})
+		AddAllApplicationKeyMappings(map[string]ApplicationKeyMapping{"key1": {ApplicationUUID: "app1"}})
+		DeleteAllSubscriptionsByApplicationsUUID("Org1")
+		DeleteAllApplicationMappingsByApplicationsUUID("map1")
+	}
+	close(stop)
+	wg.Wait()
+	assert.Empty(t, GetAllSubscriptions())
+	assert.Empty(t, GetAllApplicationMappings())
+	assert.Len(t, GetAllApplications(), 1)
+}
@@ -0,0 +1,225 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+)
+
+// Authentication methods of the internal REST server
+const (
+	AuthenticationMTLS = "MTLS"
+	AuthenticationJWT  = "JWT"
+)
+
+const bearerPrefix = "Bearer "
+
+// restAuthenticator authenticates the requests to the internal REST server with the rules of the read and the
+// write endpoints
+type restAuthenticator struct {
+	read      authenticationRule
+	write     authenticationRule
+	jwtConfig jwtValidationConfig
+}
+
+type authenticationRule struct {
+	mtls   bool
+	jwt    bool
+	scopes []string
+}
+
+type jwtValidationConfig struct {
+	issuer    string
+	audience  string
+	publicKey interface{}
+}
+
+// newRestAuthenticator creates the authenticator of the internal REST server from the configurations
+func newRestAuthenticator(conf *config.Config) (*restAuthenticator, error) {
+	read, err := newAuthenticationRule(conf.Agent.RestServer.ReadAuthentication.Methods, conf.Agent.RestServer.ReadAuthentication.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	write, err := newAuthenticationRule(conf.Agent.RestServer.WriteAuthentication.Methods, conf.Agent.RestServer.WriteAuthentication.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if !write.mtls && !write.jwt {
+		logger.LoggerMgtServer.Error("The write endpoints of the internal REST server, such as POST /apis, are not " +
+			"authenticated as no authentication methods are configured for them")
+	}
+	authenticator := &restAuthenticator{read: read, write: write}
+	if read.jwt || write.jwt {
+		jwtConf := conf.Agent.RestServer.JWT
+		if jwtConf.Issuer == "" || jwtConf.CertificatePath == "" {
+			return nil, errors.New("the issuer and the certificate are required to validate the JWTs")
+		}
+		publicKey, err := readPublicKey(jwtConf.CertificatePath)
+		if err != nil {
+			return nil, err
+		}
+		authenticator.jwtConfig = jwtValidationConfig{issuer: jwtConf.Issuer, audience: jwtConf.Audience, publicKey: publicKey}
+	}
+	return authenticator, nil
+}
+
+func newAuthenticationRule(methods []string, scopes []string) (authenticationRule, error) {
+	rule := authenticationRule{scopes: scopes}
+	for _, method := range methods {
+		switch strings.ToUpper(method) {
+		case AuthenticationMTLS:
+			rule.mtls = true
+		case AuthenticationJWT:
+			rule.jwt = true
+		default:
+			return rule, fmt.Errorf("unknown authentication method %q", method)
+		}
+	}
+	return rule, nil
+}
+
+// requiresClientCertificate returns whether the client certificates should be requested in the TLS handshake
+func (authenticator *restAuthenticator) requiresClientCertificate() bool {
+	return authenticator.read.mtls || authenticator.write.mtls
+}
+
+// tlsClientAuth returns the TLS client authentication of the server. The client certificates are verified if
+// given so that the endpoints which do not require mutual TLS can be accessed without them.
+func (authenticator *restAuthenticator) tlsClientAuth() tls.ClientAuthType {
+	if authenticator.requiresClientCertificate() {
+		return tls.VerifyClientCertIfGiven
+	}
+	return tls.NoClientCert
+}
+
+// middleware rejects the requests which do not pass the rule of the endpoint
+func (authenticator *restAuthenticator) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule := authenticator.write
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			rule = authenticator.read
+		}
+		if err := authenticator.authenticate(c.Request, rule); err != nil {
+			logger.LoggerMgtServer.Debugf("Request %s %s is not authenticated: %v", c.Request.Method, c.Request.URL.Path, err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate returns nil when the request passes any of the methods of the rule
+func (authenticator *restAuthenticator) authenticate(req *http.Request, rule authenticationRule) error {
+	if !rule.mtls && !rule.jwt {
+		return nil
+	}
+	var errs []error
+	if rule.mtls {
+		if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+			return nil
+		}
+		errs = append(errs, errors.New("no trusted client certificate"))
+	}
+	if rule.jwt {
+		err := authenticator.validateJWT(req.Header.Get("Authorization"), rule.scopes)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// validateJWT validates the bearer token of the authorization header and checks it has the required scopes
+func (authenticator *restAuthenticator) validateJWT(authorization string, requiredScopes []string) error {
+	if !strings.HasPrefix(authorization, bearerPrefix) {
+		return errors.New("no bearer token")
+	}
+	options := []jwt.ParserOption{
+		jwt.WithIssuer(authenticator.jwtConfig.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+	}
+	if authenticator.jwtConfig.audience != "" {
+		options = append(options, jwt.WithAudience(authenticator.jwtConfig.audience))
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(strings.TrimPrefix(authorization, bearerPrefix), claims, func(*jwt.Token) (interface{}, error) {
+		return authenticator.jwtConfig.publicKey, nil
+	}, options...)
+	if err != nil {
+		return err
+	}
+	scopes := getScopes(claims)
+	for _, requiredScope := range requiredScopes {
+		if !slices.Contains(scopes, requiredScope) {
+			return fmt.Errorf("scope %s is missing in the token", requiredScope)
+		}
+	}
+	return nil
+}
+
+// getScopes returns the scopes of the space separated scope claim or the scp claim array
+func getScopes(claims jwt.MapClaims) []string {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	var scopes []string
+	if scp, ok := claims["scp"].([]interface{}); ok {
+		for _, scope := range scp {
+			if scopeString, ok := scope.(string); ok {
+				scopes = append(scopes, scopeString)
+			}
+		}
+	}
+	return scopes
+}
+
+// readPublicKey reads the public key of a PEM encoded certificate or public key
+func readPublicKey(path string) (interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the JWT certificate: %w", err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	if block.Type == "CERTIFICATE" {
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return certificate.PublicKey, nil
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
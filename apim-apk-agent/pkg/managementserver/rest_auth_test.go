@@ -0,0 +1,132 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+)
+
+const testJWTIssuer = "https://idp.example.com/oauth2/token"
+
+func writeTestPublicKey(t *testing.T, key *rsa.PrivateKey) string {
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}), 0600))
+	return path
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, issuer string, scope string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   issuer,
+		"sub":   "admin",
+		"scope": scope,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestRestAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	conf := &config.Config{}
+	conf.Agent.RestServer.ReadAuthentication.Methods = []string{"mtls", "jwt"}
+	conf.Agent.RestServer.ReadAuthentication.Scopes = []string{"agent:read"}
+	conf.Agent.RestServer.WriteAuthentication.Methods = []string{"JWT"}
+	conf.Agent.RestServer.WriteAuthentication.Scopes = []string{"agent:write"}
+	conf.Agent.RestServer.JWT.Issuer = testJWTIssuer
+	conf.Agent.RestServer.JWT.CertificatePath = writeTestPublicKey(t, key)
+	authenticator, err := newRestAuthenticator(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, authenticator.tlsClientAuth())
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(authenticator.middleware())
+	r.GET("/applications", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/apis", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		clientCert     bool
+		expectedStatus int
+	}{
+		{"read without credentials", http.MethodGet, "/applications", "", false, http.StatusUnauthorized},
+		{"read with client certificate", http.MethodGet, "/applications", "", true, http.StatusOK},
+		{"read with token", http.MethodGet, "/applications", signTestToken(t, key, testJWTIssuer, "agent:read"), false, http.StatusOK},
+		{"read with token without scope", http.MethodGet, "/applications", signTestToken(t, key, testJWTIssuer, "openid"), false, http.StatusUnauthorized},
+		{"write with client certificate", http.MethodPost, "/apis", "", true, http.StatusUnauthorized},
+		{"write with read token", http.MethodPost, "/apis", signTestToken(t, key, testJWTIssuer, "agent:read"), false, http.StatusUnauthorized},
+		{"write with token", http.MethodPost, "/apis", signTestToken(t, key, testJWTIssuer, "agent:read agent:write"), false, http.StatusOK},
+		{"write with token of another issuer", http.MethodPost, "/apis", signTestToken(t, key, "https://other.example.com", "agent:write"), false, http.StatusUnauthorized},
+		{"write with token of another key", http.MethodPost, "/apis", signTestToken(t, otherKey, testJWTIssuer, "agent:write"), false, http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			if test.clientCert {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+			}
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRestAuthenticatorConfigurations(t *testing.T) {
+	authenticator, err := newRestAuthenticator(&config.Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, authenticator.tlsClientAuth())
+
+	conf := &config.Config{}
+	conf.Agent.RestServer.WriteAuthentication.Methods = []string{"Basic"}
+	_, err = newRestAuthenticator(conf)
+	assert.Error(t, err, "Unknown authentication method is accepted")
+
+	conf = &config.Config{}
+	conf.Agent.RestServer.WriteAuthentication.Methods = []string{AuthenticationJWT}
+	_, err = newRestAuthenticator(conf)
+	assert.Error(t, err, "JWT authentication is accepted without an issuer")
+}
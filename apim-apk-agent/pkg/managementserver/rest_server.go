@@ -18,6 +18,7 @@ package managementserver
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -32,6 +33,8 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+const organizationQueryParam = "organization"
+
 func init() {
 }
 
@@ -42,19 +45,44 @@ func StartInternalServer(port uint) {
 	if err == nil {
 		envLabel = cpConfig.ControlPlane.EnvironmentLabels
 	}
+	authenticator, authErr := newRestAuthenticator(cpConfig)
+	if authErr != nil {
+		logger.LoggerMgtServer.Errorf("Internal REST server is not started due to invalid authentication configurations: %v", authErr)
+		return
+	}
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
+	r.Use(authenticator.middleware())
+	if err == nil {
+		registerManagementEndpoints(r, cpConfig)
+	}
 
+	// The resources of a single organization are returned when the organization query parameter is given
 	r.GET("/applications", func(c *gin.Context) {
-		applicationList := GetAllApplications()
+		var applicationList []ResolvedApplication
+		if organization, found := c.GetQuery(organizationQueryParam); found {
+			applicationList = GetAllApplicationsOfOrganization(organization)
+		} else {
+			applicationList = GetAllApplications()
+		}
 		c.JSON(http.StatusOK, ResolvedApplicationList{List: applicationList})
 	})
 	r.GET("/subscriptions", func(c *gin.Context) {
-		subscriptionList := GetAllSubscriptions()
+		var subscriptionList []Subscription
+		if organization, found := c.GetQuery(organizationQueryParam); found {
+			subscriptionList = GetAllSubscriptionsOfOrganization(organization)
+		} else {
+			subscriptionList = GetAllSubscriptions()
+		}
 		c.JSON(http.StatusOK, SubscriptionList{List: subscriptionList})
 	})
 	r.GET("/applicationmappings", func(c *gin.Context) {
-		applicationMappingList := GetAllApplicationMappings()
+		var applicationMappingList []ApplicationMapping
+		if organization, found := c.GetQuery(organizationQueryParam); found {
+			applicationMappingList = GetAllApplicationMappingsOfOrganization(organization)
+		} else {
+			applicationMappingList = GetAllApplicationMappings()
+		}
 		c.JSON(http.StatusOK, ApplicationMappingList{List: applicationMappingList})
 	})
 	r.POST("/apis", func(c *gin.Context) {
@@ -90,32 +118,12 @@ func StartInternalServer(port uint) {
 			}
 			c.JSON(http.StatusOK, map[string]string{"message": "Success"})
 		} else {
-			if strings.EqualFold(event.API.APIType, "rest") && event.API.Definition == "" {
-				event.API.Definition = utils.OpenAPIDefaultYaml
-			}
-			if strings.EqualFold(event.API.APIType, "rest") {
-				yaml, errJSONToYaml := JSONToYAML(event.API.Definition)
-				if errJSONToYaml == nil {
-					event.API.Definition = yaml
-				}
-			}
-			apiYaml, definition := createAPIYaml(&event)
-			deploymentContent := createDeployementYaml(event.API.Vhost)
-			logger.LoggerMgtServer.Debugf("Created apiYaml : %s, \n\n\n created definition file: %s", apiYaml, definition)
-			definitionPath := fmt.Sprintf("%s-%s/Definitions/swagger.yaml", event.API.APIName, event.API.APIVersion)
-			if strings.ToUpper(event.API.APIType) == "GRAPHQL" {
-				definitionPath = fmt.Sprintf("%s-%s/Definitions/schema.graphql", event.API.APIName, event.API.APIVersion)
+			zipFiles, err := createAPIProject(&event)
+			if err != nil {
+				logger.LoggerMgtServer.Errorf("Unable to import API %s: %v", event.API.APIUUID, err)
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
 			}
-			zipFiles := []utils.ZipFile{{
-				Path:    fmt.Sprintf("%s-%s/api.yaml", event.API.APIName, event.API.APIVersion),
-				Content: apiYaml,
-			}, {
-				Path:    fmt.Sprintf("%s-%s/deployment_environments.yaml", event.API.APIName, event.API.APIVersion),
-				Content: deploymentContent,
-			}, {
-				Path:    definitionPath,
-				Content: definition,
-			}}
 			var buf bytes.Buffer
 			if err := utils.CreateZipFile(&buf, zipFiles); err != nil {
 				logger.LoggerMgtServer.Errorf("Error while creating apim zip file for api uuid: %s. Error: %+v", event.API.APIUUID, err)
@@ -130,8 +138,51 @@ func StartInternalServer(port uint) {
 			c.JSON(http.StatusOK, map[string]string{"id": id, "revisionID": revisionID})
 		}
 	})
-	publicKeyLocation, privateKeyLocation, _ := config.GetKeyLocations()
-	r.RunTLS(fmt.Sprintf(":%d", port), publicKeyLocation, privateKeyLocation)
+	publicKeyLocation, privateKeyLocation, truststoreLocation := config.GetKeyLocations()
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: r,
+		TLSConfig: &tls.Config{
+			ClientAuth: authenticator.tlsClientAuth(),
+		},
+	}
+	if authenticator.requiresClientCertificate() {
+		server.TLSConfig.ClientCAs = config.GetTrustedCertPool(truststoreLocation)
+	}
+	if err := server.ListenAndServeTLS(publicKeyLocation, privateKeyLocation); err != nil {
+		logger.LoggerMgtServer.Errorf("Internal REST server stopped: %v", err)
+	}
+}
+
+// createAPIProject creates the files of the API project imported to API Manager for an API defined in APK
+func createAPIProject(event *APICPEvent) ([]utils.ZipFile, error) {
+	apiTypeMapping, err := getAPITypeMapping(event.API.APIType)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(event.API.APIType, "rest") && event.API.Definition == "" {
+		event.API.Definition = utils.OpenAPIDefaultYaml
+	}
+	if strings.EqualFold(event.API.APIType, "rest") {
+		yaml, errJSONToYaml := JSONToYAML(event.API.Definition)
+		if errJSONToYaml == nil {
+			event.API.Definition = yaml
+		}
+	}
+	apiYaml, definition := createAPIYaml(event)
+	deploymentContent := createDeployementYaml(event.API.Vhost)
+	logger.LoggerMgtServer.Debugf("Created apiYaml : %s, \n\n\n created definition file: %s", apiYaml, definition)
+	definitionPath := fmt.Sprintf("%s-%s/Definitions/%s", event.API.APIName, event.API.APIVersion, apiTypeMapping.definitionFile)
+	return []utils.ZipFile{{
+		Path:    fmt.Sprintf("%s-%s/api.yaml", event.API.APIName, event.API.APIVersion),
+		Content: apiYaml,
+	}, {
+		Path:    fmt.Sprintf("%s-%s/deployment_environments.yaml", event.API.APIName, event.API.APIVersion),
+		Content: deploymentContent,
+	}, {
+		Path:    definitionPath,
+		Content: definition,
+	}}, nil
 }
 
 func createAPIYaml(apiCPEvent *APICPEvent) (string, string) {
@@ -156,9 +207,14 @@ func createAPIYaml(apiCPEvent *APICPEvent) (string, string) {
 	}
 	authHeader := apiCPEvent.API.AuthHeader
 	apiKeyHeader := apiCPEvent.API.APIKeyHeader
-	apiType := "HTTP"
-	if apiCPEvent.API.APIType == "GraphQL" {
-		apiType = "GRAPHQL"
+	apiTypeMapping, err := getAPITypeMapping(apiCPEvent.API.APIType)
+	if err != nil {
+		logger.LoggerMgtServer.Errorf("%v. Hence importing API %s as a REST API", err, apiCPEvent.API.APIUUID)
+		apiTypeMapping = apiTypeMappings[apkAPITypeREST]
+	}
+	endpointType := apiCPEvent.API.EndpointProtocol
+	if apiTypeMapping.endpointType != "" {
+		endpointType = apiTypeMapping.endpointType
 	}
 
 	var subTypeConfiguration = make(map[string]interface{})
@@ -189,10 +245,10 @@ func createAPIYaml(apiCPEvent *APICPEvent) (string, string) {
 			"isRevision":                   false,
 			"enableSchemaValidation":       false,
 			"enableSubscriberVerification": false,
-			"type":                         apiType,
-			"transport":                    []string{"http", "https"},
+			"type":                         apiTypeMapping.apimType,
+			"transport":                    apiTypeMapping.transports,
 			"endpointConfig": map[string]interface{}{
-				"endpoint_type": apiCPEvent.API.EndpointProtocol,
+				"endpoint_type": endpointType,
 				"sandbox_endpoints": map[string]interface{}{
 					"url": sandEndpoint,
 				},
@@ -279,6 +335,9 @@ func createAPIYaml(apiCPEvent *APICPEvent) (string, string) {
 	}
 	logger.LoggerMgtServer.Debugf("Prepared yaml : %+v", data)
 	definition := apiCPEvent.API.Definition
+	if isWebSocketAPI(apiCPEvent.API.APIType) && definition == "" {
+		definition = createDefaultAsyncAPI(apiCPEvent.API, operations)
+	}
 	if strings.EqualFold(apiCPEvent.API.APIType, "rest") {
 		// Process OpenAPI and set required values
 		openAPI, errConvertYaml := ConvertYAMLToMap(definition)
@@ -467,17 +526,25 @@ func extractOperations(event APICPEvent) ([]APIOperation, []ScopeWrapper, error)
 	var requestOperationPolicies []OperationPolicy
 	var responseOperationPolicies []OperationPolicy
 	scopewrappers := map[string]ScopeWrapper{}
-	if strings.ToUpper(event.API.APIType) == "GRAPHQL" {
+	if isWebSocketAPI(event.API.APIType) && len(event.API.Operations) == 0 {
+		// The messages of all the topics can be published and subscribed when no operations are defined
+		event.API.Operations = []OperationFromDP{{Path: "/*", Verb: "SUBSCRIBE"}, {Path: "/*", Verb: "PUBLISH"}}
+	}
+	switch strings.ToUpper(event.API.APIType) {
+	case apkAPITypeGraphQL, apkAPITypeGRPC, apkAPITypeWS, apkAPITypeWebSocket:
 		for _, operation := range event.API.Operations {
+			addScopeWrappers(scopewrappers, operation.Scopes)
 			apiOp := APIOperation{
 				Target:           operation.Path,
 				Verb:             operation.Verb,
 				AuthType:         "Application & Application User",
 				ThrottlingPolicy: "Unlimited",
+				Scopes:           operation.Scopes,
 			}
 			apiOperations = append(apiOperations, apiOp)
 		}
-	} else if strings.ToUpper(event.API.APIType) == "REST" {
+		return apiOperations, getScopeWrappers(scopewrappers), nil
+	case apkAPITypeREST:
 		var openAPIPaths OpenAPIPaths
 		openAPI := event.API.Definition
 		if err := yaml.Unmarshal([]byte(openAPI), &openAPIPaths); err != nil {
@@ -492,17 +559,7 @@ func extractOperations(event APICPEvent) ([]APIOperation, []ScopeWrapper, error)
 				}
 				operationFromDP := *ptrToOperationFromDP
 				scopes := operationFromDP.Scopes
-				for _, scope := range scopes {
-					scopewrappers[scope] = ScopeWrapper{
-						Scope: Scope{
-							Name:        scope,
-							DisplayName: scope,
-							Description: scope,
-							Bindings:    []string{},
-						},
-						Shared: false,
-					}
-				}
+				addScopeWrappers(scopewrappers, scopes)
 				// Process filters
 				for _, operationLevelFilter := range operationFromDP.Filters {
 					switch filter := operationLevelFilter.(type) {
@@ -616,15 +673,34 @@ func extractOperations(event APICPEvent) ([]APIOperation, []ScopeWrapper, error)
 				apiOperations = append(apiOperations, apiOp)
 			}
 		}
-		var scopeWrapperSlice []ScopeWrapper
-		for _, value := range scopewrappers {
-			scopeWrapperSlice = append(scopeWrapperSlice, value)
-		}
-		return apiOperations, scopeWrapperSlice, nil
+		return apiOperations, getScopeWrappers(scopewrappers), nil
 	}
 	return []APIOperation{}, []ScopeWrapper{}, nil
 }
 
+// addScopeWrappers adds the scopes of an operation to the scope wrappers of the API
+func addScopeWrappers(scopewrappers map[string]ScopeWrapper, scopes []string) {
+	for _, scope := range scopes {
+		scopewrappers[scope] = ScopeWrapper{
+			Scope: Scope{
+				Name:        scope,
+				DisplayName: scope,
+				Description: scope,
+				Bindings:    []string{},
+			},
+			Shared: false,
+		}
+	}
+}
+
+func getScopeWrappers(scopewrappers map[string]ScopeWrapper) []ScopeWrapper {
+	var scopeWrapperSlice []ScopeWrapper
+	for _, value := range scopewrappers {
+		scopeWrapperSlice = append(scopeWrapperSlice, value)
+	}
+	return scopeWrapperSlice
+}
+
 func findMatchingAPKOperation(path string, verb string, operations []OperationFromDP) *OperationFromDP {
 	for _, operationFromDP := range operations {
 		if strings.EqualFold(operationFromDP.Verb, verb) {
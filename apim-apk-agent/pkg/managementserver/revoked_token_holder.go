@@ -0,0 +1,98 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
+)
+
+const (
+	// TokenRevokedEventType is the event type used when streaming revoked tokens to the common controllers.
+	// The revoked token (JTI) is carried in the Uuid field and its expiry time (epoch millis) in the TimeStamp field.
+	TokenRevokedEventType = "TOKEN_REVOKED"
+	// defaultRevokedTokenRetention is used when the revocation event does not carry an expiry time.
+	defaultRevokedTokenRetention = time.Hour
+)
+
+var (
+	revokedTokenMap   map[string]RevokedToken
+	revokedTokenMutex sync.RWMutex
+)
+
+func init() {
+	revokedTokenMap = make(map[string]RevokedToken)
+}
+
+// AddRevokedToken adds a revoked token to the revokedTokenMap. Expired tokens are purged at the same time.
+func AddRevokedToken(revokedToken RevokedToken) {
+	if revokedToken.ExpiryTime <= 0 {
+		revokedToken.ExpiryTime = time.Now().Add(defaultRevokedTokenRetention).UnixMilli()
+	}
+	revokedTokenMutex.Lock()
+	defer revokedTokenMutex.Unlock()
+	purgeExpiredRevokedTokens(time.Now().UnixMilli())
+	revokedTokenMap[revokedToken.Token] = revokedToken
+}
+
+// IsTokenRevoked checks whether the given token is in the revokedTokenMap and is not yet expired
+func IsTokenRevoked(token string) bool {
+	revokedTokenMutex.RLock()
+	defer revokedTokenMutex.RUnlock()
+	revokedToken, ok := revokedTokenMap[token]
+	return ok && revokedToken.ExpiryTime > time.Now().UnixMilli()
+}
+
+// GetAllRevokedTokens returns all the revoked tokens in the revokedTokenMap which are not yet expired
+func GetAllRevokedTokens() []RevokedToken {
+	revokedTokenMutex.Lock()
+	defer revokedTokenMutex.Unlock()
+	purgeExpiredRevokedTokens(time.Now().UnixMilli())
+	revokedTokens := make([]RevokedToken, 0, len(revokedTokenMap))
+	for _, revokedToken := range revokedTokenMap {
+		revokedTokens = append(revokedTokens, revokedToken)
+	}
+	return revokedTokens
+}
+
+// DeleteAllRevokedTokens deletes all the revoked tokens in the revokedTokenMap
+func DeleteAllRevokedTokens() {
+	revokedTokenMutex.Lock()
+	defer revokedTokenMutex.Unlock()
+	revokedTokenMap = make(map[string]RevokedToken)
+}
+
+// CreateRevokedTokenEvent creates the event to be streamed to the common controllers for a revoked token
+func CreateRevokedTokenEvent(revokedToken RevokedToken) *subscription.Event {
+	return &subscription.Event{
+		Uuid:      revokedToken.Token,
+		Type:      TokenRevokedEventType,
+		TimeStamp: revokedToken.ExpiryTime,
+	}
+}
+
+// purgeExpiredRevokedTokens removes the expired tokens. Caller should hold the write lock.
+func purgeExpiredRevokedTokens(now int64) {
+	for token, revokedToken := range revokedTokenMap {
+		if revokedToken.ExpiryTime <= now {
+			delete(revokedTokenMap, token)
+		}
+	}
+}
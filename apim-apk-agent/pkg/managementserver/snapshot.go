@@ -0,0 +1,148 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+)
+
+// Snapshot holds the applications, subscriptions, application mappings and application key mappings known to the
+// agent so that they can be served before the control plane is reachable after a restart. It also holds the revision
+// acknowledgements which are not yet delivered to the control plane so that they are not lost by a restart.
+type Snapshot struct {
+	Applications           map[string]Application           `json:"applications"`
+	Subscriptions          map[string]Subscription          `json:"subscriptions"`
+	ApplicationMappings    map[string]ApplicationMapping    `json:"applicationMappings"`
+	ApplicationKeyMappings map[string]ApplicationKeyMapping `json:"applicationKeyMappings"`
+	RevisionAcks           []RevisionAck                    `json:"revisionAcks,omitempty"`
+	// TimeStamp is the time in milliseconds the snapshot was taken
+	TimeStamp int64 `json:"timeStamp"`
+}
+
+// RevisionAck is a revision acknowledgement which is not yet delivered to the control plane
+type RevisionAck struct {
+	Type     string `json:"type"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Payload  []byte `json:"payload"`
+	Attempts int    `json:"attempts"`
+}
+
+// SnapshotStore persists the snapshots of the agent state
+type SnapshotStore interface {
+	// Save persists the given snapshot replacing the previous one
+	Save(snapshot *Snapshot) error
+	// Load returns the last saved snapshot. A nil snapshot is returned when nothing is saved yet.
+	Load() (*Snapshot, error)
+}
+
+var (
+	snapshotStore    SnapshotStore
+	pendingSnapshots chan *Snapshot
+	// revisionAcks are the undelivered revision acknowledgements, guarded by the subscriptionDataMutex
+	revisionAcks []RevisionAck
+)
+
+// RestoreSnapshot loads the last snapshot from the given store into the in-memory maps. It returns whether a
+// snapshot was restored.
+func RestoreSnapshot(store SnapshotStore) (bool, error) {
+	snapshot, err := store.Load()
+	if err != nil || snapshot == nil {
+		return false, err
+	}
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
+	applicationMap = nonNilMap(snapshot.Applications)
+	subscriptionMap = nonNilMap(snapshot.Subscriptions)
+	applicationMappingMap = nonNilMap(snapshot.ApplicationMappings)
+	applicationKeyMappingMap = nonNilMap(snapshot.ApplicationKeyMappings)
+	revisionAcks = snapshot.RevisionAcks
+	logger.LoggerMgtServer.Infof("Restored %d applications, %d subscriptions and %d revision acknowledgements from "+
+		"the snapshot taken at %v", len(applicationMap), len(subscriptionMap), len(revisionAcks),
+		time.UnixMilli(snapshot.TimeStamp))
+	return true, nil
+}
+
+// EnableSnapshots saves a snapshot to the given store after each change of the applications, subscriptions,
+// application mappings or application key mappings. The snapshots are saved in the background and the
+// intermediate snapshots of consecutive changes are skipped while a snapshot is being saved.
+func EnableSnapshots(store SnapshotStore) {
+	pendingSnapshots = make(chan *Snapshot, 1)
+	snapshotStore = store
+	go func() {
+		for snapshot := range pendingSnapshots {
+			if err := store.Save(snapshot); err != nil {
+				logger.LoggerMgtServer.Errorf("Error while saving the snapshot: %v", err)
+			}
+		}
+	}()
+}
+
+// GetRevisionAcks returns the undelivered revision acknowledgements, which are the ones restored from the snapshot
+// until they are replaced with SetRevisionAcks
+func GetRevisionAcks() []RevisionAck {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
+	return slices.Clone(revisionAcks)
+}
+
+// SetRevisionAcks replaces the undelivered revision acknowledgements saved with the snapshots
+func SetRevisionAcks(acks []RevisionAck) {
+	subscriptionDataMutex.Lock()
+	defer subscriptionDataMutex.Unlock()
+	revisionAcks = acks
+	snapshotChanged()
+}
+
+// takeSnapshot returns a copy of the current state. The caller must hold the subscriptionDataMutex.
+func takeSnapshot() *Snapshot {
+	return &Snapshot{
+		Applications:           maps.Clone(applicationMap),
+		Subscriptions:          maps.Clone(subscriptionMap),
+		ApplicationMappings:    maps.Clone(applicationMappingMap),
+		ApplicationKeyMappings: maps.Clone(applicationKeyMappingMap),
+		RevisionAcks:           slices.Clone(revisionAcks),
+		TimeStamp:              time.Now().UnixMilli(),
+	}
+}
+
+// snapshotChanged queues a snapshot of the current state to be saved. The state is copied by the caller so that
+// the maps are not read by the goroutine saving the snapshots. The caller must hold the subscriptionDataMutex.
+func snapshotChanged() {
+	if snapshotStore == nil {
+		return
+	}
+	snapshot := takeSnapshot()
+	// Replace the snapshot which is not yet picked up for saving
+	select {
+	case <-pendingSnapshots:
+	default:
+	}
+	pendingSnapshots <- snapshot
+}
+
+func nonNilMap[V any](source map[string]V) map[string]V {
+	if source == nil {
+		return make(map[string]V)
+	}
+	return source
+}
@@ -0,0 +1,133 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// snapshotDataKey is the key of the gzipped snapshot in the ConfigMap or the Secret
+const snapshotDataKey = "snapshot.json.gz"
+
+// KubernetesSnapshotStore saves the snapshots to a ConfigMap or a Secret. The snapshot is gzipped to keep it
+// within the size limit of the resource.
+type KubernetesSnapshotStore struct {
+	reader    client.Reader
+	writer    client.Client
+	namespace string
+	name      string
+	useSecret bool
+}
+
+// NewKubernetesSnapshotStore returns a snapshot store saving the snapshots to the ConfigMap, or the Secret when
+// useSecret is true, with the given name. The snapshot is read through the reader so that it can be loaded before
+// the cache of the writer is started.
+func NewKubernetesSnapshotStore(reader client.Reader, writer client.Client, namespace string, name string,
+	useSecret bool) *KubernetesSnapshotStore {
+	return &KubernetesSnapshotStore{reader: reader, writer: writer, namespace: namespace, name: name, useSecret: useSecret}
+}
+
+// Save creates or updates the ConfigMap or the Secret with the snapshot
+func (store *KubernetesSnapshotStore) Save(snapshot *Snapshot) error {
+	content, err := compressSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	object := store.newObject()
+	err = store.reader.Get(context.Background(), client.ObjectKeyFromObject(object), object)
+	if err != nil && !k8error.IsNotFound(err) {
+		return err
+	}
+	notFound := err != nil
+	if secret, isSecret := object.(*corev1.Secret); isSecret {
+		secret.Data = map[string][]byte{snapshotDataKey: content}
+	} else {
+		object.(*corev1.ConfigMap).BinaryData = map[string][]byte{snapshotDataKey: content}
+	}
+	if notFound {
+		return store.writer.Create(context.Background(), object)
+	}
+	return store.writer.Update(context.Background(), object)
+}
+
+// Load reads the snapshot from the ConfigMap or the Secret
+func (store *KubernetesSnapshotStore) Load() (*Snapshot, error) {
+	object := store.newObject()
+	if err := store.reader.Get(context.Background(), client.ObjectKeyFromObject(object), object); err != nil {
+		if k8error.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var content []byte
+	if secret, isSecret := object.(*corev1.Secret); isSecret {
+		content = secret.Data[snapshotDataKey]
+	} else {
+		content = object.(*corev1.ConfigMap).BinaryData[snapshotDataKey]
+	}
+	if content == nil {
+		return nil, nil
+	}
+	return decompressSnapshot(content)
+}
+
+func (store *KubernetesSnapshotStore) newObject() client.Object {
+	objectMeta := metav1.ObjectMeta{Name: store.name, Namespace: store.namespace}
+	if store.useSecret {
+		return &corev1.Secret{ObjectMeta: objectMeta}
+	}
+	return &corev1.ConfigMap{ObjectMeta: objectMeta}
+}
+
+func compressSnapshot(snapshot *Snapshot) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if err := json.NewEncoder(writer).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func decompressSnapshot(content []byte) (*Snapshot, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(decompressed, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,120 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package managementserver
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestSnapshot() *Snapshot {
+	return &Snapshot{
+		Applications:           map[string]Application{"app1": {UUID: "app1", Name: "Test App", Organization: "Org1"}},
+		Subscriptions:          map[string]Subscription{"sub1": {UUID: "sub1", Organization: "Org1", SubscribedAPI: &SubscribedAPI{Name: "Test API", Version: "v1"}}},
+		ApplicationMappings:    map[string]ApplicationMapping{"map1": {UUID: "map1", ApplicationRef: "app1", SubscriptionRef: "sub1"}},
+		ApplicationKeyMappings: map[string]ApplicationKeyMapping{"key1": {ApplicationUUID: "app1", KeyType: "PRODUCTION"}},
+		RevisionAcks:           []RevisionAck{{Type: "deployed", Method: "PATCH", Path: "internal/data/v1/apis/deployed-revisions", Payload: []byte(`[{"apiId":"api1"}]`), Attempts: 2}},
+		TimeStamp:              123456789,
+	}
+}
+
+func TestFileSnapshotStore(t *testing.T) {
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshot", "agent-snapshot.json"))
+	snapshot, err := store.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	assert.NoError(t, store.Save(newTestSnapshot()))
+	snapshot, err = store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, newTestSnapshot(), snapshot)
+}
+
+func TestKubernetesSnapshotStore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	for _, useSecret := range []bool{false, true} {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		store := NewKubernetesSnapshotStore(k8sClient, k8sClient, "apk", "apim-apk-agent-snapshot", useSecret)
+		snapshot, err := store.Load()
+		assert.NoError(t, err)
+		assert.Nil(t, snapshot)
+
+		assert.NoError(t, store.Save(&Snapshot{TimeStamp: 1}))
+		assert.NoError(t, store.Save(newTestSnapshot()))
+		snapshot, err = store.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, newTestSnapshot(), snapshot)
+	}
+}
+
+func TestSnapshotSavedAfterChange(t *testing.T) {
+	previousStore, previousPendingSnapshots := snapshotStore, pendingSnapshots
+	t.Cleanup(func() { snapshotStore, pendingSnapshots = previousStore, previousPendingSnapshots })
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "agent-snapshot.json"))
+	EnableSnapshots(store)
+	DeleteAllApplications()
+	AddApplication(Application{UUID: "app1", Name: "Test App", Organization: "Org1"})
+
+	assert.Eventually(t, func() bool {
+		snapshot, err := store.Load()
+		return err == nil && snapshot != nil && snapshot.Applications["app1"].Name == "Test App"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Clear the state without saving another snapshot to the store being restored
+	snapshotStore = nil
+	DeleteAllApplications()
+	restored, err := RestoreSnapshot(NewFileSnapshotStore(filepath.Join(t.TempDir(), "missing.json")))
+	assert.NoError(t, err)
+	assert.False(t, restored)
+	restored, err = RestoreSnapshot(store)
+	assert.NoError(t, err)
+	assert.True(t, restored)
+	assert.Equal(t, "Test App", GetApplication("app1").Name)
+}
+
+func TestRevisionAcksSavedWithSnapshot(t *testing.T) {
+	previousStore, previousPendingSnapshots := snapshotStore, pendingSnapshots
+	t.Cleanup(func() {
+		snapshotStore, pendingSnapshots = previousStore, previousPendingSnapshots
+		revisionAcks = nil
+	})
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "agent-snapshot.json"))
+	EnableSnapshots(store)
+	acks := []RevisionAck{{Type: "undeployed", Method: "POST", Path: "internal/data/v1/apis/undeployed-revision",
+		Payload: []byte(`{"apiUUID":"api1"}`), Attempts: 1}}
+	SetRevisionAcks(acks)
+
+	assert.Eventually(t, func() bool {
+		snapshot, err := store.Load()
+		return err == nil && snapshot != nil && len(snapshot.RevisionAcks) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	snapshotStore = nil
+	revisionAcks = nil
+	restored, err := RestoreSnapshot(store)
+	assert.NoError(t, err)
+	assert.True(t, restored)
+	assert.Equal(t, acks, GetRevisionAcks())
+}
@@ -17,6 +17,11 @@
 
 package managementserver
 
+import (
+	eventHub "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/eventhub/types"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/logging"
+)
+
 // Subscription for struct subscription
 type Subscription struct {
 	SubStatus     string         `json:"subStatus,omitempty"`
@@ -245,3 +250,69 @@ type CORSPolicy struct {
 	AccessControlMaxAge           *int     `json:"accessControlMaxAge,omitempty"`
 	AccessControlAllowMethods     []string `json:"accessControlAllowMethods,omitempty"`
 }
+
+// RevokedToken holds a revoked token received from the control plane
+type RevokedToken struct {
+	Token      string `json:"token"`
+	ExpiryTime int64  `json:"expiryTime"`
+	TokenType  string `json:"tokenType,omitempty"`
+}
+
+// DeployedAPI for struct API deployed to the data plane by the agent
+type DeployedAPI struct {
+	UUID         string `json:"uuid,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Version      string `json:"version,omitempty"`
+	BasePath     string `json:"basePath,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	RevisionID   string `json:"revisionId,omitempty"`
+	CRName       string `json:"crName,omitempty"`
+	Namespace    string `json:"namespace,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Accepted     bool   `json:"accepted"`
+	Message      string `json:"message,omitempty"`
+	// TransitionTime is the last time the status of the API CR changed in the RFC 3339 format
+	TransitionTime string `json:"transitionTime,omitempty"`
+}
+
+// DeployedAPIList for struct list of deployed APIs
+type DeployedAPIList struct {
+	List []DeployedAPI `json:"list"`
+}
+
+// DeployedKeyManager for struct key manager deployed to the data plane by the agent
+type DeployedKeyManager struct {
+	Name         string `json:"name,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Issuer       string `json:"issuer,omitempty"`
+	CRName       string `json:"crName,omitempty"`
+	Namespace    string `json:"namespace,omitempty"`
+}
+
+// DeployedKeyManagerList for struct list of deployed key managers
+type DeployedKeyManagerList struct {
+	List []DeployedKeyManager `json:"list"`
+}
+
+// RateLimitPolicyState for struct rate limit policies known to the agent
+type RateLimitPolicyState struct {
+	RateLimitPolicies    []eventHub.RateLimitPolicy    `json:"rateLimitPolicies"`
+	SubscriptionPolicies []eventHub.SubscriptionPolicy `json:"subscriptionPolicies"`
+}
+
+// AIProviderList for struct list of AI providers known to the agent
+type AIProviderList struct {
+	List []eventHub.AIProvider `json:"list"`
+}
+
+// ClientConnectionState for struct clients connected to the event stream
+type ClientConnectionState struct {
+	EventStreamID       string   `json:"eventStreamId"`
+	LatestEventSequence uint64   `json:"latestEventSequence"`
+	Clients             []string `json:"clients"`
+}
+
+// LogLevelList for struct list of package log levels
+type LogLevelList struct {
+	List []logging.PackageLogLevel `json:"list"`
+}
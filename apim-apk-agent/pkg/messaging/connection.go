@@ -28,6 +28,7 @@ import (
 
 	"github.com/streadway/amqp"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
 )
 
 var (
@@ -227,16 +228,24 @@ func handleEvent(c *Consumer, key string) error {
 	}
 
 	logger.LoggerMsg.Infof("declared Exchange, declaring Queue %q", key+"queue")
-	queue, err := c.Channel.QueueDeclare(
-		"",    // name of the queue
-		false, // durable
-		true,  // delete when usused
-		false, // exclusive
-		false, // noWait
-		nil,   // arguments
-	)
-	if err != nil {
-		return fmt.Errorf("Error while declaring queue: %s", err)
+	var queue amqp.Queue
+	if durableQueueOptions.Enabled {
+		queue, err = declareDurableQueue(c.Channel, key)
+		if err != nil {
+			return err
+		}
+	} else {
+		queue, err = c.Channel.QueueDeclare(
+			"",    // name of the queue
+			false, // durable
+			true,  // delete when usused
+			false, // exclusive
+			false, // noWait
+			nil,   // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("Error while declaring queue: %s", err)
+		}
 	}
 
 	logger.LoggerMsg.Debugf("Binding to Exchange (key %q) after declaring the Queue (%q %d messages, %d consumers)",
@@ -263,28 +272,37 @@ func handleEvent(c *Consumer, key string) error {
 	)
 	if strings.EqualFold(key, notification) {
 		for event := range deliveries {
+			metrics.RecordEventConsumed(key)
 			NotificationChannel <- event
 		}
 	} else if strings.EqualFold(key, keymanager) {
 		for event := range deliveries {
+			metrics.RecordEventConsumed(key)
 			KeyManagerChannel <- event
 		}
 	} else if strings.EqualFold(key, tokenRevocation) {
 		for event := range deliveries {
+			metrics.RecordEventConsumed(key)
 			RevokedTokenChannel <- event
 		}
 	} else if strings.EqualFold(key, throttleData) {
 		for event := range deliveries {
+			metrics.RecordEventConsumed(key)
 			ThrottleDataChannel <- event
 		}
 	}
 	return nil
 }
 
-// InitiateJMSConnection to pass event consumption
-func InitiateJMSConnection(eventListeningEndpoints []string) error {
+// InitiateJMSConnection to pass event consumption. The events are consumed through durable queues when they are
+// enabled in the given options.
+func InitiateJMSConnection(eventListeningEndpoints []string, durableQueue DurableQueueOptions) error {
 	var err error
+	if err = ValidateDurableQueueOptions(durableQueue); err != nil {
+		return err
+	}
 	EventListeningEndpoints = eventListeningEndpoints
+	durableQueueOptions = durableQueue
 	bindingKeys := []string{notification, keymanager, tokenRevocation, throttleData}
 	RabbitConn, err = connectToRabbitMQ()
 
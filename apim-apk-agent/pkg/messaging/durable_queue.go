@@ -0,0 +1,135 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/streadway/amqp"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+)
+
+const (
+	deadLetterExchangeArg  string = "x-dead-letter-exchange"
+	queueExpiryArg         string = "x-expires"
+	deadLetterQueueSuffix  string = "dead-letter"
+	deadLetterRoutingKey   string = "#"
+	deadLetterExchangeType string = "topic"
+	queueNameSeparator     string = "."
+)
+
+// DurableQueueOptions holds the configurations of the durable queues used to consume the events
+type DurableQueueOptions struct {
+	Enabled            bool
+	AgentIdentity      string
+	DeadLetterExchange string
+	// Expiry is the time the queues are kept after they are last used. The queues do not expire when it is 0.
+	Expiry time.Duration
+}
+
+var durableQueueOptions DurableQueueOptions
+
+// ValidateDurableQueueOptions returns an error when the durable queues are enabled without an agent identity. The
+// identity is not defaulted as the agents sharing it would consume the events of each other.
+func ValidateDurableQueueOptions(durableQueue DurableQueueOptions) error {
+	if durableQueue.Enabled && durableQueue.AgentIdentity == "" {
+		return errors.New("agentIdentity is required when the durable queues are enabled")
+	}
+	return nil
+}
+
+// declareDurableQueue declares the dead letter exchange and a durable queue named after the agent identity and the
+// given key. The events rejected from the queue are routed to the dead letter exchange and retained in the dead
+// letter queue of the agent. The durable queue is deleted by the broker when it is not used until it expires while
+// the dead letter queue, which is never consumed by the agent, is kept.
+func declareDurableQueue(channel *amqp.Channel, key string) (amqp.Queue, error) {
+	agentIdentity := durableQueueOptions.AgentIdentity
+	if err := channel.ExchangeDeclare(
+		durableQueueOptions.DeadLetterExchange, // name of the exchange
+		deadLetterExchangeType,                 // type
+		true,                                   // durable
+		false,                                  // delete when complete
+		false,                                  // internal
+		false,                                  // noWait
+		nil,                                    // arguments
+	); err != nil {
+		return amqp.Queue{}, fmt.Errorf("Dead Letter Exchange Declare: %s", err)
+	}
+	deadLetterQueue, err := channel.QueueDeclare(
+		agentIdentity+queueNameSeparator+deadLetterQueueSuffix, // name of the queue
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // noWait
+		nil,   // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("Error while declaring dead letter queue: %s", err)
+	}
+	if err = channel.QueueBind(deadLetterQueue.Name, deadLetterRoutingKey, durableQueueOptions.DeadLetterExchange,
+		false, nil); err != nil {
+		return amqp.Queue{}, fmt.Errorf("Dead Letter Queue Bind: %s", err)
+	}
+
+	queue, err := channel.QueueDeclare(
+		agentIdentity+queueNameSeparator+key, // name of the queue
+		true,                                 // durable
+		false,                                // delete when unused
+		false,                                // exclusive
+		false,                                // noWait
+		getDurableQueueArgs(),                // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("Error while declaring durable queue: %s", err)
+	}
+	return queue, nil
+}
+
+// getDurableQueueArgs returns the arguments of the durable queues
+func getDurableQueueArgs() amqp.Table {
+	args := amqp.Table{deadLetterExchangeArg: durableQueueOptions.DeadLetterExchange}
+	if durableQueueOptions.Expiry > 0 {
+		args[queueExpiryArg] = durableQueueOptions.Expiry.Milliseconds()
+	}
+	return args
+}
+
+// Ack acknowledges a delivery which is processed successfully
+func Ack(delivery amqp.Delivery) {
+	if err := delivery.Ack(false); err != nil {
+		logger.LoggerMsg.Errorf("Error while acknowledging the delivery %d: %v", delivery.DeliveryTag, err)
+	}
+}
+
+// Nack rejects a delivery which is failed to process. A poison delivery, which can never be processed, is sent to
+// the dead letter exchange right away while the other deliveries are requeued once and sent to the dead letter
+// exchange when they fail again. The rejected deliveries are dropped instead when the durable queues are disabled.
+func Nack(delivery amqp.Delivery, poison bool) {
+	requeue := !poison && !delivery.Redelivered
+	if requeue {
+		logger.LoggerMsg.Infof("Requeueing the delivery %d as it is failed to process", delivery.DeliveryTag)
+	} else {
+		logger.LoggerMsg.Warnf("Sending the delivery %d to the dead letter exchange as it cannot be processed",
+			delivery.DeliveryTag)
+	}
+	if err := delivery.Nack(false, requeue); err != nil {
+		logger.LoggerMsg.Errorf("Error while rejecting the delivery %d: %v", delivery.DeliveryTag, err)
+	}
+}
@@ -20,6 +20,7 @@ package messaging
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/streadway/amqp"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
@@ -32,6 +33,9 @@ var (
 	rabbitCloseError chan *amqp.Error
 	// amqpURIArray represents an array of amqpFailoverURL objects
 	amqpURIArray = make([]amqpFailoverURL, 0)
+	// consumerConnections holds the connection of the consumer of each binding key
+	consumerConnections     = make(map[string]*amqp.Connection)
+	consumerConnectionsLock sync.Mutex
 )
 
 const (
@@ -60,6 +64,9 @@ func startConsumer(key string) *Consumer {
 	}
 
 	c.Conn = RabbitConn
+	consumerConnectionsLock.Lock()
+	consumerConnections[key] = c.Conn
+	consumerConnectionsLock.Unlock()
 	go func() {
 		c.reconnect(key)
 	}()
@@ -105,3 +112,24 @@ type Consumer struct {
 	Tag     string
 	Done    chan error
 }
+
+// Reconnect closes the connections of the consumers so that they reconnect to the given event listening endpoints
+// with the given durable queue options
+func Reconnect(eventListeningEndpoints []string, durableQueue DurableQueueOptions) {
+	consumerConnectionsLock.Lock()
+	defer consumerConnectionsLock.Unlock()
+	EventListeningEndpoints = eventListeningEndpoints
+	durableQueueOptions = durableQueue
+	amqpURIArray = retrieveAMQPURLList()
+	closed := make(map[*amqp.Connection]bool)
+	for key, conn := range consumerConnections {
+		if conn == nil || closed[conn] {
+			continue
+		}
+		closed[conn] = true
+		logger.LoggerMsg.Infof("Closing the connection of the consumer for %s to reconnect", key)
+		if err := conn.Close(); err != nil && err != amqp.ErrClosed {
+			logger.LoggerMsg.Errorf("Error while closing the connection of the consumer for %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2024, WSO2 LLC. (https://www.wso2.com)
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	eventsConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_apk_agent_events_consumed_total",
+		Help: "Total number of events consumed from the control plane broker.",
+	}, []string{"type"})
+	controlPlaneRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "apim_apk_agent_control_plane_request_duration_seconds",
+		Help:    "Time taken to fetch the resources from the control plane.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"resource", "result"})
+	controlPlaneRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_apk_agent_control_plane_retries_total",
+		Help: "Total number of retried control plane requests.",
+	}, []string{"resource"})
+	workerPoolQueuedJobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "apim_apk_agent_worker_pool_queued_jobs",
+		Help: "Number of control plane requests waiting in the worker pool queue.",
+	})
+	workerPoolCapacity = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "apim_apk_agent_worker_pool_capacity",
+		Help: "Maximum number of control plane requests the worker pool queue can hold.",
+	})
+	crApplies = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_apk_agent_cr_applies_total",
+		Help: "Total number of CRs applied to the data plane.",
+	}, []string{"kind", "result"})
+	revisionAcks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_apk_agent_revision_acks_total",
+		Help: "Total number of revision acknowledgements sent to the control plane.",
+	}, []string{"type", "result"})
+	revisionAckDeadLetters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_apk_agent_revision_ack_dead_letters_total",
+		Help: "Total number of revision acknowledgements given up without being delivered to the control plane.",
+	}, []string{"type"})
+)
+
+// RegisterAgentMetrics registers the synchronization, messaging and CR application metrics with the controller
+// runtime metrics registry.
+func RegisterAgentMetrics() {
+	k8smetrics.Registry.MustRegister(eventsConsumed, controlPlaneRequestDuration, controlPlaneRetries,
+		workerPoolQueuedJobs, workerPoolCapacity, crApplies, revisionAcks, revisionAckDeadLetters)
+}
+
+// RecordEventConsumed records an event of the given type consumed from the broker.
+func RecordEventConsumed(eventType string) {
+	eventsConsumed.WithLabelValues(eventType).Inc()
+}
+
+// ObserveControlPlaneRequest records the time taken by a control plane request for the given resource started at
+// the given time.
+func ObserveControlPlaneRequest(resource string, start time.Time, succeeded bool) {
+	controlPlaneRequestDuration.WithLabelValues(resource, result(succeeded)).Observe(time.Since(start).Seconds())
+}
+
+// RecordControlPlaneRetry records a retry of a control plane request for the given resource.
+func RecordControlPlaneRetry(resource string) {
+	controlPlaneRetries.WithLabelValues(resource).Inc()
+}
+
+// SetWorkerPoolQueue records the number of queued jobs and the capacity of the worker pool queue.
+func SetWorkerPoolQueue(queued int, capacity int) {
+	workerPoolQueuedJobs.Set(float64(queued))
+	workerPoolCapacity.Set(float64(capacity))
+}
+
+// RecordCRApply records the result of creating, updating or deleting a CR of the given kind.
+func RecordCRApply(kind string, err error) {
+	crApplies.WithLabelValues(kind, result(err == nil)).Inc()
+}
+
+// RecordRevisionAck records the result of sending a revision acknowledgement of the given type.
+func RecordRevisionAck(ackType string, succeeded bool) {
+	revisionAcks.WithLabelValues(ackType, result(succeeded)).Inc()
+}
+
+// RecordRevisionAckDeadLetter records a revision acknowledgement of the given type which is given up without being
+// delivered.
+func RecordRevisionAckDeadLetter(ackType string) {
+	revisionAckDeadLetters.WithLabelValues(ackType).Inc()
+}
+
+func result(succeeded bool) string {
+	if succeeded {
+		return resultSuccess
+	}
+	return resultFailure
+}
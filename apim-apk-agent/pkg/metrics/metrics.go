@@ -29,4 +29,6 @@ func RegisterPrometheusCollector() {
 
 	collector := metrics.CustomMetricsCollector()
 	k8smetrics.Registry.MustRegister(collector)
+	RegisterReconcileMetrics()
+	RegisterAgentMetrics()
 }
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024, WSO2 LLC. (https://www.wso2.com)
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+func TestAgentMetricsExposedOnMetricsBindAddress(t *testing.T) {
+	RegisterPrometheusCollector()
+	RecordEventConsumed("notification")
+	ObserveControlPlaneRequest("apis", time.Now().Add(-time.Second), true)
+	RecordControlPlaneRetry("keymanagers")
+	SetWorkerPoolQueue(3, 100)
+	RecordCRApply("HTTPRoute", errors.New("conflict"))
+	RecordRevisionAck("deployed", true)
+	RecordRevisionAckDeadLetter("failed")
+	RecordDrift("API", DriftMissing, 1)
+	RecordReconcileRun(true)
+
+	// The manager serves the controller runtime metrics registry on the configured metrics port in the same way
+	bindAddress := getFreeAddress(t)
+	server, err := metricsserver.NewServer(metricsserver.Options{BindAddress: bindAddress}, nil, nil)
+	if err != nil {
+		t.Fatalf("Error while creating the metrics server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := server.Start(ctx); err != nil {
+			t.Errorf("Error while starting the metrics server: %v", err)
+		}
+	}()
+
+	body := scrapeMetrics(t, fmt.Sprintf("http://%s/metrics", bindAddress))
+	for _, expected := range []string{
+		`apim_apk_agent_events_consumed_total{type="notification"} 1`,
+		`apim_apk_agent_control_plane_request_duration_seconds_count{resource="apis",result="success"} 1`,
+		`apim_apk_agent_control_plane_retries_total{resource="keymanagers"} 1`,
+		`apim_apk_agent_worker_pool_queued_jobs 3`,
+		`apim_apk_agent_worker_pool_capacity 100`,
+		`apim_apk_agent_cr_applies_total{kind="HTTPRoute",result="failure"} 1`,
+		`apim_apk_agent_revision_acks_total{result="success",type="deployed"} 1`,
+		`apim_apk_agent_revision_ack_dead_letters_total{type="failed"} 1`,
+		`apim_apk_agent_reconcile_drifted_resources{drift_type="missing",kind="API"} 1`,
+		`apim_apk_agent_reconcile_runs_total{result="success"} 1`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the metrics to contain %q", expected)
+		}
+	}
+}
+
+func getFreeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error while finding a free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// scrapeMetrics reads the metrics from the given URL, waiting until the metrics server starts listening
+func scrapeMetrics(t *testing.T, url string) string {
+	var lastErr error
+	for attempt := 0; attempt < 50; attempt++ {
+		resp, err := http.Get(url)
+		if err == nil {
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err == nil && resp.StatusCode == http.StatusOK {
+				return string(body)
+			}
+			lastErr = fmt.Errorf("status code %d, error %v", resp.StatusCode, err)
+		} else {
+			lastErr = err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("Unable to scrape the metrics from %s: %v", url, lastErr)
+	return ""
+}
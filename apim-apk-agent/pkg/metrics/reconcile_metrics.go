@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2024, WSO2 LLC. (https://www.wso2.com)
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DriftMissing is the drift type of the resources which are available in the control plane but not in the cluster
+	DriftMissing = "missing"
+	// DriftModified is the drift type of the resources which are different in the control plane and the cluster
+	DriftModified = "modified"
+	// DriftOrphaned is the drift type of the resources which are available in the cluster but not in the control plane
+	DriftOrphaned = "orphaned"
+)
+
+var (
+	driftedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "apim_apk_agent_reconcile_drifted_resources",
+		Help: "Number of resources that had drifted from the control plane in the last reconciliation.",
+	}, []string{"kind", "drift_type"})
+	repairedResources = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_apk_agent_reconcile_repaired_resources_total",
+		Help: "Total number of resources repaired by the reconciliation.",
+	}, []string{"kind", "drift_type"})
+	reconcileRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "apim_apk_agent_reconcile_runs_total",
+		Help: "Total number of reconciliation runs.",
+	}, []string{"result"})
+	lastReconcileTime = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "apim_apk_agent_reconcile_last_run_timestamp_seconds",
+		Help: "Unix time of the last completed reconciliation.",
+	})
+)
+
+// RegisterReconcileMetrics registers the reconciliation metrics with the controller runtime metrics registry.
+func RegisterReconcileMetrics() {
+	k8smetrics.Registry.MustRegister(driftedResources, repairedResources, reconcileRuns, lastReconcileTime)
+}
+
+// RecordDrift records the number of resources of the given kind and drift type found in a reconciliation.
+func RecordDrift(kind string, driftType string, count int) {
+	driftedResources.WithLabelValues(kind, driftType).Set(float64(count))
+	repairedResources.WithLabelValues(kind, driftType).Add(float64(count))
+}
+
+// RecordReconcileRun records the completion of a reconciliation run.
+func RecordReconcileRun(succeeded bool) {
+	reconcileRuns.WithLabelValues(result(succeeded)).Inc()
+	lastReconcileTime.Set(float64(time.Now().Unix()))
+}
@@ -34,8 +34,9 @@ import (
 	"time"
 
 	parser "github.com/mitchellh/mapstructure"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/auth"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
 )
 
 const (
@@ -52,6 +53,9 @@ const (
 	// APIArtifactEndpoint represents the /retrieve-api-artifacts endpoint.
 	APIArtifactEndpoint string = "internal/data/v1/retrieve-api-artifacts"
 	// httpTimeout is for connection timeout of httpClient in seconds
+
+	// apisResource is the resource name of the API fetch requests in the metrics
+	apisResource = "apis"
 )
 
 // FetchAPIs submits the control plane http request to the thread pool. The thread pool would process it and return
@@ -63,7 +67,7 @@ func FetchAPIs(id *string, gwLabel []string, c chan SyncAPIResponse, resourceEnd
 		logger.LoggerSync.Info("Fetching APIs from Control Plane")
 	}
 
-	req := ConstructControlPlaneRequest(id, gwLabel, workerPool.controlPlaneParams, resourceEndpoint, sendType)
+	req := ConstructControlPlaneRequest(id, gwLabel, workerPool.getControlPlaneParams(), resourceEndpoint, sendType)
 	workerReq := workerRequest{
 		Req:                *req,
 		APIUUID:            id,
@@ -90,7 +94,8 @@ func SendRequestToControlPlane(req *http.Request, apiID *string, gwLabels []stri
 	} else {
 		logger.LoggerSync.Debugf("Sending the control plane request, url: %s", req.URL.String())
 	}
-	resp, err := client.Do(req)
+	start := time.Now()
+	resp, err := controlplane.GetClient().DoWithHTTPClient(apisResource, req, client)
 
 	respSyncAPI := SyncAPIResponse{}
 
@@ -107,6 +112,7 @@ func SendRequestToControlPlane(req *http.Request, apiID *string, gwLabels []stri
 		logger.LoggerSync.Errorf("Error occurred while retrieving APIs from API manager: %v", err)
 		respSyncAPI.Err = err
 		respSyncAPI.Resp = nil
+		metrics.ObserveControlPlaneRequest(apisResource, start, false)
 		c <- respSyncAPI
 		return false
 	}
@@ -120,6 +126,7 @@ func SendRequestToControlPlane(req *http.Request, apiID *string, gwLabels []stri
 		respSyncAPI.Err = err
 		respSyncAPI.ErrorCode = resp.StatusCode
 		respSyncAPI.Resp = nil
+		metrics.ObserveControlPlaneRequest(apisResource, start, false)
 		c <- respSyncAPI
 		return false
 	}
@@ -130,6 +137,7 @@ func SendRequestToControlPlane(req *http.Request, apiID *string, gwLabels []stri
 			respSyncAPI.Err = nil
 			respSyncAPI.Resp = respBytes
 			respSyncAPI.Found = true
+			metrics.ObserveControlPlaneRequest(apisResource, start, true)
 			c <- respSyncAPI
 			return true
 		} else if contentType == "application/json" {
@@ -141,12 +149,14 @@ func SendRequestToControlPlane(req *http.Request, apiID *string, gwLabels []stri
 				respSyncAPI.Resp = nil
 				respSyncAPI.Found = false
 				respSyncAPI.ErrorCode = resp.StatusCode
+				metrics.ObserveControlPlaneRequest(apisResource, start, false)
 				c <- respSyncAPI
 				return true
 			}
 			respSyncAPI.Err = nil
 			respSyncAPI.Resp = respBytes
 			respSyncAPI.Found = runtimeArtifactResponse.Count != 0
+			metrics.ObserveControlPlaneRequest(apisResource, start, true)
 			c <- respSyncAPI
 			return true
 		}
@@ -157,6 +167,7 @@ func SendRequestToControlPlane(req *http.Request, apiID *string, gwLabels []stri
 	respSyncAPI.Err = errors.New(string(respBytes))
 	respSyncAPI.Resp = nil
 	respSyncAPI.ErrorCode = resp.StatusCode
+	metrics.ObserveControlPlaneRequest(apisResource, start, false)
 	c <- respSyncAPI
 	return true
 }
@@ -170,8 +181,6 @@ func ConstructControlPlaneRequest(id *string, gwLabel []string, controlPlanePara
 	)
 
 	serviceURL := controlPlaneParams.serviceURL
-	userName := controlPlaneParams.username
-	password := controlPlaneParams.password
 
 	// NOTE: Getting resourceEndpoint as a parameter since GA and LA use different endpoints.
 	if strings.HasSuffix(serviceURL, "/") {
@@ -206,25 +215,16 @@ func ConstructControlPlaneRequest(id *string, gwLabel []string, controlPlanePara
 	}
 	req.URL.RawQuery = q.Encode()
 
-	// Setting authorization header
-	basicAuth := "Basic " + auth.GetBasicAuth(userName, password)
-	req.Header.Set(Authorization, basicAuth)
 	req.Header.Set("x-wso2-tenant", "ALL")
 	return req
 }
 
 // RetryFetchingAPIs function keeps retrying to fetch APIs from runtime-artifact endpoint.
 func RetryFetchingAPIs(c chan SyncAPIResponse, data SyncAPIResponse, endpoint string, sendType bool) {
-	retryInterval := workerPool.controlPlaneParams.retryInterval
-
-	// Retry fetching from control plane after a configured time interval
-	if retryInterval == 0 {
-		// Assign default retry interval
-		retryInterval = 5
-	}
-	logger.LoggerSync.Debugf("Time Duration for retrying: %v", retryInterval*time.Second)
-	time.Sleep(retryInterval * time.Second)
+	// Retry fetching from control plane backing off with the consecutive failures
+	controlplane.GetClient().WaitBeforeRetry(apisResource)
 	logger.LoggerSync.Infof("Retrying to fetch API data from control plane for the API %q.", data.APIUUID)
+	metrics.RecordControlPlaneRetry(apisResource)
 	channelFillPercentage := float64(len(workerPool.internalQueue)) / float64(cap(workerPool.internalQueue)) * 100
 	logger.LoggerSync.Infof("Workerpool channel size as a percentage is : %f", channelFillPercentage)
 	FetchAPIs(&data.APIUUID, data.GatewayLabels, c, endpoint, sendType)
@@ -23,15 +23,20 @@ import (
 	"sync"
 	"time"
 
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/metrics"
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
 )
 
 type worker struct {
-	id              int
-	internalQueue   <-chan workerRequest
-	processFunc     processHTTPRequest
-	delayAfterFault time.Duration
+	id            int
+	pool          *pool
+	internalQueue <-chan workerRequest
+	processFunc   processHTTPRequest
+	faults        int
+	// stop is closed to stop the worker when the pool shrinks
+	stop chan struct{}
 }
 
 // workerRequest is the task which can be submitted to the pool.
@@ -44,28 +49,51 @@ type workerRequest struct {
 
 // pool is the worker pool which is handling
 type pool struct {
-	internalQueue      chan workerRequest
+	internalQueue chan workerRequest
+	// lock guards the workers and the configurations below which can be changed while the pool runs
+	lock               sync.RWMutex
 	workers            []*worker
-	client             http.Client
+	client             *http.Client
 	controlPlaneParams controlPlaneParameters
+	// backoffAfterFault is the pause of a worker after a fault which grows with the consecutive faults
+	backoffAfterFault controlplane.Backoff
 }
 
 type controlPlaneParameters struct {
 	serviceURL    string
-	username      string
-	password      string
 	retryInterval time.Duration
 }
 
 type processHTTPRequest func(*http.Request, *string, []string, chan SyncAPIResponse, *http.Client) bool
 
+// ProcessFunction processes the requests in the queue of the pool until the worker is stopped
 func (w *worker) ProcessFunction() {
-	for workerReq := range w.internalQueue {
+	for !w.isStopped() {
+		var workerReq workerRequest
+		select {
+		case <-w.stop:
+			continue
+		case workerReq = <-w.internalQueue:
+		}
+		metrics.SetWorkerPoolQueue(len(w.internalQueue), cap(w.internalQueue))
 		responseReceived := w.processFunc(&workerReq.Req, workerReq.APIUUID, workerReq.labels, workerReq.SyncAPIRespChannel,
-			&workerPool.client)
-		if !responseReceived {
-			time.Sleep(w.delayAfterFault)
+			w.pool.getClient())
+		if responseReceived {
+			w.faults = 0
+			continue
 		}
+		w.faults++
+		time.Sleep(w.pool.getBackoffAfterFault().Delay(w.faults))
+	}
+	loggers.LoggerSync.Infof("ControlPlane processing worker %d stopped.", w.id)
+}
+
+func (w *worker) isStopped() bool {
+	select {
+	case <-w.stop:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -78,66 +106,129 @@ var (
 // InitializeWorkerPool creates the Worker Pool used for the Control Plane Rest API invocations.
 // maxWorkers indicate the maximum number of parallel workers sending requests to the control plane.
 // jobQueueCapacity indicate the maximum number of requests can kept inside a single worker's queue.
-// delayForFaultRequests indicate the delay a worker enforce (in seconds) when a fault response is received.
+// delayForFaultRequests indicate the delay a worker enforce (in seconds) when a fault response is received. The delay
+// doubles with the consecutive faults up to the maximum retry interval of the control plane.
 func InitializeWorkerPool(maxWorkers, jobQueueCapacity int, delayForFaultRequests time.Duration, trustStoreLocation string,
-	skipSSL bool, requestTimeout, retryInterval time.Duration, serviceURL, username, password string) {
+	skipSSL bool, requestTimeout, retryInterval time.Duration, serviceURL string) {
 	oncePoolInitiated.Do(func() {
-		workerPool = newWorkerPool(maxWorkers, jobQueueCapacity, delayForFaultRequests)
+		workerPool = newWorkerPool(maxWorkers, jobQueueCapacity, delayForFaultRequests*time.Second)
 		workerPool.controlPlaneParams = controlPlaneParameters{
 			serviceURL:    serviceURL,
-			username:      username,
-			password:      password,
 			retryInterval: retryInterval,
 		}
-		var tr *http.Transport
-		if !skipSSL {
-			caCertPool := tlsutils.GetTrustedCertPool(trustStoreLocation)
-			tr = &http.Transport{
-				TLSClientConfig: &tls.Config{RootCAs: caCertPool},
-			}
-		} else {
-			tr = &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			}
+		workerPool.client = newHTTPClient(maxWorkers, trustStoreLocation, skipSSL, requestTimeout)
+	})
+}
+
+// UpdateWorkerPool applies the changed configurations to the worker pool. The workers are added or stopped to match
+// maxWorkers. A stopped worker completes the request it is processing. The arguments are the same as of
+// InitializeWorkerPool except for the capacity of the queue, which cannot be changed.
+func UpdateWorkerPool(maxWorkers int, delayForFaultRequests time.Duration, trustStoreLocation string, skipSSL bool,
+	requestTimeout, retryInterval time.Duration, serviceURL string) {
+	if workerPool == nil {
+		return
+	}
+	workerPool.resize(maxWorkers)
+	backoffAfterFault := controlplane.GetClient().GetBackoff()
+	backoffAfterFault.Initial = delayForFaultRequests * time.Second
+	httpClient := newHTTPClient(maxWorkers, trustStoreLocation, skipSSL, requestTimeout)
+
+	workerPool.lock.Lock()
+	previousClient := workerPool.client
+	workerPool.client = httpClient
+	workerPool.backoffAfterFault = backoffAfterFault
+	workerPool.controlPlaneParams = controlPlaneParameters{
+		serviceURL:    serviceURL,
+		retryInterval: retryInterval,
+	}
+	workerPool.lock.Unlock()
+	if previousClient != nil {
+		previousClient.CloseIdleConnections()
+	}
+}
+
+// newHTTPClient creates the HTTP client the workers send the requests with
+func newHTTPClient(maxWorkers int, trustStoreLocation string, skipSSL bool, requestTimeout time.Duration) *http.Client {
+	var tr *http.Transport
+	if !skipSSL {
+		caCertPool := tlsutils.GetTrustedCertPool(trustStoreLocation)
+		tr = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: caCertPool},
 		}
-		// Configure Connection Level Parameters since it is reused over and over
-		tr.MaxConnsPerHost = maxWorkers * 2
-		tr.MaxIdleConns = maxWorkers * 2
-		tr.MaxIdleConnsPerHost = maxWorkers * 2
-		workerPool.client = http.Client{
-			Transport: tr,
-			Timeout:   requestTimeout * time.Second,
+	} else {
+		tr = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		}
-	})
+	}
+	// Configure Connection Level Parameters since it is reused over and over
+	tr.MaxConnsPerHost = maxWorkers * 2
+	tr.MaxIdleConns = maxWorkers * 2
+	tr.MaxIdleConnsPerHost = maxWorkers * 2
+	return &http.Client{
+		Transport: tr,
+		Timeout:   requestTimeout * time.Second,
+	}
 }
 
 func newWorkerPool(maxWorkers, jobQueueCapacity int, delayForFaultRequests time.Duration) *pool {
 	if jobQueueCapacity <= 0 {
 		jobQueueCapacity = 100
 	}
-	requestChannel := make(chan workerRequest, jobQueueCapacity)
-	workers := make([]*worker, maxWorkers)
-
-	// create workers
-	for i := 0; i < maxWorkers; i++ {
-		workers[i] = &worker{
-			id:              i,
-			internalQueue:   requestChannel,
-			processFunc:     SendRequestToControlPlane,
-			delayAfterFault: delayForFaultRequests,
-		}
-		go workers[i].ProcessFunction()
-		loggers.LoggerSync.Infof("ControlPlane processing worker %d spawned.", i)
+	backoffAfterFault := controlplane.GetClient().GetBackoff()
+	backoffAfterFault.Initial = delayForFaultRequests
+	p := &pool{
+		internalQueue:     make(chan workerRequest, jobQueueCapacity),
+		backoffAfterFault: backoffAfterFault,
 	}
+	p.resize(maxWorkers)
+	return p
+}
 
-	return &pool{
-		internalQueue: requestChannel,
-		workers:       workers,
+// resize spawns or stops the workers to match the given number of workers
+func (q *pool) resize(maxWorkers int) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for len(q.workers) < maxWorkers {
+		w := &worker{
+			id:            len(q.workers),
+			pool:          q,
+			internalQueue: q.internalQueue,
+			processFunc:   SendRequestToControlPlane,
+			stop:          make(chan struct{}),
+		}
+		q.workers = append(q.workers, w)
+		go w.ProcessFunction()
+		loggers.LoggerSync.Infof("ControlPlane processing worker %d spawned.", w.id)
 	}
+	for len(q.workers) > maxWorkers {
+		close(q.workers[len(q.workers)-1].stop)
+		q.workers = q.workers[:len(q.workers)-1]
+	}
+}
+
+func (q *pool) getClient() *http.Client {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	return q.client
+}
+
+func (q *pool) getBackoffAfterFault() controlplane.Backoff {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	return q.backoffAfterFault
+}
+
+func (q *pool) getControlPlaneParams() controlPlaneParameters {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	return q.controlPlaneParams
 }
 
 // Enqueue Tries to enqueue but fails if queue is full
 func (q *pool) Enqueue(req workerRequest) bool {
+	defer func() {
+		metrics.SetWorkerPoolQueue(len(q.internalQueue), cap(q.internalQueue))
+	}()
 	select {
 	case q.internalQueue <- req:
 		return true
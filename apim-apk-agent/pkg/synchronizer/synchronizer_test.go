@@ -34,14 +34,10 @@ func TestConstructControlPlaneRequest(t *testing.T) {
 		dummyID                 = "6ge3e2777gwsgwsjt2gj7wi7h72yw72yw27"
 		dummyGWLabel            = []string{"test1.gw.wso2.com", "test2.gw.wso2.com"}
 		dummyServiceURL         = "http://example.com"
-		dummyUsername           = "testusername"
-		dummyPassword           = "testpassword"
 		dummyResourceEndpoint   = "test-endpoint"
 		dummySendType           = true
 		dummyControlPlaneParams = controlPlaneParameters{
 			serviceURL:    dummyServiceURL,
-			username:      dummyUsername,
-			password:      dummyPassword,
 			retryInterval: time.Second * 5,
 		}
 	)
@@ -118,3 +114,16 @@ func TestNewWorkerPool(t *testing.T) {
 		t.Errorf("Unexpected number of workers. Got: %d, Expected: %d", len(workerPool.workers), maxWorkers)
 	}
 }
+
+func TestResizeWorkerPool(t *testing.T) {
+	workerPool := newWorkerPool(2, 10, time.Second)
+	workerPool.resize(4)
+	assert.Len(t, workerPool.workers, 4)
+	stopped := workerPool.workers[1:]
+	workerPool.resize(1)
+	assert.Len(t, workerPool.workers, 1)
+	for _, w := range stopped {
+		assert.True(t, w.isStopped())
+	}
+	assert.False(t, workerPool.workers[0].isStopped())
+}
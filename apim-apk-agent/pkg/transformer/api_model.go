@@ -17,6 +17,11 @@
 
 package transformer
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // CustomParams holds the custom parameter values that has been enabled for the selected security mode
 type CustomParams struct {
 	CustomParamMapping map[string]string `json:"customParamMapping"`
@@ -43,16 +48,63 @@ type EndpointSecurityConfig struct {
 	Sandbox    SecurityObj `json:"sandbox"`
 }
 
-// EndpointDetails represents the details of an endpoint, containing its URL.
+// EndpointDetails represents the details of an endpoint, containing its URL and the advanced endpoint configurations
+// such as the timeout and the suspension settings. The endpoints of a load balanced endpoint configuration are given
+// as a list, in which case they are held in Endpoints and the first one is also held in URL and Config.
 type EndpointDetails struct {
-	URL string `json:"url"`
+	URL       string                 `json:"url"`
+	Config    map[string]interface{} `json:"config"`
+	Endpoints []EndpointDetails      `json:"-"`
+}
+
+// endpointDetails is used to decode a single endpoint without the custom unmarshaller of EndpointDetails
+type endpointDetails EndpointDetails
+
+// UnmarshalJSON decodes either a single endpoint or the list of the endpoints of a load balanced endpoint
+// configuration
+func (endpoint *EndpointDetails) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '[' {
+		return json.Unmarshal(data, (*endpointDetails)(endpoint))
+	}
+	var endpoints []EndpointDetails
+	if err := json.Unmarshal(data, &endpoints); err != nil {
+		return err
+	}
+	*endpoint = EndpointDetails{Endpoints: endpoints}
+	if len(endpoints) > 0 {
+		endpoint.URL = endpoints[0].URL
+		endpoint.Config = endpoints[0].Config
+	}
+	return nil
+}
+
+// MarshalJSON encodes the endpoints of a load balanced endpoint configuration as a list and a single endpoint as an
+// object
+func (endpoint EndpointDetails) MarshalJSON() ([]byte, error) {
+	if len(endpoint.Endpoints) > 0 {
+		return json.Marshal(endpoint.Endpoints)
+	}
+	return json.Marshal(endpointDetails(endpoint))
+}
+
+// getEndpoints returns the endpoints, which are more than one for a load balanced endpoint configuration
+func (endpoint EndpointDetails) getEndpoints() []EndpointDetails {
+	if len(endpoint.Endpoints) == 0 {
+		return []EndpointDetails{endpoint}
+	}
+	return endpoint.Endpoints
 }
 
 // EndpointConfig represents the configuration of an endpoint, including its type, sandbox, and production details.
+// The failover endpoints of a failover endpoint configuration are used when the production or the sandbox endpoint
+// is unavailable.
 type EndpointConfig struct {
 	EndpointType        string                 `json:"endpoint_type"`
 	SandboxEndpoints    EndpointDetails        `json:"sandbox_endpoints"`
 	ProductionEndpoints EndpointDetails        `json:"production_endpoints"`
+	SandboxFailovers    []EndpointDetails      `json:"sandbox_failovers"`
+	ProductionFailovers []EndpointDetails      `json:"production_failovers"`
+	SessionManagement   string                 `json:"sessionManagement"`
 	EndpointSecurity    EndpointSecurityConfig `json:"endpoint_security"`
 }
 
@@ -180,6 +232,10 @@ type APIMApi struct {
 	APIPolicies          APIMOperationPolicies `yaml:"apiPolicies"`
 	SubtypeConfiguration SubtypeConfiguration  `yaml:"subtypeConfiguration"`
 	MaxTps               *MaxTps               `yaml:"maxTps"`
+	// Gateway features of the API Manager which are reported when they cannot be applied in APK
+	EnableSchemaValidation bool `yaml:"enableSchemaValidation"`
+	ResponseCachingEnabled bool `yaml:"responseCachingEnabled"`
+	CacheTimeout           int  `yaml:"cacheTimeout"`
 }
 
 // SubtypeConfiguration holds the details for Subtypes
@@ -45,6 +45,22 @@ type EndpointConfiguration struct {
 	EndCertificate EndpointCertificate `yaml:"certificate,omitempty"`
 	EndSecurity    EndpointSecurity    `yaml:"endpointSecurity,omitempty"`
 	AIRatelimit    AIRatelimit         `yaml:"aiRatelimit,omitempty"`
+	Resiliency     *Resiliency         `yaml:"resiliency,omitempty"`
+	Weight         int                 `yaml:"weight,omitempty"`
+	// Failovers are the endpoints the requests are retried on when the endpoint fails. The apk-conf cannot express
+	// them, hence they are only applied by the local CR generator.
+	Failovers []*EndpointConfiguration `yaml:"-"`
+}
+
+// Resiliency holds the resiliency configurations of an endpoint
+type Resiliency struct {
+	Timeout *EndpointTimeout `yaml:"timeout,omitempty"`
+}
+
+// EndpointTimeout holds the timeouts of the requests to an endpoint in seconds
+type EndpointTimeout struct {
+	UpstreamResponseTimeout      int `yaml:"upstreamResponseTimeout,omitempty"`
+	DownstreamRequestIdleTimeout int `yaml:"downstreamRequestIdleTimeout,omitempty"`
 }
 
 // AIRatelimit defines the configuration for AI rate limiting,
@@ -104,8 +120,20 @@ type AuthConfiguration struct {
 
 // EndpointConfigurations holds production and sandbox endpoints.
 type EndpointConfigurations struct {
-	Production *EndpointConfiguration `yaml:"production,omitempty"`
-	Sandbox    *EndpointConfiguration `yaml:"sandbox,omitempty"`
+	Production EndpointConfigurationList `yaml:"production,omitempty"`
+	Sandbox    EndpointConfigurationList `yaml:"sandbox,omitempty"`
+}
+
+// EndpointConfigurationList holds the endpoints of an environment, which share the requests by their weights when
+// there are more than one.
+type EndpointConfigurationList []*EndpointConfiguration
+
+// MarshalYAML writes a single endpoint as an object and load balanced endpoints as a list of weighted endpoints
+func (endpoints EndpointConfigurationList) MarshalYAML() (interface{}, error) {
+	if len(endpoints) == 1 {
+		return endpoints[0], nil
+	}
+	return []*EndpointConfiguration(endpoints), nil
 }
 
 // OperationPolicies organizes request and response policies for an API operation.
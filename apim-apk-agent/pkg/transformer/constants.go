@@ -48,6 +48,9 @@ const (
 	k8sKindTokenIssuer = "TokenIssuer"
 	apkCRDAPIVersion   = "dp.wso2.com/v1alpha1"
 
+	// Maximum number of rules added to a single route by the local CR generator
+	maxRulesPerRoute = 8
+
 	// Auth Types
 	mTLS   = "mTLS"
 	jwt    = "JWT"
@@ -102,6 +105,40 @@ const (
 	headerName  = "headerName"
 	headerValue = "headerValue"
 
+	// API Manager endpoint types with multiple endpoints
+	loadBalanceEndpointType = "load_balance"
+	failoverEndpointType    = "failover"
+
+	// Weight of each of the load balanced endpoints as they share the requests equally in the API Manager
+	loadBalancedEndpointWeight = 1
+
+	// Base interval in milliseconds of the retries of the requests on the failover endpoints
+	failoverRetryBaseIntervalMillis = 25
+
+	// Health checks of the failover endpoints. An endpoint is taken out after failing the health checks twice in a
+	// row, and put back after passing them twice in a row.
+	failoverHealthCheckIntervalSeconds = 10
+	failoverHealthCheckTimeoutSeconds  = 1
+	failoverHealthCheckThreshold       = 2
+
+	// Session management of the load balanced endpoints which does not require sticky sessions
+	noSessionManagement = "none"
+
+	// Advanced endpoint configuration keys of the API Manager endpoints
+	endpointTimeoutDuration          = "actionDuration"
+	endpointTimeoutAction            = "actionSelect"
+	endpointRetriesBeforeSuspension  = "retryTimeOut"
+	endpointRetryDelay               = "retryDelay"
+	endpointRetryErrorCodes          = "retryErroCode"
+	endpointSuspendErrorCodes        = "suspendErrorCode"
+	endpointSuspendDuration          = "suspendDuration"
+	endpointSuspendMaxDuration       = "suspendMaxDuration"
+	endpointSuspendProgressionFactor = "factor"
+	endpointTimeoutActionDiscard     = "discard"
+
+	// Idle timeout of the requests in seconds applied by APK when it is not configured
+	defaultDownstreamRequestIdleTimeout = 300
+
 	// Version constants
 	v1 = "v1"
 	v2 = "v2"
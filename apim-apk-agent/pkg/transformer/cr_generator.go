@@ -0,0 +1,922 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net"
+	neturl "net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dpv1alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha1"
+	dpv1alpha2 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha2"
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
+	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// ErrUnsupportedByLocalGenerator is returned when the apk-conf contains configurations which can only be
+// handled by the remote config generator service.
+var ErrUnsupportedByLocalGenerator = errors.New("apk-conf contains configurations not supported by the local CR generator")
+
+var pathParamRegex = regexp.MustCompile(`\{[^/{}]+\}`)
+
+// Status codes of the requests retried on the failover endpoints
+var failoverStatusCodes = []uint32{502, 503, 504}
+
+// GenerateCRsLocally generates the CR set for a particular API from the apk-conf model without calling the
+// remote config generator service. The generated CRs follow the same naming conventions as the ones returned
+// by the config generator service so that both can be used interchangeably. The routes use the vhosts of the
+// environments the API is deployed in, hence the routes of an endpoint type without an environment are not generated.
+func GenerateCRsLocally(api *API, apiDefinition string, certContainer CertContainer, organizationID string,
+	environments []Environment) (*K8sArtifacts, error) {
+	if api == nil {
+		logger.LoggerTransformer.Error("Empty apk-conf provided. Unable to generate CRDs.")
+		return nil, errors.New("Error: APK-Conf can't be empty")
+	}
+	if apiDefinition == "" {
+		logger.LoggerTransformer.Error("Empty api definition provided. Unable to generate CRDs.")
+		return nil, errors.New("Error: API Definition can't be empty")
+	}
+	if err := validateLocalCRGenerationSupport(api); err != nil {
+		return nil, err
+	}
+
+	k8sArtifact := newK8sArtifacts()
+	apiUniqueID := GetUniqueIDForAPI(api.Name, api.Version, organizationID)
+	labels := map[string]string{
+		"api-name":           generateSHA1Hash(api.Name),
+		"api-version":        generateSHA1Hash(api.Version),
+		k8sOrganizationField: generateSHA1Hash(organizationID),
+		"managed-by":         "apk",
+	}
+	objectMeta := func(name string) metav1.ObjectMeta {
+		objectLabels := make(map[string]string, len(labels))
+		for key, value := range labels {
+			objectLabels[key] = value
+		}
+		return metav1.ObjectMeta{Name: name, Labels: objectLabels}
+	}
+
+	definitionConfigMap, err := createDefinitionConfigMap(objectMeta(apiUniqueID+"-definition"), apiDefinition)
+	if err != nil {
+		logger.LoggerTransformer.Errorf("Error while compressing the API definition: %v", err)
+		return nil, err
+	}
+	k8sArtifact.ConfigMaps[definitionConfigMap.Name] = definitionConfigMap
+
+	definitionPath := api.DefinitionPath
+	if definitionPath == "" {
+		definitionPath = "/definition"
+	}
+	k8sArtifact.API = dpv1alpha3.API{
+		TypeMeta:   metav1.TypeMeta{Kind: k8sKindAPI, APIVersion: dpv1alpha3.GroupVersion.String()},
+		ObjectMeta: objectMeta(apiUniqueID),
+		Spec: dpv1alpha3.APISpec{
+			APIName:           api.Name,
+			APIVersion:        api.Version,
+			IsDefaultVersion:  api.DefaultVersion,
+			DefinitionFileRef: definitionConfigMap.Name,
+			DefinitionPath:    definitionPath,
+			APIType:           getCRAPIType(api.Type),
+			BasePath:          getFullBasePath(api.Context, api.Version),
+			Organization:      organizationID,
+			Production:        []dpv1alpha3.EnvConfig{},
+			Sandbox:           []dpv1alpha3.EnvConfig{},
+		},
+	}
+	if api.AdditionalProperties != nil {
+		for _, property := range *api.AdditionalProperties {
+			k8sArtifact.API.Spec.APIProperties = append(k8sArtifact.API.Spec.APIProperties,
+				dpv1alpha3.Property{Name: property.Name, Value: property.Value})
+		}
+	}
+
+	// API level policies which are shared between the environments
+	apiPolicySpec := &dpv1alpha3.PolicySpec{SubscriptionValidation: api.SubscriptionValidation}
+	if api.CorsConfig != nil && api.CorsConfig.CORSConfigurationEnabled {
+		apiPolicySpec.CORSPolicy = &dpv1alpha3.CORSPolicy{
+			Enabled:                       true,
+			AccessControlAllowCredentials: api.CorsConfig.AccessControlAllowCredentials,
+			AccessControlAllowHeaders:     api.CorsConfig.AccessControlAllowHeaders,
+			AccessControlAllowMethods:     api.CorsConfig.AccessControlAllowMethods,
+			AccessControlAllowOrigins:     api.CorsConfig.AccessControlAllowOrigins,
+		}
+	}
+	if api.AIProvider != nil && api.AIProvider.Name != "" {
+		apiPolicySpec.AIProvider = &dpv1alpha3.AIProviderReference{Name: api.AIProvider.Name}
+	}
+	apiPolicy := &dpv1alpha3.APIPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: "APIPolicy", APIVersion: dpv1alpha3.GroupVersion.String()},
+		ObjectMeta: objectMeta(apiUniqueID + "-api-policy"),
+		Spec: dpv1alpha3.APIPolicySpec{
+			Default:   apiPolicySpec,
+			TargetRef: policyTargetRef(k8sKindAPI, apiUniqueID),
+		},
+	}
+	k8sArtifact.APIPolicies[apiPolicy.Name] = apiPolicy
+	resourcePolicy := &dpv1alpha3.APIPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: "APIPolicy", APIVersion: dpv1alpha3.GroupVersion.String()},
+		ObjectMeta: objectMeta(apiUniqueID + "-resource-policy"),
+		Spec: dpv1alpha3.APIPolicySpec{
+			Default:   apiPolicySpec.DeepCopy(),
+			TargetRef: policyTargetRef("Resource", apiUniqueID),
+		},
+	}
+	k8sArtifact.APIPolicies[resourcePolicy.Name] = resourcePolicy
+
+	if api.RateLimit != nil && api.RateLimit.RequestsPerUnit > 0 {
+		rateLimitPolicy := createRateLimitPolicy(objectMeta("api-"+apiUniqueID), api.RateLimit,
+			policyTargetRef(k8sKindAPI, apiUniqueID))
+		k8sArtifact.RateLimitPolicies[rateLimitPolicy.Name] = rateLimitPolicy
+	}
+
+	// Resource level CRs which are referenced from the route rules of both the environments
+	var operations []Operation
+	if api.Operations != nil {
+		operations = *api.Operations
+	}
+	operationFilters := make([][]gwapiv1.LocalObjectReference, len(operations))
+	scopeRefs := make(map[string]string)
+	for i, operation := range operations {
+		operationFilters[i] = append(operationFilters[i], extensionRef("APIPolicy", resourcePolicy.Name))
+		for _, scopeName := range operation.Scopes {
+			scopeCRName, exists := scopeRefs[scopeName]
+			if !exists {
+				scopeCRName = GetSha1Value(apiUniqueID + "-" + scopeName)
+				scopeRefs[scopeName] = scopeCRName
+				k8sArtifact.Scopes[scopeCRName] = &dpv1alpha1.Scope{
+					TypeMeta:   metav1.TypeMeta{Kind: "Scope", APIVersion: dpv1alpha1.GroupVersion.String()},
+					ObjectMeta: objectMeta(scopeCRName),
+					Spec:       dpv1alpha1.ScopeSpec{Names: []string{scopeName}},
+				}
+			}
+			operationFilters[i] = append(operationFilters[i], extensionRef("Scope", scopeCRName))
+		}
+		if !operation.Secured {
+			authenticationName := apiUniqueID + "-no-authentication"
+			if _, exists := k8sArtifact.Authentication[authenticationName]; !exists {
+				disabled := true
+				k8sArtifact.Authentication[authenticationName] = &dpv1alpha2.Authentication{
+					TypeMeta:   metav1.TypeMeta{Kind: "Authentication", APIVersion: dpv1alpha2.GroupVersion.String()},
+					ObjectMeta: objectMeta(authenticationName),
+					Spec: dpv1alpha2.AuthenticationSpec{
+						Default:   &dpv1alpha2.AuthSpec{Disabled: &disabled},
+						TargetRef: policyTargetRef("Resource", apiUniqueID),
+					},
+				}
+			}
+			operationFilters[i] = append(operationFilters[i], extensionRef("Authentication", authenticationName))
+		}
+		if api.RateLimit == nil && operation.RateLimit != nil && operation.RateLimit.RequestsPerUnit > 0 {
+			rateLimitPolicy := createRateLimitPolicy(
+				objectMeta("resource-"+GetSha1Value(strings.Join([]string{apiUniqueID, operation.Target, operation.Verb}, "-"))),
+				operation.RateLimit, policyTargetRef("Resource", apiUniqueID))
+			k8sArtifact.RateLimitPolicies[rateLimitPolicy.Name] = rateLimitPolicy
+			operationFilters[i] = append(operationFilters[i], extensionRef("RateLimitPolicy", rateLimitPolicy.Name))
+		}
+	}
+
+	if api.EndpointConfigurations != nil {
+		productionVhost, sandboxVhost := getEnvironmentVhosts(environments)
+		endpointEnvironments := []struct {
+			name      string
+			endpoints EndpointConfigurationList
+			vhost     string
+		}{
+			{"production", api.EndpointConfigurations.Production, productionVhost},
+			{"sandbox", api.EndpointConfigurations.Sandbox, sandboxVhost},
+		}
+		for _, environment := range endpointEnvironments {
+			if environment.vhost == "" {
+				logger.LoggerTransformer.Debugf("The %s routes of the API %s:%s are not generated as it is not "+
+					"deployed in a %s environment", environment.name, api.Name, api.Version, environment.name)
+				continue
+			}
+			backendRefs := make([]gwapiv1.HTTPBackendRef, 0, len(environment.endpoints))
+			for i, endpoint := range environment.endpoints {
+				if endpoint == nil || endpoint.Endpoint == "" {
+					continue
+				}
+				backendName := getBackendName(organizationID, api, environment.name)
+				if len(environment.endpoints) > 1 {
+					backendName = fmt.Sprintf("%s-%d", backendName, i+1)
+				}
+				backend, err := createBackend(objectMeta(backendName), endpoint, apiUniqueID)
+				if err != nil {
+					logger.LoggerTransformer.Errorf("Error while generating the %s backend: %v", environment.name, err)
+					return nil, err
+				}
+				k8sArtifact.Backends[backend.Name] = backend
+				backendRefs = append(backendRefs, weightedBackendRef(backend.Name, endpoint.Weight))
+				if len(endpoint.Failovers) == 0 {
+					continue
+				}
+				failoverBackends, err := createFailoverBackends(objectMeta, backend, endpoint, apiUniqueID)
+				if err != nil {
+					logger.LoggerTransformer.Errorf("Error while generating the %s failover backends: %v",
+						environment.name, err)
+					return nil, err
+				}
+				for _, failoverBackend := range failoverBackends {
+					k8sArtifact.Backends[failoverBackend.Name] = failoverBackend
+					backendRefs = append(backendRefs, weightedBackendRef(failoverBackend.Name, 0))
+				}
+			}
+			if len(backendRefs) == 0 {
+				continue
+			}
+
+			authentication := createAuthentication(objectMeta(apiUniqueID+"-"+environment.name+"-authentication"),
+				api.Authentication, apiUniqueID)
+			k8sArtifact.Authentication[authentication.Name] = authentication
+
+			var routeNames []string
+			if getCRAPIType(api.Type) == "GraphQL" {
+				routeNames = addGQLRoutes(&k8sArtifact, objectMeta, apiUniqueID, environment.name, environment.vhost,
+					backendRefs, operations, operationFilters)
+			} else {
+				var err error
+				routeNames, err = addHTTPRoutes(&k8sArtifact, objectMeta, api, apiUniqueID, environment.name,
+					environment.vhost, backendRefs, operations, operationFilters)
+				if err != nil {
+					logger.LoggerTransformer.Errorf("Error while generating the %s routes: %v", environment.name, err)
+					return nil, err
+				}
+			}
+			envConfig := []dpv1alpha3.EnvConfig{{RouteRefs: routeNames}}
+			if environment.name == "production" {
+				k8sArtifact.API.Spec.Production = envConfig
+			} else {
+				k8sArtifact.API.Spec.Sandbox = envConfig
+			}
+		}
+	}
+
+	// Create ConfigMap to store the cert data if mTLS has enabled
+	if certContainer.ClientCertObj.CertAvailable {
+		createConfigMaps(certContainer.ClientCertObj.ClientCertFiles, &k8sArtifact)
+	}
+
+	// Create ConfigMap to store the cert data if endpoint security has enabled
+	if certContainer.EndpointCertObj.CertAvailable {
+		createConfigMaps(certContainer.EndpointCertObj.EndpointCertFiles, &k8sArtifact)
+	}
+
+	createEndpointSecrets(certContainer.SecretData, &k8sArtifact)
+
+	return &k8sArtifact, nil
+}
+
+// newK8sArtifacts returns a K8sArtifacts instance with all the CR maps initialized
+func newK8sArtifacts() K8sArtifacts {
+	return K8sArtifacts{HTTPRoutes: make(map[string]*gwapiv1.HTTPRoute), GQLRoutes: make(map[string]*dpv1alpha2.GQLRoute), Backends: make(map[string]*dpv1alpha2.Backend), Scopes: make(map[string]*dpv1alpha1.Scope), Authentication: make(map[string]*dpv1alpha2.Authentication), APIPolicies: make(map[string]*dpv1alpha3.APIPolicy), InterceptorServices: make(map[string]*dpv1alpha1.InterceptorService), ConfigMaps: make(map[string]*corev1.ConfigMap), Secrets: make(map[string]*corev1.Secret), RateLimitPolicies: make(map[string]*dpv1alpha1.RateLimitPolicy), AIRateLimitPolicies: make(map[string]*dpv1alpha3.AIRateLimitPolicy)}
+}
+
+// validateLocalCRGenerationSupport checks whether all the configurations in the apk-conf can be mapped by the
+// local generator. Interceptors, backend JWT, request mirroring and AI rate limits are only handled by the
+// config generator service.
+func validateLocalCRGenerationSupport(api *API) error {
+	checkPolicies := func(policies *OperationPolicies) error {
+		if policies == nil {
+			return nil
+		}
+		for _, policy := range append(append([]OperationPolicy{}, policies.Request...), policies.Response...) {
+			switch policy.PolicyName {
+			case addHeaderPolicy, removeHeaderPolicy, requestRedirectPolicy:
+			default:
+				return fmt.Errorf("%w: operation policy %s", ErrUnsupportedByLocalGenerator, policy.PolicyName)
+			}
+		}
+		return nil
+	}
+	if err := checkPolicies(api.APIPolicies); err != nil {
+		return err
+	}
+	if api.Operations != nil {
+		for _, operation := range *api.Operations {
+			if err := checkPolicies(operation.OperationPolicies); err != nil {
+				return err
+			}
+		}
+	}
+	if api.EndpointConfigurations != nil {
+		for _, endpoint := range append(append(EndpointConfigurationList{}, api.EndpointConfigurations.Production...),
+			api.EndpointConfigurations.Sandbox...) {
+			if endpoint != nil && endpoint.AIRatelimit.Enabled {
+				return fmt.Errorf("%w: AI rate limit", ErrUnsupportedByLocalGenerator)
+			}
+		}
+	}
+	return nil
+}
+
+// createDefinitionConfigMap stores the gzip compressed API definition in a ConfigMap
+func createDefinitionConfigMap(objectMeta metav1.ObjectMeta, apiDefinition string) (*corev1.ConfigMap, error) {
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	if _, err := gzipWriter.Write([]byte(apiDefinition)); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: objectMeta,
+		BinaryData: map[string][]byte{"definition": compressed.Bytes()},
+	}, nil
+}
+
+// getCRAPIType maps the apk-conf API type to the API type used in the API CR
+func getCRAPIType(apiType string) string {
+	if strings.EqualFold(apiType, "GRAPHQL") {
+		return "GraphQL"
+	}
+	if apiType == "" {
+		return restType
+	}
+	return strings.ToUpper(apiType)
+}
+
+// getFullBasePath appends the version to the context if it is not already a part of it
+func getFullBasePath(context string, version string) string {
+	if strings.Contains(context, "{version}") {
+		return strings.ReplaceAll(context, "{version}", version)
+	}
+	if strings.HasSuffix(context, "/"+version) {
+		return context
+	}
+	return strings.TrimSuffix(context, "/") + "/" + version
+}
+
+// getBackendName returns the name of the API level backend of an environment
+func getBackendName(organizationID string, api *API, environment string) string {
+	return "backend-" + GetSha1Value(strings.Join([]string{organizationID, api.Name, api.Version, environment}, "-")) + "-api"
+}
+
+// policyTargetRef returns the target reference of a policy CR attached to the API or its resources
+func policyTargetRef(kind string, apiUniqueID string) gwapiv1a2.NamespacedPolicyTargetReference {
+	return gwapiv1a2.NamespacedPolicyTargetReference{
+		Group: gwapiv1.Group(dpv1alpha3.GroupVersion.Group),
+		Kind:  gwapiv1.Kind(kind),
+		Name:  gwapiv1.ObjectName(apiUniqueID),
+	}
+}
+
+// extensionRef returns a route filter extension reference for a dp.wso2.com CR
+func extensionRef(kind string, name string) gwapiv1.LocalObjectReference {
+	return gwapiv1.LocalObjectReference{
+		Group: gwapiv1.Group(dpv1alpha3.GroupVersion.Group),
+		Kind:  gwapiv1.Kind(kind),
+		Name:  gwapiv1.ObjectName(name),
+	}
+}
+
+// createRateLimitPolicy returns a RateLimitPolicy CR for the given rate limit
+func createRateLimitPolicy(objectMeta metav1.ObjectMeta, rateLimit *RateLimit,
+	targetRef gwapiv1a2.NamespacedPolicyTargetReference) *dpv1alpha1.RateLimitPolicy {
+	return &dpv1alpha1.RateLimitPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: "RateLimitPolicy", APIVersion: dpv1alpha1.GroupVersion.String()},
+		ObjectMeta: objectMeta,
+		Spec: dpv1alpha1.RateLimitPolicySpec{
+			Default: &dpv1alpha1.RateLimitAPIPolicy{
+				API: &dpv1alpha1.APIRateLimitPolicy{
+					RequestsPerUnit: uint32(rateLimit.RequestsPerUnit),
+					Unit:            getRateLimitUnit(rateLimit.Unit),
+				},
+			},
+			TargetRef: targetRef,
+		},
+	}
+}
+
+// getRateLimitUnit maps the time unit received from the control plane to the unit accepted by the RateLimitPolicy
+func getRateLimitUnit(unit string) string {
+	switch strings.ToLower(unit) {
+	case "min", "minute":
+		return "Minute"
+	case "hour", "h":
+		return "Hour"
+	case "day", "d":
+		return "Day"
+	}
+	return CapitalizeFirstLetter(unit)
+}
+
+// getEnvironmentVhosts returns the production and the sandbox vhosts of the environments an API is deployed in. The
+// sandbox vhost of a hybrid environment is prefixed with sandbox. as done when the vhosts of the CRs are replaced.
+func getEnvironmentVhosts(environments []Environment) (string, string) {
+	var productionVhost, sandboxVhost string
+	for _, environment := range environments {
+		switch environment.Type {
+		case "hybrid":
+			productionVhost = environment.Vhost
+			sandboxVhost = "sandbox." + environment.Vhost
+		case "sandbox":
+			sandboxVhost = environment.Vhost
+		default:
+			productionVhost = environment.Vhost
+		}
+	}
+	return productionVhost, sandboxVhost
+}
+
+// createBackend returns the Backend CR for the given endpoint configuration
+func createBackend(objectMeta metav1.ObjectMeta, endpointConfig *EndpointConfiguration, apiUniqueID string) (*dpv1alpha2.Backend, error) {
+	endpointURL, err := neturl.Parse(endpointConfig.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	protocol := dpv1alpha2.HTTPProtocol
+	if strings.EqualFold(endpointURL.Scheme, "https") {
+		protocol = dpv1alpha2.HTTPSProtocol
+	}
+	service, err := getBackendService(endpointURL)
+	if err != nil {
+		return nil, err
+	}
+	backend := &dpv1alpha2.Backend{
+		TypeMeta:   metav1.TypeMeta{Kind: "Backend", APIVersion: dpv1alpha2.GroupVersion.String()},
+		ObjectMeta: objectMeta,
+		Spec: dpv1alpha2.BackendSpec{
+			Services: []dpv1alpha2.Service{service},
+			Protocol: protocol,
+			BasePath: endpointURL.Path,
+		},
+	}
+	if endpointConfig.Resiliency != nil && endpointConfig.Resiliency.Timeout != nil {
+		timeout := endpointConfig.Resiliency.Timeout
+		idleTimeout := timeout.DownstreamRequestIdleTimeout
+		if idleTimeout <= 0 {
+			idleTimeout = defaultDownstreamRequestIdleTimeout
+		}
+		// The request would be cut by the idle timeout before the response timeout otherwise
+		if idleTimeout < timeout.UpstreamResponseTimeout {
+			idleTimeout = timeout.UpstreamResponseTimeout
+		}
+		backend.Spec.Timeout = &dpv1alpha2.Timeout{
+			UpstreamResponseTimeout:      uint32(timeout.UpstreamResponseTimeout),
+			DownstreamRequestIdleTimeout: uint32(idleTimeout),
+		}
+	}
+	if endpointConfig.EndCertificate.Name != "" {
+		backend.Spec.TLS = &dpv1alpha2.TLSConfig{
+			ConfigMapRef: &dpv1alpha2.RefConfig{
+				Name: apiUniqueID + "-" + endpointConfig.EndCertificate.Name,
+				Key:  path.Base(endpointConfig.EndCertificate.Key),
+			},
+		}
+	}
+	if endpointConfig.EndSecurity.Enabled {
+		securityType := endpointConfig.EndSecurity.SecurityType
+		if securityType.APIKeyNameKey != "" {
+			backend.Spec.Security = &dpv1alpha2.SecurityConfig{
+				APIKey: &dpv1alpha2.APIKeySecurityConfig{
+					In:   securityType.In,
+					Name: securityType.APIKeyNameKey,
+					ValueFrom: dpv1alpha2.ValueRef{
+						Name:     securityType.SecretName,
+						ValueKey: securityType.APIKeyValueKey,
+					},
+				},
+			}
+		} else {
+			backend.Spec.Security = &dpv1alpha2.SecurityConfig{
+				Basic: &dpv1alpha2.BasicSecurityConfig{
+					SecretRef: dpv1alpha2.SecretRef{
+						Name:        securityType.SecretName,
+						UsernameKey: securityType.UsernameKey,
+						PasswordKey: securityType.PasswordKey,
+					},
+				},
+			}
+		}
+	}
+	return backend, nil
+}
+
+// getBackendService returns the backend service of the host and the port of the endpoint URL
+func getBackendService(endpointURL *neturl.URL) (dpv1alpha2.Service, error) {
+	if endpointURL.Hostname() == "" {
+		return dpv1alpha2.Service{}, fmt.Errorf("endpoint %s does not contain a host", endpointURL)
+	}
+	port := uint32(80)
+	if strings.EqualFold(endpointURL.Scheme, "https") {
+		port = 443
+	}
+	if _, portString, splitErr := net.SplitHostPort(endpointURL.Host); splitErr == nil {
+		parsedPort, parseErr := strconv.ParseUint(portString, 10, 32)
+		if parseErr != nil {
+			return dpv1alpha2.Service{}, parseErr
+		}
+		port = uint32(parsedPort)
+	}
+	return dpv1alpha2.Service{Host: endpointURL.Hostname(), Port: port}, nil
+}
+
+// createAuthentication maps the authentication configurations of the apk-conf to an Authentication CR
+func createAuthentication(objectMeta metav1.ObjectMeta, authConfigs *[]AuthConfiguration, apiUniqueID string) *dpv1alpha2.Authentication {
+	disabled := false
+	authTypes := &dpv1alpha2.APIAuth{
+		OAuth2: dpv1alpha2.OAuth2Auth{Required: mandatory, Header: "Authorization"},
+	}
+	if authConfigs != nil {
+		for _, authConfig := range *authConfigs {
+			switch authConfig.AuthType {
+			case oAuth2:
+				authTypes.OAuth2.Disabled = !authConfig.Enabled
+				authTypes.OAuth2.SendTokenToUpstream = authConfig.SendTokenUpStream
+				if authConfig.Required != "" {
+					authTypes.OAuth2.Required = authConfig.Required
+				}
+				if authConfig.HeaderName != "" {
+					authTypes.OAuth2.Header = authConfig.HeaderName
+				}
+			case jwt:
+				jwtDisabled := !authConfig.Enabled
+				authTypes.JWT = dpv1alpha2.JWT{
+					Disabled:            &jwtDisabled,
+					Header:              authConfig.HeaderName,
+					SendTokenToUpstream: authConfig.SendTokenUpStream,
+					Audience:            authConfig.Audience,
+				}
+			case apiKey:
+				if !authConfig.Enabled {
+					continue
+				}
+				apiKeyAuth := &dpv1alpha2.APIKeyAuth{Required: authConfig.Required}
+				if authConfig.HeaderEnabled && authConfig.HeaderName != "" {
+					apiKeyAuth.Keys = append(apiKeyAuth.Keys, dpv1alpha2.APIKey{In: "Header",
+						Name: authConfig.HeaderName, SendTokenToUpstream: authConfig.SendTokenUpStream})
+				}
+				if authConfig.QueryParamEnable && authConfig.QueryParamName != "" {
+					apiKeyAuth.Keys = append(apiKeyAuth.Keys, dpv1alpha2.APIKey{In: "Query",
+						Name: authConfig.QueryParamName, SendTokenToUpstream: authConfig.SendTokenUpStream})
+				}
+				authTypes.APIKey = apiKeyAuth
+			case mTLS:
+				if !authConfig.Enabled {
+					continue
+				}
+				mutualSSL := &dpv1alpha2.MutualSSLConfig{Required: authConfig.Required}
+				for _, certificate := range authConfig.Certificates {
+					mutualSSL.ConfigMapRefs = append(mutualSSL.ConfigMapRefs,
+						&dpv1alpha2.RefConfig{Name: certificate.Name, Key: certificate.Key})
+				}
+				authTypes.MutualSSL = mutualSSL
+			}
+		}
+	}
+	return &dpv1alpha2.Authentication{
+		TypeMeta:   metav1.TypeMeta{Kind: "Authentication", APIVersion: dpv1alpha2.GroupVersion.String()},
+		ObjectMeta: objectMeta,
+		Spec: dpv1alpha2.AuthenticationSpec{
+			Default: &dpv1alpha2.AuthSpec{
+				Disabled:  &disabled,
+				AuthTypes: authTypes,
+			},
+			TargetRef: gwapiv1a2.NamespacedPolicyTargetReference{
+				Group: gwapiv1.GroupName,
+				Kind:  gwapiv1.Kind(k8sKindAPI),
+				Name:  gwapiv1.ObjectName(apiUniqueID),
+			},
+		},
+	}
+}
+
+// gatewayParentRefs returns the parent references of the routes generated for an API
+func gatewayParentRefs() []gwapiv1.ParentReference {
+	group := gwapiv1.Group(gwapiv1.GroupName)
+	kind := gwapiv1.Kind("Gateway")
+	sectionName := gwapiv1.SectionName("httpslistener")
+	return []gwapiv1.ParentReference{{Group: &group, Kind: &kind, Name: "default", SectionName: &sectionName}}
+}
+
+// backendRef returns a route backend reference to a Backend CR
+func backendRef(name string) gwapiv1.HTTPBackendRef {
+	group := gwapiv1.Group(dpv1alpha3.GroupVersion.Group)
+	kind := gwapiv1.Kind("Backend")
+	return gwapiv1.HTTPBackendRef{
+		BackendRef: gwapiv1.BackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Group: &group, Kind: &kind, Name: gwapiv1.ObjectName(name)},
+		},
+	}
+}
+
+// createFailoverBackends returns a Backend CR for each of the failover endpoints of the endpoint, which is generated
+// as the primary backend. APK merges the backends of a route rule into a single upstream, hence all the backends
+// get a retry policy so that a failed request is retried on the other endpoints, and health checks so that an
+// unavailable endpoint is taken out until it recovers. The failover endpoints must have the same base path as the
+// primary endpoint as the requests are rewritten to the same path on all the endpoints of the upstream.
+func createFailoverBackends(objectMeta func(string) metav1.ObjectMeta, primary *dpv1alpha2.Backend,
+	endpointConfig *EndpointConfiguration, apiUniqueID string) ([]*dpv1alpha2.Backend, error) {
+	setFailoverPolicy(primary, len(endpointConfig.Failovers))
+	backends := make([]*dpv1alpha2.Backend, 0, len(endpointConfig.Failovers))
+	for i, failover := range endpointConfig.Failovers {
+		backend, err := createBackend(objectMeta(fmt.Sprintf("%s-failover-%d", primary.Name, i+1)), failover,
+			apiUniqueID)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSuffix(backend.Spec.BasePath, "/") != strings.TrimSuffix(primary.Spec.BasePath, "/") {
+			return nil, fmt.Errorf("%w: failover endpoint %s with a different base path than %s",
+				ErrUnsupportedByLocalGenerator, failover.Endpoint, endpointConfig.Endpoint)
+		}
+		setFailoverPolicy(backend, len(endpointConfig.Failovers))
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+// setFailoverPolicy sets the retry policy and the health checks of a backend of an endpoint with the given number of
+// failover endpoints
+func setFailoverPolicy(backend *dpv1alpha2.Backend, failovers int) {
+	backend.Spec.Retry = &dpv1alpha2.RetryConfig{
+		Count:              uint32(failovers),
+		BaseIntervalMillis: failoverRetryBaseIntervalMillis,
+		StatusCodes:        failoverStatusCodes,
+	}
+	backend.Spec.HealthCheck = &dpv1alpha2.HealthCheck{
+		Interval:           failoverHealthCheckIntervalSeconds,
+		Timeout:            failoverHealthCheckTimeoutSeconds,
+		UnhealthyThreshold: failoverHealthCheckThreshold,
+		HealthyThreshold:   failoverHealthCheckThreshold,
+	}
+}
+
+// weightedBackendRef returns a route backend reference to a Backend CR which receives the share of the requests
+// given by the weight. The requests are not split when the weight is not set.
+func weightedBackendRef(name string, weight int) gwapiv1.HTTPBackendRef {
+	ref := backendRef(name)
+	if weight > 0 {
+		backendWeight := int32(weight)
+		ref.Weight = &backendWeight
+	}
+	return ref
+}
+
+// chunkRuleCount returns the number of routes required to hold the given number of rules
+func chunkRuleCount(ruleCount int) int {
+	if ruleCount == 0 {
+		return 1
+	}
+	return (ruleCount + maxRulesPerRoute - 1) / maxRulesPerRoute
+}
+
+// addHTTPRoutes generates the HTTPRoutes of an environment and returns their names
+func addHTTPRoutes(k8sArtifact *K8sArtifacts, objectMeta func(string) metav1.ObjectMeta, api *API, apiUniqueID string,
+	environment string, vhost string, backendRefs []gwapiv1.HTTPBackendRef, operations []Operation,
+	operationFilters [][]gwapiv1.LocalObjectReference) ([]string, error) {
+	var apiLevelFilters []gwapiv1.HTTPRouteFilter
+	if api.APIPolicies != nil {
+		filters, err := getHTTPRouteFilters(api.APIPolicies)
+		if err != nil {
+			return nil, err
+		}
+		apiLevelFilters = filters
+	}
+	rules := make([]gwapiv1.HTTPRouteRule, 0, len(operations))
+	for i, operation := range operations {
+		pathRegex, rewritePath := getPathMatchAndRewrite(operation.Target)
+		pathMatchType := gwapiv1.PathMatchRegularExpression
+		method := gwapiv1.HTTPMethod(strings.ToUpper(operation.Verb))
+		rule := gwapiv1.HTTPRouteRule{
+			Matches: []gwapiv1.HTTPRouteMatch{{
+				Path:   &gwapiv1.HTTPPathMatch{Type: &pathMatchType, Value: &pathRegex},
+				Method: &method,
+			}},
+		}
+		operationLevelFilters, err := getHTTPRouteFilters(operation.OperationPolicies)
+		if err != nil {
+			return nil, err
+		}
+		redirected := false
+		for _, filter := range append(append([]gwapiv1.HTTPRouteFilter{}, apiLevelFilters...), operationLevelFilters...) {
+			if filter.Type == gwapiv1.HTTPRouteFilterRequestRedirect {
+				redirected = true
+			}
+			rule.Filters = append(rule.Filters, filter)
+		}
+		if !redirected {
+			// URL rewrite and backends can not be used together with a request redirect
+			rule.Filters = append([]gwapiv1.HTTPRouteFilter{{
+				Type: gwapiv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gwapiv1.HTTPURLRewriteFilter{
+					Path: &gwapiv1.HTTPPathModifier{Type: gwapiv1.FullPathHTTPPathModifier, ReplaceFullPath: &rewritePath},
+				},
+			}}, rule.Filters...)
+			rule.BackendRefs = backendRefs
+		}
+		for j := range operationFilters[i] {
+			rule.Filters = append(rule.Filters, gwapiv1.HTTPRouteFilter{
+				Type:         gwapiv1.HTTPRouteFilterExtensionRef,
+				ExtensionRef: &operationFilters[i][j],
+			})
+		}
+		rules = append(rules, rule)
+	}
+	routeNames := make([]string, 0, chunkRuleCount(len(rules)))
+	for i := 0; i < chunkRuleCount(len(rules)); i++ {
+		end := (i + 1) * maxRulesPerRoute
+		if end > len(rules) {
+			end = len(rules)
+		}
+		name := fmt.Sprintf("%s-%s-httproute-%d", apiUniqueID, environment, i+1)
+		k8sArtifact.HTTPRoutes[name] = &gwapiv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{Kind: k8sKindHTTPRoute, APIVersion: gwapiv1.GroupVersion.String()},
+			ObjectMeta: objectMeta(name),
+			Spec: gwapiv1.HTTPRouteSpec{
+				CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: gatewayParentRefs()},
+				Hostnames:       []gwapiv1.Hostname{gwapiv1.Hostname(vhost)},
+				Rules:           rules[i*maxRulesPerRoute : end],
+			},
+		}
+		routeNames = append(routeNames, name)
+	}
+	return routeNames, nil
+}
+
+// addGQLRoutes generates the GQLRoutes of an environment and returns their names
+func addGQLRoutes(k8sArtifact *K8sArtifacts, objectMeta func(string) metav1.ObjectMeta, apiUniqueID string,
+	environment string, vhost string, backendRefs []gwapiv1.HTTPBackendRef, operations []Operation,
+	operationFilters [][]gwapiv1.LocalObjectReference) []string {
+	rules := make([]dpv1alpha2.GQLRouteRules, 0, len(operations))
+	for i, operation := range operations {
+		gqlType := dpv1alpha2.GQLType(strings.ToUpper(operation.Verb))
+		target := operation.Target
+		rule := dpv1alpha2.GQLRouteRules{
+			Matches: []dpv1alpha2.GQLRouteMatch{{Type: &gqlType, Path: &target}},
+		}
+		for j := range operationFilters[i] {
+			rule.Filters = append(rule.Filters, dpv1alpha2.GQLRouteFilter{ExtensionRef: &operationFilters[i][j]})
+		}
+		rules = append(rules, rule)
+	}
+	routeNames := make([]string, 0, chunkRuleCount(len(rules)))
+	for i := 0; i < chunkRuleCount(len(rules)); i++ {
+		end := (i + 1) * maxRulesPerRoute
+		if end > len(rules) {
+			end = len(rules)
+		}
+		name := fmt.Sprintf("%s-%s-gqlroute-%d", apiUniqueID, environment, i+1)
+		k8sArtifact.GQLRoutes[name] = &dpv1alpha2.GQLRoute{
+			TypeMeta:   metav1.TypeMeta{Kind: "GQLRoute", APIVersion: dpv1alpha2.GroupVersion.String()},
+			ObjectMeta: objectMeta(name),
+			Spec: dpv1alpha2.GQLRouteSpec{
+				CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: gatewayParentRefs()},
+				Hostnames:       []gwapiv1.Hostname{gwapiv1.Hostname(vhost)},
+				BackendRefs:     backendRefs,
+				Rules:           rules[i*maxRulesPerRoute : end],
+			},
+		}
+		routeNames = append(routeNames, name)
+	}
+	return routeNames
+}
+
+// getPathMatchAndRewrite converts an operation target to the regular expression used to match the request path
+// and the path used to rewrite the request before routing it to the backend. The literal segments of the target
+// are escaped so that characters such as dots only match themselves.
+func getPathMatchAndRewrite(target string) (string, string) {
+	if target == "" {
+		target = "/"
+	}
+	groupIndex := 0
+	nextGroup := func() string {
+		groupIndex++
+		return "\\" + strconv.Itoa(groupIndex)
+	}
+	wildcard := strings.HasSuffix(target, "/*")
+	if wildcard {
+		target = strings.TrimSuffix(target, "*")
+	}
+	var matchPath strings.Builder
+	literalStart := 0
+	for _, param := range pathParamRegex.FindAllStringIndex(target, -1) {
+		matchPath.WriteString(regexp.QuoteMeta(target[literalStart:param[0]]))
+		matchPath.WriteString("(.*)")
+		literalStart = param[1]
+	}
+	matchPath.WriteString(regexp.QuoteMeta(target[literalStart:]))
+	rewritePath := pathParamRegex.ReplaceAllStringFunc(target, func(string) string { return nextGroup() })
+	if wildcard {
+		matchPath.WriteString("(.*)")
+		rewritePath += nextGroup()
+	}
+	return matchPath.String(), rewritePath
+}
+
+// getHTTPRouteFilters maps the header modification and redirect policies of the apk-conf to HTTPRoute filters
+func getHTTPRouteFilters(policies *OperationPolicies) ([]gwapiv1.HTTPRouteFilter, error) {
+	if policies == nil {
+		return nil, nil
+	}
+	var filters []gwapiv1.HTTPRouteFilter
+	requestHeaders := &gwapiv1.HTTPHeaderFilter{}
+	responseHeaders := &gwapiv1.HTTPHeaderFilter{}
+	addHeaderModification := func(headerFilter *gwapiv1.HTTPHeaderFilter, policy OperationPolicy) {
+		header, ok := policy.Parameters.(Header)
+		if !ok {
+			return
+		}
+		if policy.PolicyName == addHeaderPolicy {
+			headerFilter.Add = append(headerFilter.Add,
+				gwapiv1.HTTPHeader{Name: gwapiv1.HTTPHeaderName(header.HeaderName), Value: header.HeaderValue})
+		} else if policy.PolicyName == removeHeaderPolicy {
+			headerFilter.Remove = append(headerFilter.Remove, header.HeaderName)
+		}
+	}
+	for _, policy := range policies.Request {
+		if policy.PolicyName == requestRedirectPolicy {
+			redirect, ok := policy.Parameters.(RedirectPolicy)
+			if !ok {
+				continue
+			}
+			redirectFilter, err := getRequestRedirectFilter(redirect)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, gwapiv1.HTTPRouteFilter{
+				Type:            gwapiv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: redirectFilter,
+			})
+			continue
+		}
+		addHeaderModification(requestHeaders, policy)
+	}
+	for _, policy := range policies.Response {
+		addHeaderModification(responseHeaders, policy)
+	}
+	if len(requestHeaders.Add) > 0 || len(requestHeaders.Remove) > 0 {
+		filters = append(filters, gwapiv1.HTTPRouteFilter{
+			Type:                  gwapiv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: requestHeaders,
+		})
+	}
+	if len(responseHeaders.Add) > 0 || len(responseHeaders.Remove) > 0 {
+		filters = append(filters, gwapiv1.HTTPRouteFilter{
+			Type:                   gwapiv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: responseHeaders,
+		})
+	}
+	return filters, nil
+}
+
+// getRequestRedirectFilter maps a redirect policy to a HTTPRoute request redirect filter
+func getRequestRedirectFilter(redirect RedirectPolicy) (*gwapiv1.HTTPRequestRedirectFilter, error) {
+	redirectURL, err := neturl.Parse(redirect.URL)
+	if err != nil {
+		return nil, err
+	}
+	redirectFilter := &gwapiv1.HTTPRequestRedirectFilter{}
+	if redirectURL.Scheme != "" {
+		scheme := strings.ToLower(redirectURL.Scheme)
+		redirectFilter.Scheme = &scheme
+	}
+	if redirectURL.Hostname() != "" {
+		hostname := gwapiv1.PreciseHostname(redirectURL.Hostname())
+		redirectFilter.Hostname = &hostname
+	}
+	if redirectURL.Port() != "" {
+		port, parseErr := strconv.ParseInt(redirectURL.Port(), 10, 32)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		portNumber := gwapiv1.PortNumber(port)
+		redirectFilter.Port = &portNumber
+	}
+	if redirectURL.Path != "" {
+		redirectPath := redirectURL.Path
+		redirectFilter.Path = &gwapiv1.HTTPPathModifier{Type: gwapiv1.FullPathHTTPPathModifier, ReplaceFullPath: &redirectPath}
+	}
+	if redirect.StatusCode != 0 {
+		statusCode := redirect.StatusCode
+		redirectFilter.StatusCode = &statusCode
+	}
+	return redirectFilter, nil
+}
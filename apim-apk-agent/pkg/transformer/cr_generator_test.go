@@ -0,0 +1,517 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dpv1alpha3 "github.com/wso2/apk/common-go-libs/apis/dp/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	k8Yaml "sigs.k8s.io/yaml"
+)
+
+// updateGolden rewrites the golden files with the generated CRs instead of comparing them, e.g.
+// go test ./pkg/transformer -run TestGenerateCRsLocally -update
+var updateGolden = flag.Bool("update", false, "update the golden files of the locally generated CRs")
+
+// readTestAPIArtifacts returns the API artifacts inside the base test payload by the API name
+func readTestAPIArtifacts(t *testing.T) map[string]*APIArtifact {
+	return readTestPayloadArtifacts(t, filepath.Join(testResourcesDir, "Base", "Test_Payload.zip"))
+}
+
+// readTestPayloadArtifacts returns the API artifacts inside the given test payload by the API name
+func readTestPayloadArtifacts(t *testing.T, payloadPath string) map[string]*APIArtifact {
+	zipFileBytes, err := os.ReadFile(payloadPath)
+	if err != nil {
+		t.Fatal("Error reading test payload:", err)
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(zipFileBytes), int64(len(zipFileBytes)))
+	if err != nil {
+		t.Fatal("Error creating zip reader:", err)
+	}
+	artifacts := make(map[string]*APIArtifact)
+	for _, zipFile := range zipReader.File {
+		artifact, err := DecodeAPIArtifact(zipFile)
+		if err != nil || artifact.APIJson == "" {
+			continue
+		}
+		var apiYaml APIYaml
+		if err := json.Unmarshal([]byte(artifact.APIJson), &apiYaml); err != nil {
+			continue
+		}
+		artifacts[apiYaml.Data.Name] = artifact
+	}
+	return artifacts
+}
+
+// testEnvironments are the environments of the test APIs with the vhosts of the CRs of the config generator service
+var testEnvironments = []Environment{
+	{Name: "Default", Vhost: "default.gw.wso2.com", Type: "production"},
+	{Name: "Sandbox", Vhost: "default.sandbox.gw.wso2.com", Type: "sandbox"},
+}
+
+// generateLocalTestCRs generates the apk-conf for the given artifact and the CRs from it
+func generateLocalTestCRs(t *testing.T, artifact *APIArtifact, updateAPI func(*API)) *K8sArtifacts {
+	_, _, _, _, endpointSecurityData, api, _, _, err := GenerateAPKConf(artifact.APIJson, artifact.CertArtifact, "default")
+	assert.NoError(t, err)
+	if updateAPI != nil {
+		updateAPI(api)
+	}
+	certContainer := CertContainer{
+		ClientCertObj:   artifact.CertMeta,
+		EndpointCertObj: artifact.EndpointCertMeta,
+		SecretData:      endpointSecurityData,
+	}
+	k8sArtifact, err := GenerateCRsLocally(api, artifact.Schema, certContainer, "default", testEnvironments)
+	assert.NoError(t, err)
+	assert.NotNil(t, k8sArtifact)
+	return k8sArtifact
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// addGoldenCRs adds the CRs of a kind to the CRs serialized into a golden file by the kind and the name
+func addGoldenCRs[T client.Object](objects map[string]client.Object, kind string, crs map[string]T) {
+	for name, cr := range crs {
+		objects[kind+"/"+name] = cr
+	}
+}
+
+// marshalK8sArtifacts serializes all the CRs of the artifact into a multi-document YAML ordered by the kind and
+// the name of the CRs
+func marshalK8sArtifacts(t *testing.T, k8sArtifact *K8sArtifacts) []byte {
+	objects := map[string]client.Object{"API/" + k8sArtifact.API.Name: &k8sArtifact.API}
+	addGoldenCRs(objects, "HTTPRoute", k8sArtifact.HTTPRoutes)
+	addGoldenCRs(objects, "GQLRoute", k8sArtifact.GQLRoutes)
+	addGoldenCRs(objects, "Backend", k8sArtifact.Backends)
+	addGoldenCRs(objects, "Scope", k8sArtifact.Scopes)
+	addGoldenCRs(objects, "Authentication", k8sArtifact.Authentication)
+	addGoldenCRs(objects, "APIPolicy", k8sArtifact.APIPolicies)
+	addGoldenCRs(objects, "InterceptorService", k8sArtifact.InterceptorServices)
+	addGoldenCRs(objects, "ConfigMap", k8sArtifact.ConfigMaps)
+	addGoldenCRs(objects, "Secret", k8sArtifact.Secrets)
+	addGoldenCRs(objects, "RateLimitPolicy", k8sArtifact.RateLimitPolicies)
+	addGoldenCRs(objects, "AIRateLimitPolicy", k8sArtifact.AIRateLimitPolicies)
+	if k8sArtifact.BackendJWT != nil {
+		objects["BackendJWT/"+k8sArtifact.BackendJWT.Name] = k8sArtifact.BackendJWT
+	}
+	var documents []string
+	for _, key := range sortedKeys(objects) {
+		content, err := k8Yaml.Marshal(objects[key])
+		if err != nil {
+			t.Fatalf("Error marshalling %s: %v", key, err)
+		}
+		documents = append(documents, string(content))
+	}
+	return []byte(strings.Join(documents, "---\n"))
+}
+
+// assertMatchesGolden serializes the full CR set and compares it byte for byte with the golden file in the golden
+// test resources. The golden file is rewritten instead when the tests are run with the -update flag.
+func assertMatchesGolden(t *testing.T, goldenFile string, generated *K8sArtifacts) {
+	goldenPath := filepath.Join(testResourcesDir, "Golden", goldenFile)
+	content := marshalK8sArtifacts(t, generated)
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, content, 0644); err != nil {
+			t.Fatal("Error updating the golden file:", err)
+		}
+		return
+	}
+	golden, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatal("Error reading the golden file:", err)
+	}
+	assert.Equal(t, string(golden), string(content), "CRs differ from %s, run the tests with -update to accept "+
+		"the changes", goldenFile)
+}
+
+// assertMatchesConfigGenerator compares the locally generated CRs with the CRs generated by the config generator
+// service
+func assertMatchesConfigGenerator(t *testing.T, golden *K8sArtifacts, generated *K8sArtifacts) {
+	assert.Equal(t, golden.API.Name, generated.API.Name)
+	assert.Equal(t, golden.API.Labels, generated.API.Labels)
+	assert.Equal(t, golden.API.Spec.APIName, generated.API.Spec.APIName)
+	assert.Equal(t, golden.API.Spec.APIVersion, generated.API.Spec.APIVersion)
+	assert.Equal(t, golden.API.Spec.APIType, generated.API.Spec.APIType)
+	assert.Equal(t, golden.API.Spec.BasePath, generated.API.Spec.BasePath)
+	assert.Equal(t, golden.API.Spec.DefinitionFileRef, generated.API.Spec.DefinitionFileRef)
+	assert.Equal(t, golden.API.Spec.DefinitionPath, generated.API.Spec.DefinitionPath)
+	assert.Equal(t, golden.API.Spec.IsDefaultVersion, generated.API.Spec.IsDefaultVersion)
+	assert.Equal(t, golden.API.Spec.Organization, generated.API.Spec.Organization)
+
+	assert.Equal(t, sortedKeys(golden.HTTPRoutes), sortedKeys(generated.HTTPRoutes))
+	for name, goldenRoute := range golden.HTTPRoutes {
+		generatedRoute := generated.HTTPRoutes[name]
+		assert.Equal(t, goldenRoute.Labels, generatedRoute.Labels, name)
+		assert.Equal(t, goldenRoute.Spec.ParentRefs, generatedRoute.Spec.ParentRefs, name)
+		assert.Equal(t, goldenRoute.Spec.Hostnames, generatedRoute.Spec.Hostnames, name)
+		assert.Equal(t, goldenRoute.Spec.Rules, generatedRoute.Spec.Rules, name)
+	}
+	assert.Equal(t, sortedKeys(golden.GQLRoutes), sortedKeys(generated.GQLRoutes))
+	for name, goldenRoute := range golden.GQLRoutes {
+		generatedRoute := generated.GQLRoutes[name]
+		assert.Equal(t, goldenRoute.Labels, generatedRoute.Labels, name)
+		assert.Equal(t, goldenRoute.Spec.ParentRefs, generatedRoute.Spec.ParentRefs, name)
+		assert.Equal(t, goldenRoute.Spec.Hostnames, generatedRoute.Spec.Hostnames, name)
+		assert.Equal(t, goldenRoute.Spec.BackendRefs, generatedRoute.Spec.BackendRefs, name)
+		assert.Equal(t, goldenRoute.Spec.Rules, generatedRoute.Spec.Rules, name)
+	}
+	assert.Equal(t, sortedKeys(golden.Backends), sortedKeys(generated.Backends))
+	for name, goldenBackend := range golden.Backends {
+		assert.Equal(t, goldenBackend.Labels, generated.Backends[name].Labels, name)
+		assert.Equal(t, goldenBackend.Spec, generated.Backends[name].Spec, name)
+	}
+	assert.Equal(t, sortedKeys(golden.Authentication), sortedKeys(generated.Authentication))
+	for name, goldenAuthentication := range golden.Authentication {
+		generatedAuthentication := generated.Authentication[name]
+		assert.Equal(t, goldenAuthentication.Labels, generatedAuthentication.Labels, name)
+		assert.Equal(t, goldenAuthentication.Spec.TargetRef, generatedAuthentication.Spec.TargetRef, name)
+		assert.Equal(t, goldenAuthentication.Spec.Default.Disabled, generatedAuthentication.Spec.Default.Disabled, name)
+		assert.Equal(t, goldenAuthentication.Spec.Default.AuthTypes.OAuth2, generatedAuthentication.Spec.Default.AuthTypes.OAuth2, name)
+	}
+	assert.Equal(t, sortedKeys(golden.APIPolicies), sortedKeys(generated.APIPolicies))
+	for name, goldenPolicy := range golden.APIPolicies {
+		generatedPolicy := generated.APIPolicies[name]
+		assert.Equal(t, goldenPolicy.Labels, generatedPolicy.Labels, name)
+		assert.Equal(t, goldenPolicy.Spec.TargetRef, generatedPolicy.Spec.TargetRef, name)
+		assert.Equal(t, goldenPolicy.Spec.Default.SubscriptionValidation, generatedPolicy.Spec.Default.SubscriptionValidation, name)
+	}
+	assert.Equal(t, sortedKeys(golden.Scopes), sortedKeys(generated.Scopes))
+	assert.Equal(t, sortedKeys(golden.RateLimitPolicies), sortedKeys(generated.RateLimitPolicies))
+	// Certificate ConfigMaps are added from the certificates in the API project
+	for name := range golden.ConfigMaps {
+		assert.Contains(t, generated.ConfigMaps, name)
+	}
+}
+
+// assertDefinition checks whether the definition ConfigMap holds the compressed API definition
+func assertDefinition(t *testing.T, k8sArtifact *K8sArtifacts, apiDefinition string) {
+	configMap, found := k8sArtifact.ConfigMaps[k8sArtifact.API.Spec.DefinitionFileRef]
+	if !assert.True(t, found) {
+		return
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(configMap.BinaryData["definition"]))
+	assert.NoError(t, err)
+	definition, err := io.ReadAll(gzipReader)
+	assert.NoError(t, err)
+	assert.Equal(t, apiDefinition, string(definition))
+}
+
+func TestGenerateCRsLocallyForRESTAPI(t *testing.T) {
+	artifact, found := readTestAPIArtifacts(t)["PizzaShackAPI"]
+	if !found {
+		t.Fatal("PizzaShackAPI not found in the test payload")
+	}
+	var golden K8sArtifacts
+	assert.NoError(t, json.Unmarshal([]byte(HTTPk8Json), &golden))
+
+	generated := generateLocalTestCRs(t, artifact, nil)
+	assertMatchesConfigGenerator(t, &golden, generated)
+	assertMatchesGolden(t, "PizzaShackAPI.yaml", generated)
+	assertDefinition(t, generated, artifact.Schema)
+	assert.Equal(t, []string{"e7c96c6e9e1a402b0437af3a4e18b2daa0e699b9-production-httproute-1"},
+		generated.API.Spec.Production[0].RouteRefs)
+	assert.Equal(t, []string{"e7c96c6e9e1a402b0437af3a4e18b2daa0e699b9-sandbox-httproute-1"},
+		generated.API.Spec.Sandbox[0].RouteRefs)
+}
+
+func TestGenerateCRsLocallyForGraphQLAPI(t *testing.T) {
+	artifact, found := readTestAPIArtifacts(t)["StarWarsAPI"]
+	if !found {
+		t.Fatal("StarWarsAPI not found in the test payload")
+	}
+	var golden K8sArtifacts
+	assert.NoError(t, json.Unmarshal([]byte(GQLk8Json), &golden))
+
+	// The golden file was generated for the same API published with a different name and context
+	generated := generateLocalTestCRs(t, artifact, func(api *API) {
+		api.Name = "StartWarsAPI"
+		api.Context = "/swapi"
+	})
+	assertMatchesConfigGenerator(t, &golden, generated)
+	assertMatchesGolden(t, "StarWarsAPI.yaml", generated)
+	assertDefinition(t, generated, artifact.Schema)
+	assert.Len(t, generated.API.Spec.Production[0].RouteRefs, 2)
+	assert.Len(t, generated.API.Spec.Sandbox[0].RouteRefs, 2)
+}
+
+func TestGenerateCRsLocallyWithResourceConfigurations(t *testing.T) {
+	api := &API{
+		Name:                   "TestAPI",
+		Version:                "1.0.0",
+		Context:                "/test",
+		Type:                   restType,
+		SubscriptionValidation: true,
+		EndpointConfigurations: &EndpointConfigurations{
+			Production: EndpointConfigurationList{{Endpoint: "http://backend.default.svc:8080/api",
+				Resiliency: &Resiliency{Timeout: &EndpointTimeout{UpstreamResponseTimeout: 600}}}},
+		},
+		Operations: &[]Operation{
+			{Target: "/pets/{petId}/tags/{tagId}", Verb: "GET", Scopes: []string{"read"}, Secured: true},
+			{Target: "/*", Verb: "POST", Secured: false, RateLimit: &RateLimit{RequestsPerUnit: 10, Unit: "min"},
+				OperationPolicies: &OperationPolicies{Request: []OperationPolicy{
+					{PolicyName: addHeaderPolicy, Parameters: Header{HeaderName: "x-test", HeaderValue: "value"}},
+				}}},
+		},
+	}
+	k8sArtifact, err := GenerateCRsLocally(api, "{}", CertContainer{}, "default", testEnvironments)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/test/1.0.0", k8sArtifact.API.Spec.BasePath)
+	assert.Empty(t, k8sArtifact.API.Spec.Sandbox)
+	assert.Len(t, k8sArtifact.Scopes, 1)
+	assert.Len(t, k8sArtifact.RateLimitPolicies, 1)
+	for _, rateLimitPolicy := range k8sArtifact.RateLimitPolicies {
+		assert.Equal(t, "Minute", rateLimitPolicy.Spec.Default.API.Unit)
+	}
+	backend := k8sArtifact.Backends[getBackendName("default", api, "production")]
+	if assert.NotNil(t, backend) {
+		assert.Equal(t, "backend.default.svc", backend.Spec.Services[0].Host)
+		assert.Equal(t, uint32(8080), backend.Spec.Services[0].Port)
+		assert.Equal(t, "/api", backend.Spec.BasePath)
+		if assert.NotNil(t, backend.Spec.Timeout) {
+			assert.Equal(t, uint32(600), backend.Spec.Timeout.UpstreamResponseTimeout)
+			assert.Equal(t, uint32(600), backend.Spec.Timeout.DownstreamRequestIdleTimeout)
+		}
+	}
+
+	route := k8sArtifact.HTTPRoutes[k8sArtifact.API.Spec.Production[0].RouteRefs[0]]
+	if assert.NotNil(t, route) && assert.Len(t, route.Spec.Rules, 2) {
+		assert.Equal(t, "/pets/(.*)/tags/(.*)", *route.Spec.Rules[0].Matches[0].Path.Value)
+		assert.Equal(t, "/pets/\\1/tags/\\2", *route.Spec.Rules[0].Filters[0].URLRewrite.Path.ReplaceFullPath)
+		assert.Equal(t, "/(.*)", *route.Spec.Rules[1].Matches[0].Path.Value)
+		assert.Equal(t, "/\\1", *route.Spec.Rules[1].Filters[0].URLRewrite.Path.ReplaceFullPath)
+		assert.Equal(t, "x-test", string(route.Spec.Rules[1].Filters[1].RequestHeaderModifier.Add[0].Name))
+		// resource policy, no-authentication and rate limit references
+		assert.Len(t, route.Spec.Rules[1].Filters, 5)
+	}
+}
+
+func TestGetPathMatchAndRewrite(t *testing.T) {
+	tests := []struct {
+		target      string
+		matchPath   string
+		rewritePath string
+	}{
+		{target: "", matchPath: "/", rewritePath: "/"},
+		{target: "/menu", matchPath: "/menu", rewritePath: "/menu"},
+		{target: "/*", matchPath: "/(.*)", rewritePath: "/\\1"},
+		{target: "/order/{orderId}", matchPath: "/order/(.*)", rewritePath: "/order/\\1"},
+		{target: "/order.json", matchPath: "/order\\.json", rewritePath: "/order.json"},
+		{target: "/v1.0/{id}+/items(all)/*", matchPath: "/v1\\.0/(.*)\\+/items\\(all\\)/(.*)",
+			rewritePath: "/v1.0/\\1+/items(all)/\\2"},
+	}
+	for _, test := range tests {
+		matchPath, rewritePath := getPathMatchAndRewrite(test.target)
+		assert.Equal(t, test.matchPath, matchPath, test.target)
+		assert.Equal(t, test.rewritePath, rewritePath, test.target)
+	}
+}
+
+func TestGenerateCRsLocallyWithEnvironmentVhosts(t *testing.T) {
+	api := &API{
+		Name:    "TestAPI",
+		Version: "1.0.0",
+		Context: "/test",
+		Type:    restType,
+		EndpointConfigurations: &EndpointConfigurations{
+			Production: EndpointConfigurationList{{Endpoint: "http://backend:8080/api"}},
+			Sandbox:    EndpointConfigurationList{{Endpoint: "http://sandbox-backend:8080/api"}},
+		},
+		Operations: &[]Operation{{Target: "/*", Verb: "GET", Secured: true}},
+	}
+	routeHostnames := func(k8sArtifact *K8sArtifacts, envConfigs []dpv1alpha3.EnvConfig) []gwapiv1.Hostname {
+		if len(envConfigs) == 0 {
+			return nil
+		}
+		return k8sArtifact.HTTPRoutes[envConfigs[0].RouteRefs[0]].Spec.Hostnames
+	}
+
+	k8sArtifact, err := GenerateCRsLocally(api, "{}", CertContainer{}, "default",
+		[]Environment{{Name: "Default", Vhost: "api.example.com", Type: "hybrid"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []gwapiv1.Hostname{"api.example.com"}, routeHostnames(k8sArtifact, k8sArtifact.API.Spec.Production))
+	assert.Equal(t, []gwapiv1.Hostname{"sandbox.api.example.com"},
+		routeHostnames(k8sArtifact, k8sArtifact.API.Spec.Sandbox))
+
+	// The sandbox routes are not generated when the API is only deployed in a production environment
+	k8sArtifact, err = GenerateCRsLocally(api, "{}", CertContainer{}, "default",
+		[]Environment{{Name: "Default", Vhost: "api.example.com", Type: "production"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []gwapiv1.Hostname{"api.example.com"}, routeHostnames(k8sArtifact, k8sArtifact.API.Spec.Production))
+	assert.Empty(t, k8sArtifact.API.Spec.Sandbox)
+	assert.Len(t, k8sArtifact.HTTPRoutes, 1)
+}
+
+func TestGenerateCRsLocallyUnsupportedConfigurations(t *testing.T) {
+	api := &API{
+		Name:    "TestAPI",
+		Version: "1.0.0",
+		Context: "/test",
+		APIPolicies: &OperationPolicies{Request: []OperationPolicy{
+			{PolicyName: interceptorPolicy, Parameters: InterceptorService{BackendURL: "http://interceptor:8080"}},
+		}},
+	}
+	_, err := GenerateCRsLocally(api, "{}", CertContainer{}, "default", testEnvironments)
+	assert.True(t, errors.Is(err, ErrUnsupportedByLocalGenerator))
+
+	_, err = GenerateCRsLocally(nil, "{}", CertContainer{}, "default", testEnvironments)
+	assert.Error(t, err)
+	_, err = GenerateCRsLocally(&API{Name: "TestAPI"}, "", CertContainer{}, "default", testEnvironments)
+	assert.Error(t, err)
+}
+
+func TestGenerateCRsLocallyWithLoadBalancedEndpoints(t *testing.T) {
+	artifact, found := readTestPayloadArtifacts(t,
+		filepath.Join(testResourcesDir, "Endpoints", "LoadBalanced_Payload.zip"))["PizzaShackAPI"]
+	if !found {
+		t.Fatal("PizzaShackAPI not found in the test payload")
+	}
+
+	result := ValidateAPIArtifact(artifact, "default")
+	assert.False(t, result.HasErrors(), "%v", result.Err())
+	for _, field := range issueFields(result.Warnings) {
+		assert.NotContains(t, field, "endpointConfig")
+	}
+
+	generated := generateLocalTestCRs(t, artifact, nil)
+	assertMatchesGolden(t, "PizzaShackAPI_LoadBalanced.yaml", generated)
+	assert.Len(t, generated.Backends, 3)
+	api := &API{Name: "PizzaShackAPI", Version: "1.0.0"}
+	prodBackendName := getBackendName("default", api, "production")
+	for i, host := range []string{"pizza-1", "pizza-2"} {
+		backend := generated.Backends[fmt.Sprintf("%s-%d", prodBackendName, i+1)]
+		if assert.NotNil(t, backend) {
+			assert.Equal(t, host, backend.Spec.Services[0].Host)
+			assert.Nil(t, backend.Spec.Retry)
+		}
+	}
+	assert.Equal(t, uint32(45), generated.Backends[prodBackendName+"-2"].Spec.Timeout.UpstreamResponseTimeout)
+	assert.NotNil(t, generated.Backends[getBackendName("default", api, "sandbox")])
+
+	for _, route := range generated.HTTPRoutes {
+		for _, rule := range route.Spec.Rules {
+			if route.Name == generated.API.Spec.Production[0].RouteRefs[0] {
+				if assert.Len(t, rule.BackendRefs, 2) {
+					assert.Equal(t, int32(1), *rule.BackendRefs[0].Weight)
+					assert.Equal(t, int32(1), *rule.BackendRefs[1].Weight)
+				}
+			} else {
+				assert.Len(t, rule.BackendRefs, 1)
+			}
+		}
+	}
+}
+
+func TestGenerateCRsLocallyWithFailoverEndpoints(t *testing.T) {
+	artifact, found := readTestPayloadArtifacts(t,
+		filepath.Join(testResourcesDir, "Endpoints", "Failover_Payload.zip"))["PizzaShackAPI"]
+	if !found {
+		t.Fatal("PizzaShackAPI not found in the test payload")
+	}
+
+	result := ValidateAPIArtifact(artifact, "default")
+	assert.False(t, result.HasErrors(), "%v", result.Err())
+	assert.Contains(t, issueFields(result.Warnings), "endpointConfig.production_failovers")
+	assert.NotContains(t, issueFields(result.Warnings), "endpointConfig.sandbox_failovers")
+
+	// The apk-conf has no failover endpoints, so only the local generator applies them
+	apkConf, _, _, _, _, _, _, _, err := GenerateAPKConf(artifact.APIJson, artifact.CertArtifact, "default")
+	assert.NoError(t, err)
+	assert.NotContains(t, apkConf, "pizza-backup")
+
+	generated := generateLocalTestCRs(t, artifact, nil)
+	assertMatchesGolden(t, "PizzaShackAPI_Failover.yaml", generated)
+	assert.Len(t, generated.Backends, 4)
+	api := &API{Name: "PizzaShackAPI", Version: "1.0.0"}
+	prodBackendName := getBackendName("default", api, "production")
+	for i, host := range []string{"pizza", "pizza-backup-1", "pizza-backup-2"} {
+		backendName := prodBackendName
+		if i > 0 {
+			backendName = fmt.Sprintf("%s-failover-%d", prodBackendName, i)
+		}
+		backend := generated.Backends[backendName]
+		if assert.NotNil(t, backend, backendName) {
+			assert.Equal(t, host, backend.Spec.Services[0].Host)
+			if assert.NotNil(t, backend.Spec.Retry, backendName) {
+				assert.Equal(t, uint32(2), backend.Spec.Retry.Count)
+				assert.Equal(t, failoverStatusCodes, backend.Spec.Retry.StatusCodes)
+			}
+			assert.NotNil(t, backend.Spec.HealthCheck, backendName)
+		}
+	}
+	failover := generated.Backends[prodBackendName+"-failover-1"]
+	if assert.NotNil(t, failover) && assert.NotNil(t, failover.Spec.TLS.ConfigMapRef) {
+		assert.Contains(t, failover.Spec.TLS.ConfigMapRef.Name, "epcert-prod-1")
+	}
+	assert.Equal(t, uint32(45),
+		generated.Backends[prodBackendName+"-failover-2"].Spec.Timeout.UpstreamResponseTimeout)
+	sandboxBackend := generated.Backends[getBackendName("default", api, "sandbox")]
+	if assert.NotNil(t, sandboxBackend) {
+		assert.Nil(t, sandboxBackend.Spec.Retry)
+		assert.Nil(t, sandboxBackend.Spec.HealthCheck)
+	}
+
+	for _, route := range generated.HTTPRoutes {
+		for _, rule := range route.Spec.Rules {
+			if route.Name == generated.API.Spec.Production[0].RouteRefs[0] {
+				assert.Len(t, rule.BackendRefs, 3)
+			} else {
+				assert.Len(t, rule.BackendRefs, 1)
+			}
+		}
+	}
+
+	// APK requires the endpoints of a route to share the base path
+	_, _, _, _, endpointSecurityData, apkAPI, _, _, err := GenerateAPKConf(artifact.APIJson, artifact.CertArtifact,
+		"default")
+	assert.NoError(t, err)
+	for _, endpoint := range apkAPI.EndpointConfigurations.Production {
+		for _, failoverEndpoint := range endpoint.Failovers {
+			failoverEndpoint.Endpoint = "https://pizza-backup:9443/v2"
+		}
+	}
+	certContainer := CertContainer{
+		ClientCertObj:   artifact.CertMeta,
+		EndpointCertObj: artifact.EndpointCertMeta,
+		SecretData:      endpointSecurityData,
+	}
+	_, err = GenerateCRsLocally(apkAPI, artifact.Schema, certContainer, "default", testEnvironments)
+	assert.True(t, errors.Is(err, ErrUnsupportedByLocalGenerator))
+}
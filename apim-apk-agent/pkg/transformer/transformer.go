@@ -180,13 +180,11 @@ func GenerateAPKConf(APIJson string, certArtifact CertificateArtifact, organizat
 		endCertAvailable = true
 	}
 
-	sandboxURL := apiYamlData.EndpointConfig.SandboxEndpoints.URL
-	prodURL := apiYamlData.EndpointConfig.ProductionEndpoints.URL
 	endpointSecurityData := apiYamlData.EndpointConfig.EndpointSecurity
 	apiUniqueID := GetUniqueIDForAPI(apiYamlData.Name, apiYamlData.Version, apiYamlData.OrganizationID)
 	logger.LoggerTransformer.Infof("Maxtps: %+v", apiYamlData)
 	prodAIRatelimit, sandAIRatelimit := prepareAIRatelimit(apiYamlData.MaxTps)
-	endpointRes := getEndpointConfigs(sandboxURL, prodURL, endCertAvailable, endpointCertList, endpointSecurityData, apiUniqueID, prodAIRatelimit, sandAIRatelimit)
+	endpointRes := getEndpointConfigs(apiYamlData.EndpointConfig, endCertAvailable, endpointCertList, apiUniqueID, prodAIRatelimit, sandAIRatelimit)
 
 	apk.EndpointConfigurations = &endpointRes
 
@@ -637,102 +635,139 @@ func mapAuthConfigs(apiUUID string, authHeader string, configuredAPIKeyHeader st
 	return authConfigs
 }
 
-// getEndpointConfigs will map the endpoints and there security configurations and returns them
+// getEndpointConfigs will map the endpoints and there security configurations and returns them. The endpoints of a
+// load balanced endpoint configuration share the requests equally and the failover endpoints of a failover endpoint
+// configuration are added as the failovers of the production and the sandbox endpoints.
 // TODO: Currently the APK-Conf does not support giving multiple certs for a particular endpoint.
 // After fixing this, the following logic should be changed to map multiple cert configs
-func getEndpointConfigs(sandboxURL string, prodURL string, endCertAvailable bool, endpointCertList EndpointCertDescriptor, endpointSecurityData EndpointSecurityConfig, apiUniqueID string, prodAIRatelimit *AIRatelimit, sandAIRatelimit *AIRatelimit) EndpointConfigurations {
-	var sandboxEndpointConf, prodEndpointConf EndpointConfiguration
-	var sandBoxEndpointEnabled = false
-	var prodEndpointEnabled = false
-	if sandboxURL != "" {
-		sandBoxEndpointEnabled = true
+func getEndpointConfigs(endpointConfig EndpointConfig, endCertAvailable bool, endpointCertList EndpointCertDescriptor, apiUniqueID string, prodAIRatelimit *AIRatelimit, sandAIRatelimit *AIRatelimit) EndpointConfigurations {
+	var prodFailovers, sandboxFailovers []EndpointDetails
+	if strings.EqualFold(endpointConfig.EndpointType, failoverEndpointType) {
+		prodFailovers = endpointConfig.ProductionFailovers
+		sandboxFailovers = endpointConfig.SandboxFailovers
 	}
-	if prodURL != "" {
-		prodEndpointEnabled = true
+	return EndpointConfigurations{
+		Production: getEnvironmentEndpointConfigs("production", endpointConfig.ProductionEndpoints, prodFailovers,
+			endCertAvailable, endpointCertList, endpointConfig.EndpointSecurity.Production, apiUniqueID, prodAIRatelimit),
+		Sandbox: getEnvironmentEndpointConfigs("sandbox", endpointConfig.SandboxEndpoints, sandboxFailovers,
+			endCertAvailable, endpointCertList, endpointConfig.EndpointSecurity.Sandbox, apiUniqueID, sandAIRatelimit),
 	}
-	if prodAIRatelimit != nil {
-		prodEndpointConf.AIRatelimit = *prodAIRatelimit
+}
+
+// getEnvironmentEndpointConfigs maps the endpoints of an environment along with their failovers, certificates,
+// security configurations, AI rate limits and timeouts
+func getEnvironmentEndpointConfigs(environment string, endpoints EndpointDetails, failovers []EndpointDetails,
+	endCertAvailable bool, endpointCertList EndpointCertDescriptor, securityData SecurityObj, apiUniqueID string,
+	aiRatelimit *AIRatelimit) EndpointConfigurationList {
+	var endpointConfigs EndpointConfigurationList
+	for _, endpoint := range endpoints.getEndpoints() {
+		if endpoint.URL == "" {
+			continue
+		}
+		endpointConfigs = append(endpointConfigs, getEndpointConfiguration(environment, endpoint, endCertAvailable,
+			endpointCertList, securityData, apiUniqueID, aiRatelimit))
+	}
+	if len(endpointConfigs) > 1 {
+		for _, endpointConf := range endpointConfigs {
+			endpointConf.Weight = loadBalancedEndpointWeight
+		}
+	} else if len(endpointConfigs) == 1 {
+		for _, failover := range failovers {
+			if failover.URL == "" {
+				continue
+			}
+			endpointConfigs[0].Failovers = append(endpointConfigs[0].Failovers, getEndpointConfiguration(environment,
+				failover, endCertAvailable, endpointCertList, securityData, apiUniqueID, aiRatelimit))
+		}
 	}
-	if sandAIRatelimit != nil {
-		sandboxEndpointConf.AIRatelimit = *sandAIRatelimit
+	return endpointConfigs
+}
+
+// getEndpointConfiguration maps an endpoint along with its certificate, security configurations, AI rate limit and
+// timeouts
+func getEndpointConfiguration(environment string, endpoint EndpointDetails, endCertAvailable bool,
+	endpointCertList EndpointCertDescriptor, securityData SecurityObj, apiUniqueID string,
+	aiRatelimit *AIRatelimit) *EndpointConfiguration {
+	endpointConf := &EndpointConfiguration{
+		Endpoint:   endpoint.URL,
+		Resiliency: getEndpointResiliency(endpoint.Config),
+	}
+	if aiRatelimit != nil {
+		endpointConf.AIRatelimit = *aiRatelimit
 	}
-	sandboxEndpointConf.Endpoint = sandboxURL
-	prodEndpointConf.Endpoint = prodURL
 	if endCertAvailable {
 		for _, endCert := range endpointCertList.EndpointCertData {
-			if endCert.Endpoint == sandboxURL {
-				sandboxEndpointConf.EndCertificate = EndpointCertificate{
-					Name: endCert.Alias,
-					Key:  endCert.Certificate,
-				}
-			}
-			if endCert.Endpoint == prodURL {
-				prodEndpointConf.EndCertificate = EndpointCertificate{
+			if endCert.Endpoint == endpoint.URL {
+				endpointConf.EndCertificate = EndpointCertificate{
 					Name: endCert.Alias,
 					Key:  endCert.Certificate,
 				}
 			}
 		}
 	}
-
-	if endpointSecurityData.Sandbox.Enabled {
-		sandboxEndpointConf.EndSecurity.Enabled = true
-		if endpointSecurityData.Sandbox.Type == "apikey" {
-			sandboxEndpointConf.EndSecurity.SecurityType = SecretInfo{
-				SecretName:     strings.Join([]string{apiUniqueID, "sandbox", "secret"}, "-"),
+	if securityData.Enabled {
+		endpointConf.EndSecurity.Enabled = true
+		if securityData.Type == "apikey" {
+			endpointConf.EndSecurity.SecurityType = SecretInfo{
+				SecretName:     strings.Join([]string{apiUniqueID, environment, "secret"}, "-"),
 				In:             "Header",
-				APIKeyNameKey:  endpointSecurityData.Sandbox.APIKeyIdentifier,
+				APIKeyNameKey:  securityData.APIKeyIdentifier,
 				APIKeyValueKey: "apiKey",
 			}
 		} else {
-			sandboxEndpointConf.EndSecurity.SecurityType = SecretInfo{
-				SecretName:  strings.Join([]string{apiUniqueID, "sandbox", "secret"}, "-"),
+			endpointConf.EndSecurity.SecurityType = SecretInfo{
+				SecretName:  strings.Join([]string{apiUniqueID, environment, "secret"}, "-"),
 				UsernameKey: "username",
 				PasswordKey: "password",
 			}
 		}
 	}
+	return endpointConf
+}
 
-	if endpointSecurityData.Production.Enabled {
-		prodEndpointConf.EndSecurity.Enabled = true
-		if endpointSecurityData.Production.Type == "apikey" {
-			prodEndpointConf.EndSecurity.SecurityType = SecretInfo{
-				SecretName:     strings.Join([]string{apiUniqueID, "production", "secret"}, "-"),
-				In:             "Header",
-				APIKeyNameKey:  endpointSecurityData.Production.APIKeyIdentifier,
-				APIKeyValueKey: "apiKey",
-			}
-		} else {
-			prodEndpointConf.EndSecurity.SecurityType = SecretInfo{
-				SecretName:  strings.Join([]string{apiUniqueID, "production", "secret"}, "-"),
-				UsernameKey: "username",
-				PasswordKey: "password",
-			}
-		}
+// getEndpointResiliency maps the advanced configurations of an API Manager endpoint to the resiliency configurations
+// of the APK endpoint. Only the connection timeout has an APK counterpart, the suspension of the endpoint is reported
+// by the validation of the API.
+func getEndpointResiliency(endpointConfig map[string]interface{}) *Resiliency {
+	timeoutMillis, found, err := getEndpointConfigNumber(endpointConfig, endpointTimeoutDuration)
+	if err != nil || !found || timeoutMillis <= 0 {
+		return nil
+	}
+	// The timeout is rounded up to seconds so that the requests are not timed out earlier than in the API Manager
+	timeoutSeconds := (timeoutMillis + 999) / 1000
+	return &Resiliency{
+		Timeout: &EndpointTimeout{
+			UpstreamResponseTimeout: timeoutSeconds,
+		},
 	}
+}
 
-	epconfigs := EndpointConfigurations{}
-	if sandBoxEndpointEnabled && prodEndpointEnabled {
-		epconfigs = EndpointConfigurations{
-			Sandbox:    &sandboxEndpointConf,
-			Production: &prodEndpointConf,
-		}
-	} else if sandBoxEndpointEnabled {
-		epconfigs = EndpointConfigurations{
-			Sandbox: &sandboxEndpointConf,
+// getEndpointConfigNumber returns the numeric value of an advanced endpoint configuration, which the API Manager
+// stores either as a number or as a string. An empty value is reported as not found.
+func getEndpointConfigNumber(endpointConfig map[string]interface{}, key string) (int, bool, error) {
+	switch value := endpointConfig[key].(type) {
+	case float64:
+		return int(value), true, nil
+	case string:
+		if strings.TrimSpace(value) == "" {
+			return 0, false, nil
 		}
-	} else if prodEndpointEnabled {
-		epconfigs = EndpointConfigurations{
-			Production: &prodEndpointConf,
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, true, fmt.Errorf("%s %q is not a number", key, value)
 		}
+		return int(number), true, nil
+	case nil:
+		return 0, false, nil
+	default:
+		return 0, true, fmt.Errorf("%s %v is not a number", key, value)
 	}
-	return epconfigs
 }
 
 // GenerateCRs takes the .apk-conf, api definition, vHost and the organization for a particular API and then generate and returns
 // the relavant CRD set as a zip
 func GenerateCRs(apkConf string, apiDefinition string, certContainer CertContainer, k8ResourceGenEndpoint string, organizationID string) (*K8sArtifacts, error) {
-	k8sArtifact := K8sArtifacts{HTTPRoutes: make(map[string]*gwapiv1.HTTPRoute), GQLRoutes: make(map[string]*dpv1alpha2.GQLRoute), Backends: make(map[string]*dpv1alpha2.Backend), Scopes: make(map[string]*dpv1alpha1.Scope), Authentication: make(map[string]*dpv1alpha2.Authentication), APIPolicies: make(map[string]*dpv1alpha3.APIPolicy), InterceptorServices: make(map[string]*dpv1alpha1.InterceptorService), ConfigMaps: make(map[string]*corev1.ConfigMap), Secrets: make(map[string]*corev1.Secret), RateLimitPolicies: make(map[string]*dpv1alpha1.RateLimitPolicy), AIRateLimitPolicies: make(map[string]*dpv1alpha3.AIRateLimitPolicy)}
+	k8sArtifact := newK8sArtifacts()
 	if apkConf == "" {
 		logger.LoggerTransformer.Error("Empty apk-conf parameter provided. Unable to generate CRDs.")
 		return nil, errors.New("Error: APK-Conf can't be empty")
@@ -1084,6 +1119,26 @@ func addOrganization(k8sArtifact *K8sArtifacts, organization string) {
 	for _, scope := range k8sArtifact.Scopes {
 		scope.ObjectMeta.Labels[k8sOrganizationField] = organizationHash
 	}
+	for _, interceptorService := range k8sArtifact.InterceptorServices {
+		addOrganizationLabel(&interceptorService.ObjectMeta, organizationHash)
+	}
+	for _, rateLimitPolicy := range k8sArtifact.RateLimitPolicies {
+		addOrganizationLabel(&rateLimitPolicy.ObjectMeta, organizationHash)
+	}
+	for _, aiRateLimitPolicy := range k8sArtifact.AIRateLimitPolicies {
+		addOrganizationLabel(&aiRateLimitPolicy.ObjectMeta, organizationHash)
+	}
+	if k8sArtifact.BackendJWT != nil {
+		addOrganizationLabel(&k8sArtifact.BackendJWT.ObjectMeta, organizationHash)
+	}
+}
+
+// addOrganizationLabel adds the organization label to a CR whose labels may not be initialized
+func addOrganizationLabel(objectMeta *metav1.ObjectMeta, organizationHash string) {
+	if objectMeta.Labels == nil {
+		objectMeta.Labels = make(map[string]string)
+	}
+	objectMeta.Labels[k8sOrganizationField] = organizationHash
 }
 
 // addRevisionAndAPIUUID will add the API ID and the revision field attributes to the API CR
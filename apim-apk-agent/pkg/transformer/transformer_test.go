@@ -158,6 +158,75 @@ func TestAPKConfGeneration(t *testing.T) {
 	}
 }
 
+func TestAPKConfGatewayFeatures(t *testing.T) {
+	apiJSON := newTestAPIJson(t, func(api *APIMApi) {
+		api.Operations[0].OperationPolicies = &APIMOperationPolicies{}
+		api.CORSConfiguration = CORSConfiguration{
+			CORSConfigurationEnabled:      true,
+			AccessControlAllowOrigins:     []string{"https://wso2.com"},
+			AccessControlAllowCredentials: true,
+			AccessControlAllowHeaders:     []string{"authorization"},
+			AccessControlAllowMethods:     []string{"GET"},
+		}
+		api.EndpointConfig.ProductionEndpoints.Config = map[string]interface{}{endpointTimeoutDuration: "30500"}
+		api.EndpointConfig.SandboxEndpoints = EndpointDetails{
+			URL:    "https://sandbox:8443/api",
+			Config: map[string]interface{}{endpointTimeoutDuration: 60000},
+		}
+	})
+	apkConf, _, _, _, _, api, _, _, err := GenerateAPKConf(apiJSON, CertificateArtifact{}, "default")
+	assert.NoError(t, err)
+	if assert.NotNil(t, api.CorsConfig) {
+		assert.Equal(t, []string{"https://wso2.com"}, api.CorsConfig.AccessControlAllowOrigins)
+		assert.True(t, api.CorsConfig.AccessControlAllowCredentials)
+	}
+	assert.Equal(t, 31, api.EndpointConfigurations.Production[0].Resiliency.Timeout.UpstreamResponseTimeout)
+	assert.Equal(t, 60, api.EndpointConfigurations.Sandbox[0].Resiliency.Timeout.UpstreamResponseTimeout)
+	assert.Contains(t, apkConf, "upstreamResponseTimeout: 31")
+	assert.Contains(t, apkConf, "corsConfigurationEnabled: true")
+
+	// The endpoints without a valid connection timeout keep the default timeouts of APK
+	for _, endpointConfig := range []map[string]interface{}{nil, {endpointTimeoutDuration: ""},
+		{endpointTimeoutDuration: "30s"}, {endpointTimeoutDuration: "-1"}} {
+		assert.Nil(t, getEndpointResiliency(endpointConfig))
+	}
+}
+
+func TestAPKConfLoadBalancedEndpoints(t *testing.T) {
+	apiJSON := newTestAPIJson(t, func(api *APIMApi) {
+		api.Operations[0].OperationPolicies = &APIMOperationPolicies{}
+		api.EndpointConfig = EndpointConfig{
+			EndpointType: loadBalanceEndpointType,
+			ProductionEndpoints: EndpointDetails{Endpoints: []EndpointDetails{
+				{URL: "https://backend-1:8443/api"},
+				{URL: "https://backend-2:8443/api"},
+			}},
+			SandboxEndpoints: EndpointDetails{URL: "https://sandbox:8443/api"},
+		}
+	})
+	apkConf, _, _, _, _, api, _, _, err := GenerateAPKConf(apiJSON, CertificateArtifact{}, "default")
+	assert.NoError(t, err)
+	if assert.Len(t, api.EndpointConfigurations.Production, 2) {
+		assert.Equal(t, "https://backend-2:8443/api", api.EndpointConfigurations.Production[1].Endpoint)
+		assert.Equal(t, loadBalancedEndpointWeight, api.EndpointConfigurations.Production[1].Weight)
+	}
+	assert.Len(t, api.EndpointConfigurations.Sandbox, 1)
+	assert.Zero(t, api.EndpointConfigurations.Sandbox[0].Weight)
+	assert.Contains(t, apkConf, "production:\n  - endpoint: https://backend-1:8443/api\n    weight: 1")
+	assert.Contains(t, apkConf, "  sandbox:\n    endpoint: https://sandbox:8443/api")
+
+	// The load balanced endpoints keep their array form in the api.json
+	var decoded EndpointDetails
+	assert.NoError(t, json.Unmarshal([]byte(`[{"url":"https://backend-1:8443/api"},{"url":"https://backend-2:8443/api"}]`),
+		&decoded))
+	assert.Equal(t, "https://backend-1:8443/api", decoded.URL)
+	assert.Len(t, decoded.getEndpoints(), 2)
+	encoded, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"url":"https://backend-1:8443/api","config":null},{"url":"https://backend-2:8443/api","config":null}]`,
+		string(encoded))
+}
+
 func TestAddRevisionAndAPIUUID(t *testing.T) {
 	for _, k8Json := range sampleK8Artifacts {
 		var k8sArtifact K8sArtifacts
@@ -217,6 +286,12 @@ func TestAddOrganization(t *testing.T) {
 		for _, scope := range k8sArtifact.Scopes {
 			assert.Equal(t, organizationHash, scope.ObjectMeta.Labels[k8sOrganizationField])
 		}
+		for _, rateLimitPolicy := range k8sArtifact.RateLimitPolicies {
+			assert.Equal(t, organizationHash, rateLimitPolicy.ObjectMeta.Labels[k8sOrganizationField])
+		}
+		for _, interceptorService := range k8sArtifact.InterceptorServices {
+			assert.Equal(t, organizationHash, interceptorService.ObjectMeta.Labels[k8sOrganizationField])
+		}
 	}
 }
 
@@ -0,0 +1,520 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/internal/constants"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/managementserver"
+)
+
+// ValidationIssue describes a single finding of the validation of an API project
+type ValidationIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationResult holds the findings of the validation of an API project. Errors block the deployment of the API
+// while warnings point out the configurations which are not applied in APK.
+type ValidationResult struct {
+	APIUUID    string            `json:"apiUUID"`
+	RevisionID uint32            `json:"revisionId"`
+	Errors     []ValidationIssue `json:"errors"`
+	Warnings   []ValidationIssue `json:"warnings"`
+}
+
+// HasErrors returns whether the validation found any blocking errors
+func (result *ValidationResult) HasErrors() bool {
+	return len(result.Errors) > 0
+}
+
+// Err returns the blocking errors of the validation as a single error or nil when there are none
+func (result *ValidationResult) Err() error {
+	if !result.HasErrors() {
+		return nil
+	}
+	errs := make([]error, 0, len(result.Errors))
+	for _, issue := range result.Errors {
+		errs = append(errs, fmt.Errorf("%s: %s", issue.Field, issue.Message))
+	}
+	return errors.Join(errs...)
+}
+
+func (result *ValidationResult) addError(field string, format string, args ...interface{}) {
+	result.Errors = append(result.Errors, ValidationIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (result *ValidationResult) addWarning(field string, format string, args ...interface{}) {
+	result.Warnings = append(result.Warnings, ValidationIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+var (
+	supportedSecuritySchemes = []string{oAuth2SecScheme, applicationSecurityMandatory, applicationSecurityOptional,
+		mutualSSL, mutualSSLMandatory, apiKeySecScheme}
+	supportedEndpointTypes         = []string{"", "http", "address", loadBalanceEndpointType, failoverEndpointType}
+	supportedEndpointSecurityTypes = []string{"basic", "apikey"}
+	supportedRESTVerbs             = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+	supportedGraphQLVerbs          = []string{"QUERY", "MUTATION", "SUBSCRIPTION"}
+	supportedRedirectStatusCodes   = []int{301, 302}
+
+	// Mandatory string parameters of the operation policies mapped to APK by the policy name
+	requestPolicyParameters = map[string][]string{
+		constants.InterceptorService: {interceptorServiceURL, includes},
+		constants.BackendJWT:         {encoding, header, signingAlgorithm, tokenTTL},
+		constants.AddHeader:          {headerName, headerValue},
+		constants.RemoveHeader:       {headerName},
+		constants.RedirectRequest:    {url},
+		constants.MirrorRequest:      {url},
+	}
+	responsePolicyParameters = map[string][]string{
+		constants.InterceptorService: {interceptorServiceURL, includes},
+		constants.AddHeader:          {headerName, headerValue},
+		constants.RemoveHeader:       {headerName},
+	}
+)
+
+// ValidateAPIArtifact checks a decoded API project against the configurations supported by APK before the apk-conf
+// and the CRs are generated for it.
+func ValidateAPIArtifact(artifact *APIArtifact, organizationID string) *ValidationResult {
+	result := &ValidationResult{Errors: []ValidationIssue{}, Warnings: []ValidationIssue{}}
+	if artifact == nil || artifact.APIJson == "" {
+		result.addError("api.json", "API definition is not found in the API project")
+		return result
+	}
+	var apiYaml APIYaml
+	if err := json.Unmarshal([]byte(artifact.APIJson), &apiYaml); err != nil {
+		result.addError("api.json", "API definition cannot be decoded: %v", err)
+		return result
+	}
+	api := apiYaml.Data
+	result.APIUUID = api.RevisionedAPIID
+	result.RevisionID = api.RevisionID
+
+	validateAPIDetails(api, result)
+	validateEndpoints(api.EndpointConfig, result)
+	validateGatewayFeatures(api, result)
+	validateSecuritySchemes(api.SecuritySchemes, artifact.CertMeta, result)
+	validateOperations(api, organizationID, result)
+	validatePolicies("apiPolicies", api.APIPolicies, result)
+	validateClientCertificates(artifact, result)
+	validateEndpointCertificates(artifact, api.EndpointConfig, result)
+	return result
+}
+
+// validateAPIDetails checks the basic information and the type of the API
+func validateAPIDetails(api APIMApi, result *ValidationResult) {
+	if api.Name == "" {
+		result.addError("name", "API name is empty")
+	}
+	if api.Version == "" {
+		result.addError("version", "API version is empty")
+	}
+	if api.Context == "" {
+		result.addError("context", "API context is empty")
+	}
+	if getAPIType(api.Type) == "" {
+		result.addError("type", "API type %q is not supported", api.Type)
+	}
+	if api.SubtypeConfiguration.Subtype == "AIAPI" && api.SubtypeConfiguration.Configuration != "" {
+		var aiConfig Configuration
+		if err := json.Unmarshal([]byte(api.SubtypeConfiguration.Configuration), &aiConfig); err != nil {
+			result.addError("subtypeConfiguration", "AI API configuration cannot be decoded: %v", err)
+		}
+	}
+	if api.CORSConfiguration.CORSConfigurationEnabled && len(api.CORSConfiguration.AccessControlAllowOrigins) == 0 {
+		result.addWarning("corsConfiguration", "CORS is enabled without any allowed origins")
+	}
+}
+
+// validateEndpoints checks the endpoint URLs and the endpoint security of the API
+func validateEndpoints(endpointConfig EndpointConfig, result *ValidationResult) {
+	endpointType := strings.ToLower(endpointConfig.EndpointType)
+	if !StringExists(endpointType, supportedEndpointTypes) {
+		result.addError("endpointConfig.endpoint_type", "endpoint type %q is not supported", endpointConfig.EndpointType)
+	}
+	prodURLs := validateEnvironmentEndpoints("endpointConfig.production_endpoints", endpointConfig.ProductionEndpoints,
+		result)
+	sandboxURLs := validateEnvironmentEndpoints("endpointConfig.sandbox_endpoints", endpointConfig.SandboxEndpoints,
+		result)
+	if len(prodURLs) == 0 && len(sandboxURLs) == 0 {
+		result.addError("endpointConfig", "neither a production nor a sandbox endpoint is configured")
+	}
+	if endpointType == loadBalanceEndpointType && endpointConfig.SessionManagement != "" &&
+		!strings.EqualFold(endpointConfig.SessionManagement, noSessionManagement) {
+		result.addWarning("endpointConfig.sessionManagement", "session management %q of the load balanced endpoints "+
+			"is not supported in APK and the requests of a session can be routed to any of the endpoints",
+			endpointConfig.SessionManagement)
+	}
+	if endpointType == failoverEndpointType {
+		validateFailoverEndpoints("endpointConfig.production_failovers", endpointConfig.ProductionFailovers,
+			prodURLs, result)
+		validateFailoverEndpoints("endpointConfig.sandbox_failovers", endpointConfig.SandboxFailovers, sandboxURLs,
+			result)
+	}
+	var prodURL, sandboxURL string
+	if len(prodURLs) > 0 {
+		prodURL = prodURLs[0]
+	}
+	if len(sandboxURLs) > 0 {
+		sandboxURL = sandboxURLs[0]
+	}
+	validateEndpointSecurity("endpointConfig.endpoint_security.production", endpointConfig.EndpointSecurity.Production,
+		prodURL, result)
+	validateEndpointSecurity("endpointConfig.endpoint_security.sandbox", endpointConfig.EndpointSecurity.Sandbox,
+		sandboxURL, result)
+}
+
+// validateEnvironmentEndpoints checks the URLs and the advanced configurations of the endpoints of an environment and
+// returns their URLs
+func validateEnvironmentEndpoints(field string, endpoints EndpointDetails, result *ValidationResult) []string {
+	var urls []string
+	for i, endpoint := range endpoints.getEndpoints() {
+		endpointField := field
+		if len(endpoints.Endpoints) > 0 {
+			endpointField = fmt.Sprintf("%s[%d]", field, i)
+		}
+		if endpoint.URL == "" {
+			if len(endpoints.Endpoints) > 0 {
+				result.addError(endpointField+".url", "URL of the load balanced endpoint is empty")
+			}
+			continue
+		}
+		validateURL(endpointField+".url", endpoint.URL, result)
+		validateAdvancedEndpointConfig(endpointField+".config", endpoint.Config, result)
+		urls = append(urls, endpoint.URL)
+	}
+	return urls
+}
+
+// validateFailoverEndpoints checks the URLs of the failover endpoints of an environment and warns that they are
+// applied differently in APK
+func validateFailoverEndpoints(field string, failovers []EndpointDetails, urls []string, result *ValidationResult) {
+	if len(failovers) == 0 {
+		return
+	}
+	if len(urls) == 0 {
+		result.addWarning(field, "failover endpoints are not applied as the primary endpoint is not configured")
+		return
+	}
+	for i, failover := range failovers {
+		validateURL(fmt.Sprintf("%s[%d].url", field, i), failover.URL, result)
+	}
+	result.addWarning(field, "failover endpoints are only applied when the CRs are generated by the agent. They are "+
+		"generated as backends with a retry policy and health checks, and they share the requests with the primary "+
+		"endpoint while they are healthy as APK cannot prioritize the endpoints")
+}
+
+// validateAdvancedEndpointConfig warns about the advanced endpoint configurations which cannot be expressed in APK.
+// The connection timeout is mapped to the timeout of the endpoint while APK has no counterpart for the suspension of
+// an endpoint and the timeouts tolerated before it.
+func validateAdvancedEndpointConfig(field string, endpointConfig map[string]interface{}, result *ValidationResult) {
+	if len(endpointConfig) == 0 {
+		return
+	}
+	if _, _, err := getEndpointConfigNumber(endpointConfig, endpointTimeoutDuration); err != nil {
+		result.addWarning(field+"."+endpointTimeoutDuration, "connection timeout is not applied as %v", err)
+	}
+	if action, _ := endpointConfig[endpointTimeoutAction].(string); strings.EqualFold(action, endpointTimeoutActionDiscard) {
+		result.addWarning(field+"."+endpointTimeoutAction,
+			"timed out requests are responded with a fault as discarding the response is not supported in APK")
+	}
+	for _, key := range []string{endpointRetriesBeforeSuspension, endpointRetryDelay, endpointRetryErrorCodes} {
+		if isEndpointConfigSet(endpointConfig, key) {
+			result.addWarning(field+"."+key, "timeouts tolerated before suspending the endpoint are not supported "+
+				"in APK and the setting is ignored")
+		}
+	}
+	for _, key := range []string{endpointSuspendErrorCodes, endpointSuspendDuration, endpointSuspendMaxDuration,
+		endpointSuspendProgressionFactor} {
+		if isEndpointConfigSet(endpointConfig, key) {
+			result.addWarning(field+"."+key, "suspending the endpoint on errors is not supported in APK and the "+
+				"setting is ignored")
+		}
+	}
+}
+
+// isEndpointConfigSet returns whether the advanced endpoint configuration has a value other than the defaults of
+// the API Manager, which are an empty value, an empty list or a non-positive number
+func isEndpointConfigSet(endpointConfig map[string]interface{}, key string) bool {
+	if values, isList := endpointConfig[key].([]interface{}); isList {
+		return len(values) > 0
+	}
+	number, found, err := getEndpointConfigNumber(endpointConfig, key)
+	return err != nil || (found && number > 0)
+}
+
+// validateGatewayFeatures warns about the gateway features of the API which are not supported in APK
+func validateGatewayFeatures(api APIMApi, result *ValidationResult) {
+	if api.EnableSchemaValidation {
+		result.addWarning("enableSchemaValidation", "schema validation is not supported in APK and the requests "+
+			"and the responses are not validated against the API definition")
+	}
+	if api.ResponseCachingEnabled {
+		result.addWarning("responseCachingEnabled", "response caching is not supported in APK and the responses "+
+			"are not cached for %d seconds", api.CacheTimeout)
+	}
+}
+
+func validateEndpointSecurity(field string, security SecurityObj, endpointURL string, result *ValidationResult) {
+	if !security.Enabled {
+		return
+	}
+	if endpointURL == "" {
+		result.addWarning(field, "endpoint security is enabled for an endpoint which is not configured")
+	}
+	securityType := strings.ToLower(security.Type)
+	if !StringExists(securityType, supportedEndpointSecurityTypes) {
+		result.addError(field+".type", "endpoint security type %q is not supported", security.Type)
+		return
+	}
+	if securityType == "apikey" && security.APIKeyIdentifier == "" {
+		result.addError(field+".apiKeyIdentifier", "API key identifier of the endpoint security is empty")
+	}
+}
+
+// validateURL checks whether the value is an absolute HTTP or HTTPS URL
+func validateURL(field string, value string, result *ValidationResult) {
+	parsedURL, err := neturl.Parse(value)
+	if err != nil {
+		result.addError(field, "URL %q is invalid: %v", value, err)
+		return
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != https {
+		result.addError(field, "URL %q does not use the http or https scheme", value)
+		return
+	}
+	if parsedURL.Hostname() == "" {
+		result.addError(field, "URL %q does not have a host", value)
+	}
+}
+
+// validateSecuritySchemes checks whether the security schemes of the API can be mapped to the APK authentications
+func validateSecuritySchemes(securitySchemes []string, certMeta CertMetadata, result *ValidationResult) {
+	for _, securityScheme := range securitySchemes {
+		if !StringExists(securityScheme, supportedSecuritySchemes) {
+			result.addError("securityScheme", "security scheme %q is not supported", securityScheme)
+		}
+	}
+	if StringExists(mutualSSL, securitySchemes) && !certMeta.CertAvailable {
+		result.addError("securityScheme", "mutual SSL is enabled without any client certificates")
+	}
+}
+
+// validateOperations checks the verbs, the targets, the rate limit policies and the operation policies of the
+// operations of the API
+func validateOperations(api APIMApi, organizationID string, result *ValidationResult) {
+	supportedVerbs := supportedRESTVerbs
+	if getAPIType(api.Type) == "GRAPHQL" {
+		supportedVerbs = supportedGraphQLVerbs
+	}
+	if len(api.Operations) == 0 {
+		result.addError("operations", "API does not have any operations")
+	}
+	validateRateLimitPolicy("apiThrottlingPolicy", api.APIThrottlingPolicy, organizationID, result)
+	for i, operation := range api.Operations {
+		field := fmt.Sprintf("operations[%d]", i)
+		if !StringExists(strings.ToUpper(operation.Verb), supportedVerbs) {
+			result.addError(field+".verb", "verb %q is not supported", operation.Verb)
+		}
+		if operation.Target == "" {
+			result.addError(field+".target", "target is empty")
+		} else if getAPIType(api.Type) == restType && !strings.HasPrefix(operation.Target, "/") {
+			result.addError(field+".target", "target %q does not start with /", operation.Target)
+		}
+		if api.APIThrottlingPolicy == "" {
+			validateRateLimitPolicy(field+".throttlingPolicy", operation.ThrottlingPolicy, organizationID, result)
+		}
+		if operation.OperationPolicies != nil {
+			validatePolicies(field+".operationPolicies", *operation.OperationPolicies, result)
+		}
+	}
+}
+
+// validateRateLimitPolicy warns when the rate limit policy is not known to the agent as it is not applied then
+func validateRateLimitPolicy(field string, policyName string, organizationID string, result *ValidationResult) {
+	if policyName == "" || policyName == "Unlimited" {
+		return
+	}
+	if managementserver.GetRateLimitPolicy(policyName, organizationID).Name == "" {
+		result.addWarning(field, "rate limit policy %q is not found and is not applied", policyName)
+	}
+}
+
+// validatePolicies checks whether the operation policies can be mapped to the APK policies
+func validatePolicies(field string, policies APIMOperationPolicies, result *ValidationResult) {
+	for i, policy := range policies.Request {
+		validatePolicy(fmt.Sprintf("%s.request[%d]", field, i), policy, requestPolicyParameters, result)
+	}
+	for i, policy := range policies.Response {
+		validatePolicy(fmt.Sprintf("%s.response[%d]", field, i), policy, responsePolicyParameters, result)
+	}
+	for i, policy := range policies.Fault {
+		result.addWarning(fmt.Sprintf("%s.fault[%d]", field, i), "fault policy %q is not supported and is not applied",
+			policy.PolicyName)
+	}
+}
+
+func validatePolicy(field string, policy APIMOperationPolicy, supportedPolicies map[string][]string,
+	result *ValidationResult) {
+	parameters, supported := supportedPolicies[policy.PolicyName]
+	if !supported {
+		result.addWarning(field, "policy %q is not supported and is not applied", policy.PolicyName)
+		return
+	}
+	missingParameter := false
+	for _, parameter := range parameters {
+		if _, ok := policy.Parameters[parameter].(string); !ok {
+			result.addError(field+".parameters."+parameter, "parameter of the policy %q is missing or is not a string",
+				policy.PolicyName)
+			missingParameter = true
+		}
+	}
+	if missingParameter {
+		return
+	}
+	switch policy.PolicyName {
+	case constants.InterceptorService:
+		validateURL(field+".parameters."+interceptorServiceURL, policy.Parameters[interceptorServiceURL].(string), result)
+	case constants.RedirectRequest, constants.MirrorRequest:
+		validateURL(field+".parameters."+url, policy.Parameters[url].(string), result)
+	case constants.BackendJWT:
+		if _, err := strconv.Atoi(policy.Parameters[tokenTTL].(string)); err != nil {
+			result.addError(field+".parameters."+tokenTTL, "token TTL %q is not a number",
+				policy.Parameters[tokenTTL])
+		}
+	}
+	if policy.PolicyName == constants.RedirectRequest {
+		validateRedirectStatusCode(field+".parameters."+statusCode, policy.Parameters[statusCode], result)
+	}
+}
+
+func validateRedirectStatusCode(field string, value interface{}, result *ValidationResult) {
+	var code int
+	switch v := value.(type) {
+	case nil:
+		return
+	case float64:
+		code = int(v)
+	case int:
+		code = v
+	case string:
+		parsedCode, err := strconv.Atoi(v)
+		if err != nil {
+			result.addError(field, "status code %q is not a number", v)
+			return
+		}
+		code = parsedCode
+	default:
+		result.addError(field, "status code %v is not a number", v)
+		return
+	}
+	found := false
+	for _, supportedCode := range supportedRedirectStatusCodes {
+		if code == supportedCode {
+			found = true
+			break
+		}
+	}
+	if !found {
+		result.addError(field, "status code %d is not supported for redirects", code)
+	}
+}
+
+// validateClientCertificates checks that the client certificates used for mutual SSL are present and well formed
+func validateClientCertificates(artifact *APIArtifact, result *ValidationResult) {
+	if artifact.CertArtifact.ClientCerts == "" {
+		return
+	}
+	var certList CertDescriptor
+	if err := json.Unmarshal([]byte(artifact.CertArtifact.ClientCerts), &certList); err != nil {
+		result.addError("client_certificates.json", "client certificates cannot be decoded: %v", err)
+		return
+	}
+	for _, cert := range certList.CertData {
+		validateCertificateFile("Client-certificates/"+cert.Certificate, cert.Certificate,
+			artifact.CertMeta.ClientCertFiles, result)
+	}
+}
+
+// validateEndpointCertificates checks that the endpoint certificates are present and well formed and warns about
+// the ones which do not belong to an endpoint of the API
+func validateEndpointCertificates(artifact *APIArtifact, endpointConfig EndpointConfig, result *ValidationResult) {
+	if artifact.CertArtifact.EndpointCerts == "" {
+		return
+	}
+	var certList EndpointCertDescriptor
+	if err := json.Unmarshal([]byte(artifact.CertArtifact.EndpointCerts), &certList); err != nil {
+		result.addError("endpoint_certificates.json", "endpoint certificates cannot be decoded: %v", err)
+		return
+	}
+	var endpointURLs []string
+	for _, endpoint := range append(endpointConfig.ProductionEndpoints.getEndpoints(),
+		endpointConfig.SandboxEndpoints.getEndpoints()...) {
+		endpointURLs = append(endpointURLs, endpoint.URL)
+	}
+	for _, cert := range certList.EndpointCertData {
+		field := "Endpoint-certificates/" + cert.Certificate
+		validateCertificateFile(field, cert.Certificate, artifact.EndpointCertMeta.EndpointCertFiles, result)
+		if !StringExists(cert.Endpoint, endpointURLs) {
+			result.addWarning(field, "certificate of the endpoint %q is not applied as it is not an endpoint of the API",
+				cert.Endpoint)
+		}
+	}
+}
+
+func validateCertificateFile(field string, fileName string, certFiles map[string]string, result *ValidationResult) {
+	content, found := certFiles[fileName]
+	if !found {
+		result.addError(field, "certificate file is not found in the API project")
+		return
+	}
+	certificate, err := parseCertificate([]byte(content))
+	if err != nil {
+		result.addError(field, "certificate is malformed: %v", err)
+		return
+	}
+	if time.Now().After(certificate.NotAfter) {
+		result.addWarning(field, "certificate %q expired at %v", certificate.Subject.CommonName, certificate.NotAfter)
+	}
+}
+
+// parseCertificate parses a PEM or DER encoded certificate. The client certificates of the API projects carry the
+// base64 encoded PEM content within a PEM block, hence the decoded content is parsed again when it is not DER.
+func parseCertificate(content []byte) (*x509.Certificate, error) {
+	for depth := 0; depth < 3; depth++ {
+		if block, _ := pem.Decode(content); block != nil {
+			content = block.Bytes
+		} else if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content))); err == nil {
+			content = decoded
+		}
+		if certificate, err := x509.ParseCertificate(content); err == nil {
+			return certificate, nil
+		}
+	}
+	return nil, errors.New("content is not a PEM or DER encoded certificate")
+}
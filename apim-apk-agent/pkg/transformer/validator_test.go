@@ -0,0 +1,207 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestAPIJson returns the api.json of a valid REST API after applying the given changes to it
+func newTestAPIJson(t *testing.T, updateAPI func(*APIMApi)) string {
+	api := APIMApi{
+		Name:            "TestAPI",
+		Version:         "1.0.0",
+		Context:         "/test/1.0.0",
+		Type:            "HTTP",
+		SecuritySchemes: []string{oAuth2SecScheme, applicationSecurityMandatory},
+		EndpointConfig: EndpointConfig{
+			EndpointType:        "http",
+			ProductionEndpoints: EndpointDetails{URL: "https://backend:8443/api"},
+		},
+		Operations:      []APIMOperation{{Target: "/*", Verb: "GET"}},
+		RevisionID:      1,
+		RevisionedAPIID: "test-api-uuid",
+	}
+	if updateAPI != nil {
+		updateAPI(&api)
+	}
+	apiJSON, err := json.Marshal(APIYaml{Data: api})
+	assert.NoError(t, err)
+	return string(apiJSON)
+}
+
+func issueFields(issues []ValidationIssue) []string {
+	fields := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		fields = append(fields, issue.Field)
+	}
+	return fields
+}
+
+func TestValidateTestAPIArtifacts(t *testing.T) {
+	artifacts := readTestAPIArtifacts(t)
+	assert.NotEmpty(t, artifacts)
+	for name, artifact := range artifacts {
+		result := ValidateAPIArtifact(artifact, "default")
+		assert.False(t, result.HasErrors(), "API %s: %v", name, result.Err())
+		assert.NotEmpty(t, result.APIUUID)
+	}
+
+	// The synapse policies are not supported by APK
+	apiJSON := newTestAPIJson(t, func(api *APIMApi) {
+		api.Operations[0].OperationPolicies = &APIMOperationPolicies{
+			Request: []APIMOperationPolicy{{PolicyName: "jsonToXML", PolicyVersion: v1}},
+			Fault:   []APIMOperationPolicy{{PolicyName: "addLogMessage", PolicyVersion: v1}},
+		}
+	})
+	result := ValidateAPIArtifact(&APIArtifact{APIJson: apiJSON}, "default")
+	assert.False(t, result.HasErrors())
+	assert.ElementsMatch(t, []string{"operations[0].operationPolicies.request[0]", "operations[0].operationPolicies.fault[0]"},
+		issueFields(result.Warnings))
+}
+
+func TestValidateAPIArtifactErrors(t *testing.T) {
+	apiJSON := newTestAPIJson(t, func(api *APIMApi) {
+		api.Type = "SOAP"
+		api.SecuritySchemes = []string{oAuth2SecScheme, "basic_auth", mutualSSL}
+		api.EndpointConfig.ProductionEndpoints.URL = "backend:8443"
+		api.EndpointConfig.SandboxEndpoints.URL = "ftp://backend/api"
+		api.EndpointConfig.EndpointSecurity.Production = SecurityObj{Enabled: true, Type: "DIGEST"}
+		api.Operations = []APIMOperation{{
+			Target: "/*",
+			Verb:   "GET",
+			OperationPolicies: &APIMOperationPolicies{
+				Request: []APIMOperationPolicy{
+					{PolicyName: "apkAddHeader", Parameters: map[string]interface{}{"headerName": "x-test"}},
+					{PolicyName: "apkRedirectRequest", Parameters: map[string]interface{}{"url": "https://redirect",
+						"statusCode": "307"}},
+				},
+			},
+		}}
+	})
+	artifact := &APIArtifact{
+		APIJson: apiJSON,
+		CertArtifact: CertificateArtifact{
+			EndpointCerts: `{"data":[{"alias":"backend","endpoint":"https://backend:8443/api","certificate":"backend.crt"}]}`,
+		},
+		EndpointCertMeta: EndpointCertMetadata{
+			CertAvailable:     true,
+			EndpointCertFiles: map[string]string{"backend.crt": "-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydA==\n-----END CERTIFICATE-----"},
+		},
+	}
+	result := ValidateAPIArtifact(artifact, "default")
+	assert.True(t, result.HasErrors())
+	assert.Error(t, result.Err())
+	assert.Equal(t, "test-api-uuid", result.APIUUID)
+	assert.Equal(t, uint32(1), result.RevisionID)
+	assert.ElementsMatch(t, []string{
+		"type",
+		"endpointConfig.production_endpoints.url",
+		"endpointConfig.sandbox_endpoints.url",
+		"endpointConfig.endpoint_security.production.type",
+		"securityScheme",
+		"securityScheme",
+		"operations[0].operationPolicies.request[0].parameters.headerValue",
+		"operations[0].operationPolicies.request[1].parameters.statusCode",
+		"Endpoint-certificates/backend.crt",
+	}, issueFields(result.Errors))
+
+	result = ValidateAPIArtifact(&APIArtifact{APIJson: "{"}, "default")
+	assert.Equal(t, []string{"api.json"}, issueFields(result.Errors))
+	result = ValidateAPIArtifact(nil, "default")
+	assert.True(t, result.HasErrors())
+}
+
+func TestValidateAPIArtifactWarnings(t *testing.T) {
+	apiJSON := newTestAPIJson(t, func(api *APIMApi) {
+		api.APIThrottlingPolicy = "UnknownPolicy"
+		api.APIPolicies = APIMOperationPolicies{
+			Response: []APIMOperationPolicy{{PolicyName: "apkRedirectRequest"}},
+		}
+	})
+	result := ValidateAPIArtifact(&APIArtifact{APIJson: apiJSON}, "default")
+	assert.False(t, result.HasErrors())
+	assert.Nil(t, result.Err())
+	assert.ElementsMatch(t, []string{"apiThrottlingPolicy", "apiPolicies.response[0]"}, issueFields(result.Warnings))
+}
+
+func TestValidateAPIArtifactGatewayFeatures(t *testing.T) {
+	apiJSON := newTestAPIJson(t, func(api *APIMApi) {
+		api.EnableSchemaValidation = true
+		api.ResponseCachingEnabled = true
+		api.CacheTimeout = 300
+		api.EndpointConfig.ProductionEndpoints.Config = map[string]interface{}{
+			endpointTimeoutDuration:          "30000",
+			endpointTimeoutAction:            "discard",
+			endpointRetriesBeforeSuspension:  "3",
+			endpointRetryErrorCodes:          []string{},
+			endpointSuspendErrorCodes:        []string{"101504"},
+			endpointSuspendDuration:          "",
+			endpointSuspendProgressionFactor: "-1",
+		}
+		api.EndpointConfig.SandboxEndpoints = EndpointDetails{
+			URL:    "https://sandbox:8443/api",
+			Config: map[string]interface{}{endpointTimeoutDuration: "30s"},
+		}
+	})
+	result := ValidateAPIArtifact(&APIArtifact{APIJson: apiJSON}, "default")
+	assert.False(t, result.HasErrors())
+	assert.ElementsMatch(t, []string{"enableSchemaValidation", "responseCachingEnabled",
+		"endpointConfig.production_endpoints.config.actionSelect",
+		"endpointConfig.production_endpoints.config.retryTimeOut",
+		"endpointConfig.production_endpoints.config.suspendErrorCode",
+		"endpointConfig.sandbox_endpoints.config.actionDuration"}, issueFields(result.Warnings))
+}
+
+func TestValidateAPIArtifactMultipleEndpoints(t *testing.T) {
+	apiJSON := newTestAPIJson(t, func(api *APIMApi) {
+		api.EndpointConfig = EndpointConfig{
+			EndpointType:      loadBalanceEndpointType,
+			SessionManagement: "transport",
+			ProductionEndpoints: EndpointDetails{Endpoints: []EndpointDetails{
+				{URL: "https://backend-1:8443/api"},
+				{URL: "backend-2:8443"},
+			}},
+		}
+	})
+	result := ValidateAPIArtifact(&APIArtifact{APIJson: apiJSON}, "default")
+	assert.Equal(t, []string{"endpointConfig.production_endpoints[1].url"}, issueFields(result.Errors))
+	assert.Equal(t, []string{"endpointConfig.sessionManagement"}, issueFields(result.Warnings))
+
+	apiJSON = newTestAPIJson(t, func(api *APIMApi) {
+		api.EndpointConfig = EndpointConfig{
+			EndpointType:        failoverEndpointType,
+			ProductionEndpoints: EndpointDetails{URL: "https://backend:8443/api"},
+			ProductionFailovers: []EndpointDetails{{URL: "https://backup:8443/api"}},
+			SandboxFailovers:    []EndpointDetails{{URL: "https://sandbox-backup:8443/api"}},
+		}
+	})
+	result = ValidateAPIArtifact(&APIArtifact{APIJson: apiJSON}, "default")
+	assert.False(t, result.HasErrors())
+	assert.ElementsMatch(t, []string{"endpointConfig.production_failovers", "endpointConfig.sandbox_failovers"},
+		issueFields(result.Warnings))
+
+	apiJSON = newTestAPIJson(t, func(api *APIMApi) {
+		api.EndpointConfig = EndpointConfig{EndpointType: loadBalanceEndpointType}
+	})
+	result = ValidateAPIArtifact(&APIArtifact{APIJson: apiJSON}, "default")
+	assert.Equal(t, []string{"endpointConfig"}, issueFields(result.Errors))
+}
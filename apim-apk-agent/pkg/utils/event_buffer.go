@@ -0,0 +1,129 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// EventSequenceFieldNumber is the protobuf field number the sequence number of an event is sent in. The field is
+// not part of the Event message of the clients built before the sequence numbers were introduced, hence it is
+// written as an unknown field which those clients ignore.
+const EventSequenceFieldNumber protowire.Number = 8
+
+// DefaultEventBufferSize is the number of the recent events kept to be replayed to the reconnecting clients
+const DefaultEventBufferSize = 1000
+
+// bufferedEvent is an event sent to the clients together with its sequence number
+type bufferedEvent struct {
+	sequence uint64
+	event    *subscription.Event
+}
+
+// eventBuffer is a ring buffer of the recent events
+type eventBuffer struct {
+	events []bufferedEvent
+	// next is the index the next event is written to
+	next int
+	size int
+}
+
+func newEventBuffer(capacity int) *eventBuffer {
+	if capacity <= 0 {
+		capacity = DefaultEventBufferSize
+	}
+	return &eventBuffer{events: make([]bufferedEvent, capacity)}
+}
+
+// add adds the event overwriting the oldest event when the buffer is full
+func (buffer *eventBuffer) add(sequence uint64, event *subscription.Event) {
+	buffer.events[buffer.next] = bufferedEvent{sequence: sequence, event: event}
+	buffer.next = (buffer.next + 1) % len(buffer.events)
+	if buffer.size < len(buffer.events) {
+		buffer.size++
+	}
+}
+
+// since returns the events after the given sequence up to the latest sequence. False is returned when the buffer
+// no longer has all of those events.
+func (buffer *eventBuffer) since(lastSequence uint64, latestSequence uint64) ([]*subscription.Event, bool) {
+	if lastSequence > latestSequence {
+		return nil, false
+	}
+	missed := latestSequence - lastSequence
+	if missed > uint64(buffer.size) {
+		return nil, false
+	}
+	events := make([]*subscription.Event, 0, missed)
+	for i := buffer.size - int(missed); i < buffer.size; i++ {
+		index := (buffer.next - buffer.size + i + len(buffer.events)) % len(buffer.events)
+		events = append(events, buffer.events[index].event)
+	}
+	return events, true
+}
+
+// setEventSequence writes the sequence number to the event
+func setEventSequence(event *subscription.Event, sequence uint64) {
+	message := event.ProtoReflect()
+	field := protowire.AppendTag(nil, EventSequenceFieldNumber, protowire.VarintType)
+	field = protowire.AppendVarint(field, sequence)
+	message.SetUnknown(append(withoutEventSequence(message.GetUnknown()), field...))
+}
+
+// GetEventSequence returns the sequence number of the event. False is returned when the event has no sequence.
+func GetEventSequence(event *subscription.Event) (uint64, bool) {
+	unknown := event.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		number, wireType, length := protowire.ConsumeTag(unknown)
+		if length < 0 {
+			return 0, false
+		}
+		unknown = unknown[length:]
+		if number == EventSequenceFieldNumber && wireType == protowire.VarintType {
+			sequence, length := protowire.ConsumeVarint(unknown)
+			return sequence, length >= 0
+		}
+		length = protowire.ConsumeFieldValue(number, wireType, unknown)
+		if length < 0 {
+			return 0, false
+		}
+		unknown = unknown[length:]
+	}
+	return 0, false
+}
+
+// withoutEventSequence removes a previously written sequence number from the unknown fields
+func withoutEventSequence(unknown []byte) []byte {
+	remaining := make([]byte, 0, len(unknown))
+	for len(unknown) > 0 {
+		number, wireType, tagLength := protowire.ConsumeTag(unknown)
+		if tagLength < 0 {
+			return remaining
+		}
+		valueLength := protowire.ConsumeFieldValue(number, wireType, unknown[tagLength:])
+		if valueLength < 0 {
+			return remaining
+		}
+		if number != EventSequenceFieldNumber {
+			remaining = append(remaining, unknown[:tagLength+valueLength]...)
+		}
+		unknown = unknown[tagLength+valueLength:]
+	}
+	return remaining
+}
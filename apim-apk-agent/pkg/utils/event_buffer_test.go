@@ -0,0 +1,168 @@
+/*
+ *  Copyright (c) 2024, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apkmgt "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/service/apkmgt"
+	subscription "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEventBufferSince(t *testing.T) {
+	buffer := newEventBuffer(3)
+	events := make([]*subscription.Event, 0)
+	for i := 1; i <= 5; i++ {
+		event := &subscription.Event{Uuid: string(rune('a' + i))}
+		events = append(events, event)
+		buffer.add(uint64(i), event)
+	}
+
+	missed, found := buffer.since(5, 5)
+	assert.True(t, found)
+	assert.Empty(t, missed)
+
+	missed, found = buffer.since(3, 5)
+	assert.True(t, found)
+	assert.Equal(t, events[3:], missed)
+
+	missed, found = buffer.since(2, 5)
+	assert.True(t, found)
+	assert.Equal(t, events[2:], missed)
+
+	_, found = buffer.since(1, 5)
+	assert.False(t, found, "Overwritten events should not be found")
+
+	_, found = buffer.since(6, 5)
+	assert.False(t, found, "A sequence from the future should not be found")
+}
+
+func TestEventSequence(t *testing.T) {
+	event := &subscription.Event{Uuid: "event"}
+	_, found := GetEventSequence(event)
+	assert.False(t, found)
+
+	setEventSequence(event, 7)
+	setEventSequence(event, 300)
+	sequence, found := GetEventSequence(event)
+	assert.True(t, found)
+	assert.Equal(t, uint64(300), sequence)
+
+	// The sequence survives the wire format
+	payload, err := proto.Marshal(event)
+	assert.Nil(t, err)
+	received := &subscription.Event{}
+	assert.Nil(t, proto.Unmarshal(payload, received))
+	sequence, found = GetEventSequence(received)
+	assert.True(t, found)
+	assert.Equal(t, uint64(300), sequence)
+	assert.Equal(t, "event", received.Uuid)
+}
+
+func TestSendEventSequence(t *testing.T) {
+	removeAllClientConnections()
+	SetEventBufferSize(2)
+	defer SetEventBufferSize(DefaultEventBufferSize)
+
+	first := &subscription.Event{Uuid: "first"}
+	second := &subscription.Event{Uuid: "second"}
+	SendEvent(first)
+	SendEvent(second)
+	firstSequence, _ := GetEventSequence(first)
+	secondSequence, _ := GetEventSequence(second)
+	assert.Equal(t, firstSequence+1, secondSequence)
+
+	// A client that missed the second event receives only it
+	resumed := new(MockEventStreamServer)
+	resumed.On("Send", second).Return(nil).Once()
+	_, found := ResumeClientConnection("resumed", resumed, GetEventStreamID(), firstSequence)
+	assert.True(t, found)
+	waitForSentEvents(t, resumed)
+	assert.Contains(t, GetClientConnectionIDs(), "resumed")
+	DeleteClientConnection("resumed")
+
+	// Events of another agent process and events no longer buffered cannot be resumed
+	other := new(MockEventStreamServer)
+	_, found = ResumeClientConnection("other", other, "another-stream", firstSequence)
+	assert.False(t, found)
+	SendEvent(&subscription.Event{Uuid: "third"})
+	SendEvent(&subscription.Event{Uuid: "fourth"})
+	_, found = ResumeClientConnection("other", other, GetEventStreamID(), firstSequence)
+	assert.False(t, found)
+	other.AssertNotCalled(t, "Send", mock.Anything)
+	assert.NotContains(t, GetClientConnectionIDs(), "other")
+
+	removeAllClientConnections()
+}
+
+// receivingEventStream is a client stream which passes the events it receives to a channel
+type receivingEventStream struct {
+	apkmgt.EventStreamService_StreamEventsServer
+	events chan *subscription.Event
+}
+
+func (stream *receivingEventStream) Send(event *subscription.Event) error {
+	stream.events <- event
+	return nil
+}
+
+// blockingEventStream is a client stream which does not receive the events until it is released
+type blockingEventStream struct {
+	apkmgt.EventStreamService_StreamEventsServer
+	release chan struct{}
+}
+
+func (stream *blockingEventStream) Send(*subscription.Event) error {
+	<-stream.release
+	return nil
+}
+
+func TestSlowClientDoesNotBlockOtherClients(t *testing.T) {
+	removeAllClientConnections()
+	SetEventBufferSize(2)
+	defer SetEventBufferSize(DefaultEventBufferSize)
+
+	slow := &blockingEventStream{release: make(chan struct{})}
+	defer close(slow.release)
+	slowStopped := AddClientConnection("slow", slow)
+	fast := &receivingEventStream{events: make(chan *subscription.Event, 1)}
+	AddClientConnection("fast", fast)
+
+	// The slow client is disconnected once it falls behind the buffered events while the other client receives them
+	for i := 0; i < 4; i++ {
+		SendEvent(&subscription.Event{Uuid: fmt.Sprintf("event-%d", i)})
+		select {
+		case event := <-fast.events:
+			assert.Equal(t, fmt.Sprintf("event-%d", i), event.Uuid)
+		case <-time.After(time.Second):
+			t.Fatalf("Event %d is not received by the fast client", i)
+		}
+	}
+	select {
+	case <-slowStopped:
+	default:
+		t.Error("Slow client is not disconnected")
+	}
+	assert.Equal(t, []string{"fast", "slow"}, GetClientConnectionIDs())
+	removeAllClientConnections()
+}
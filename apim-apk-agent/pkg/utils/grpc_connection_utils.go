@@ -4,6 +4,8 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,21 +15,175 @@ import (
 	"github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
 )
 
-var clientConnections = make(map[string]apkmgt.EventStreamService_StreamEventsServer)
+// clientConnection queues the events of a client which are sent to its stream in order by a separate goroutine so
+// that a slow client does not hold back the others
+type clientConnection struct {
+	stream apkmgt.EventStreamService_StreamEventsServer
+	events chan *subscription.Event
+	// stop is closed when the events are no longer sent to the client
+	stop    chan struct{}
+	stopped bool
+}
+
+var clientConnections = make(map[string]*clientConnection)
+
+var (
+	// eventLock serializes the sequence numbers and the queueing of the events so that the events are received in
+	// order. The events are sent to the clients outside of it.
+	eventLock     sync.Mutex
+	eventSequence uint64
+	recentEvents  = newEventBuffer(DefaultEventBufferSize)
+	// eventStreamID identifies the sequence numbers of this agent process as they start over after a restart
+	eventStreamID = uuid.New().String()
+)
+
+// newClientConnection creates the connection of a client and starts sending its events. The events queued more than
+// the buffered events disconnect the client, which can resume from the buffered events after it reconnects. The
+// caller must hold the eventLock.
+func newClientConnection(clientID string, stream apkmgt.EventStreamService_StreamEventsServer) *clientConnection {
+	connection := &clientConnection{
+		stream: stream,
+		events: make(chan *subscription.Event, len(recentEvents.events)),
+		stop:   make(chan struct{}),
+	}
+	go connection.sendEvents(clientID)
+	return connection
+}
+
+// sendEvents sends the queued events to the client until it is stopped or an event cannot be sent
+func (connection *clientConnection) sendEvents(clientID string) {
+	for {
+		select {
+		case <-connection.stop:
+			return
+		case event := <-connection.events:
+			if err := connection.stream.Send(event); err != nil {
+				loggers.LoggerAPKOperator.Errorf("Error sending event to client %s: %v", clientID, err)
+				eventLock.Lock()
+				connection.stopSending()
+				eventLock.Unlock()
+				return
+			}
+			loggers.LoggerAPKOperator.Debugf("Event sent to client %s", clientID)
+		}
+	}
+}
 
-// AddClientConnection adds a client connection to the map
-func AddClientConnection(clientID string, stream apkmgt.EventStreamService_StreamEventsServer) {
-	clientConnections[clientID] = stream
+// queueEvent queues the event to be sent to the client. The client is stopped when its queue is full. The caller
+// must hold the eventLock.
+func (connection *clientConnection) queueEvent(clientID string, event *subscription.Event) {
+	if connection.stopped {
+		return
+	}
+	select {
+	case connection.events <- event:
+	default:
+		loggers.LoggerAPKOperator.Errorf("Disconnecting client %s as it does not keep up with the events", clientID)
+		connection.stopSending()
+	}
+}
+
+// stopSending stops sending the events to the client. The caller must hold the eventLock.
+func (connection *clientConnection) stopSending() {
+	if !connection.stopped {
+		connection.stopped = true
+		close(connection.stop)
+	}
+}
+
+// AddClientConnection adds a client connection to the map. The returned channel is closed when the events can no
+// longer be sent to the client, in which case the client should be disconnected.
+func AddClientConnection(clientID string, stream apkmgt.EventStreamService_StreamEventsServer) <-chan struct{} {
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	if existing, found := clientConnections[clientID]; found {
+		existing.stopSending()
+	}
+	connection := newClientConnection(clientID, stream)
+	clientConnections[clientID] = connection
+	return connection.stop
 }
 
 // DeleteClientConnection deletes a client connection from the map
 func DeleteClientConnection(clientID string) {
-	delete(clientConnections, clientID)
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	if connection, found := clientConnections[clientID]; found {
+		connection.stopSending()
+		delete(clientConnections, clientID)
+	}
 }
 
 // GetAllClientConnections returns all client connections
 func GetAllClientConnections() map[string]apkmgt.EventStreamService_StreamEventsServer {
-	return clientConnections
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	streams := make(map[string]apkmgt.EventStreamService_StreamEventsServer, len(clientConnections))
+	for clientID, connection := range clientConnections {
+		streams[clientID] = connection.stream
+	}
+	return streams
+}
+
+// GetClientConnectionIDs returns the IDs of the connected clients sorted
+func GetClientConnectionIDs() []string {
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	clientIDs := make([]string, 0, len(clientConnections))
+	for clientID := range clientConnections {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+	return clientIDs
+}
+
+// GetLatestEventSequence returns the sequence number of the latest event sent to the clients
+func GetLatestEventSequence() uint64 {
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	return eventSequence
+}
+
+// SetEventBufferSize sets the number of the recent events kept to be replayed to the reconnecting clients. The
+// events buffered so far are dropped.
+func SetEventBufferSize(size int) {
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	recentEvents = newEventBuffer(size)
+}
+
+// GetEventStreamID returns the ID of the event stream of this agent process. The sequence numbers of the events
+// are only comparable within the same stream.
+func GetEventStreamID() string {
+	return eventStreamID
+}
+
+// ResumeClientConnection queues the events after lastSequence to a reconnecting client and adds its connection. The
+// returned channel is closed when the events can no longer be sent to the client. False is returned without adding
+// the connection when the events of the given stream after lastSequence are no longer buffered, in which case the
+// client should be sent the full state.
+func ResumeClientConnection(clientID string, stream apkmgt.EventStreamService_StreamEventsServer, streamID string,
+	lastSequence uint64) (<-chan struct{}, bool) {
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	if streamID != eventStreamID {
+		return nil, false
+	}
+	missedEvents, found := recentEvents.since(lastSequence, eventSequence)
+	if !found {
+		return nil, false
+	}
+	loggers.LoggerAPKOperator.Infof("Resuming the events of client %s from sequence %d with %d missed events",
+		clientID, lastSequence, len(missedEvents))
+	if existing, found := clientConnections[clientID]; found {
+		existing.stopSending()
+	}
+	connection := newClientConnection(clientID, stream)
+	for _, event := range missedEvents {
+		connection.queueEvent(clientID, event)
+	}
+	clientConnections[clientID] = connection
+	return connection.stop, true
 }
 
 // SendInitialEventToAllConnectedClients sends initial event to all connected clients
@@ -41,13 +197,14 @@ func SendInitialEventToAllConnectedClients() {
 		TimeStamp: milliseconds,
 	}
 	loggers.LoggerAPKOperator.Debugf("Sending initial event to all clients: %v", &event)
-	for _, stream := range GetAllClientConnections() {
-		stream.Send(&event)
-	}
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	publishEvent(&event)
 }
 
-// SendInitialEvent sends initial event to the enforcer
-func SendInitialEvent(srv apkmgt.EventStreamService_StreamEventsServer) {
+// SendInitialEvent sends initial event to the enforcer. The event carries the latest sequence number so that the
+// client can resume from it after reconnecting.
+func SendInitialEvent(clientID string) {
 	currentTime := time.Now()
 	milliseconds := currentTime.UnixNano() / int64(time.Millisecond)
 
@@ -57,19 +214,45 @@ func SendInitialEvent(srv apkmgt.EventStreamService_StreamEventsServer) {
 		TimeStamp: milliseconds,
 	}
 	loggers.LoggerAPKOperator.Debugf("Sending initial event to client: %v", &event)
-	srv.Send(&event)
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	setEventSequence(&event, eventSequence)
+	if connection, found := clientConnections[clientID]; found {
+		connection.queueEvent(clientID, &event)
+	}
+}
+
+// SendEventToClient sends an event to a single client without a sequence number. It waits while the queue of the
+// client is full.
+func SendEventToClient(clientID string, event *subscription.Event) {
+	eventLock.Lock()
+	connection, found := clientConnections[clientID]
+	eventLock.Unlock()
+	if !found {
+		return
+	}
+	select {
+	case connection.events <- event:
+	case <-connection.stop:
+	}
 }
 
 // SendEvent sends event to the common-controllers
 func SendEvent(event *subscription.Event) {
 	loggers.LoggerAPKOperator.Infof("Sending event to all clients: %v", event)
-	for clientID, stream := range GetAllClientConnections() {
-		err := stream.Send(event)
-		if err != nil {
-			loggers.LoggerAPKOperator.Errorf("Error sending event to client %s: %v", clientID, err)
-		} else {
-			loggers.LoggerAPKOperator.Debugf("Event sent to client %s", clientID)
-		}
+	eventLock.Lock()
+	defer eventLock.Unlock()
+	publishEvent(event)
+}
+
+// publishEvent assigns the next sequence number to the event, buffers it and queues it to all the clients. The
+// caller must hold the eventLock.
+func publishEvent(event *subscription.Event) {
+	eventSequence++
+	setEventSequence(event, eventSequence)
+	recentEvents.add(eventSequence, event)
+	for clientID, connection := range clientConnections {
+		connection.queueEvent(clientID, event)
 	}
 }
 
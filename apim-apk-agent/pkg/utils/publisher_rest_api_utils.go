@@ -18,24 +18,28 @@ package utils
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
-	"net/url"
 	"strings"
 
 	"github.com/wso2/product-apim-tooling/apim-apk-agent/config"
+	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/controlplane"
 	logger "github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/loggers"
-	"github.com/wso2/product-apim-tooling/apim-apk-agent/pkg/tlsutils"
 )
 
 // Scope - token scope
 type Scope string
 
+// Resource names of the publisher REST API requests sent through the control plane client
+const (
+	apiImportResource         = "apiimport"
+	apiRevisionDeleteResource = "apirevisiondelete"
+)
+
 const (
 	// APIImportRelativePath is the relative path of API import in publisher rest API
 	APIImportRelativePath = "api/am/publisher/v4/apis/import?preserveProvider=false&overwrite=true&rotateRevision=true&preservePortalConfigurations=true"
@@ -104,15 +108,8 @@ components:
 )
 
 var (
-	tokenURL             string
-	apiImportURL         string
-	apiDeleteURL         string
-	username             string
-	password             string
-	skipSSL              bool
-	clientID             string
-	clientSecret         string
-	basicAuthHeaderValue string
+	apiImportURL string
+	apiDeleteURL string
 )
 
 func init() {
@@ -122,104 +119,26 @@ func init() {
 		// This has to be error. For debugging purpose info
 		logger.LoggerUtils.Errorf("Error reading configs: %v", errReadConfig)
 	}
+	UpdateControlPlaneParameters(conf)
+}
+
+// UpdateControlPlaneParameters derives the publisher REST API URLs from the control plane configurations
+func UpdateControlPlaneParameters(conf *config.Config) {
 	// Populate data from the config
 	cpConfigs := conf.ControlPlane
 	cpURL := cpConfigs.ServiceURL
 	// If the eventHub URL is configured with trailing slash
 	if strings.HasSuffix(cpURL, "/") {
 		apiImportURL = cpURL + APIImportRelativePath
-		tokenURL = cpURL + TokenRelativePath
 		apiDeleteURL = cpURL + APIDeleteRelativePath
 	} else {
 		apiImportURL = cpURL + "/" + APIImportRelativePath
-		tokenURL = cpURL + "/" + TokenRelativePath
 		apiDeleteURL = cpURL + "/" + APIDeleteRelativePath
 	}
-	username = cpConfigs.Username
-	password = cpConfigs.Password
-	clientID = cpConfigs.ClientID
-	clientSecret = cpConfigs.ClientSecret
-	skipSSL = cpConfigs.SkipSSLVerification
-
-	// If clientId and clientSecret is not provided use username and password as basic auth to access rest apis.
-	basicAuthHeaderValue = GetBasicAuthHeaderValue(username, password)
-}
-
-// Base64EncodeCredentials encodes the given username and password into a base64 string.
-func Base64EncodeCredentials(username, password string) string {
-	credentials := username + ":" + password
-	return base64.StdEncoding.EncodeToString([]byte(credentials))
-}
-
-// GetBasicAuthHeaderValue constructs and returns the Basic authentication header value using the provided username and password.
-func GetBasicAuthHeaderValue(username, password string) string {
-	return fmt.Sprintf("Basic %s", Base64EncodeCredentials(username, password))
-}
-
-// GetToken retrieves an OAuth token using the provided credentials and scopes.
-func GetToken(scopes []string, clientID string, clientSecret string) (string, error) {
-	form := url.Values{}
-	form.Set("grant_type", "password")
-	form.Set("username", username)
-	form.Set("password", password)
-	form.Set("scope", strings.Join(scopes, " "))
-	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(form.Encode()))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Authorization", GetBasicAuthHeaderValue(clientID, clientSecret))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	// Check for non-200 response status
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected response status: %s", resp.Status)
-	}
-
-	// Parse JSON response
-	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", err
-	}
-
-	// Extract access_token
-	accessToken, ok := response["access_token"].(string)
-	if !ok {
-		return "", fmt.Errorf("access_token not found in response")
-	}
-
-	return accessToken, nil
-}
-
-// GetSuitableAuthHeadervalue returns an appropriate authentication header value based on whether client credentials are provided.
-func GetSuitableAuthHeadervalue(scopes []string) (string, error) {
-	if clientID != "" && clientSecret != "" {
-		token, err := GetToken(scopes, clientID, clientSecret)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("Bearer %s", token), nil
-	}
-	return basicAuthHeaderValue, nil
 }
 
 // ImportAPI imports an API from a zip file, returning the ID of the imported API.
 func ImportAPI(apiZipName string, zipFileBytes *bytes.Buffer) (string, string, error) {
-	authHeaderVal, err := GetSuitableAuthHeadervalue([]string{string(AdminScope), string(ImportExportScope)})
-	if err != nil {
-		return "", "", err
-	}
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	part, err := writer.CreateFormFile("file", apiZipName)
@@ -235,10 +154,9 @@ func ImportAPI(apiZipName string, zipFileBytes *bytes.Buffer) (string, string, e
 		return "", "", err
 	}
 
-	req.Header.Set("Authorization", authHeaderVal)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("Accept", "application/json")
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
+	resp, err := controlplane.GetClient().Do(apiImportResource, req)
 	if err != nil {
 		return "", "", err
 	}
@@ -267,18 +185,13 @@ func ImportAPI(apiZipName string, zipFileBytes *bytes.Buffer) (string, string, e
 // DeleteAPIRevision deletes an API given its UUID.
 func DeleteAPIRevision(apiUUID string, revisionID string, body string) error {
 	deleteURL := apiDeleteURL + apiUUID + "/undeploy-revision?revisionId=" + revisionID
-	authheaderval, err := GetSuitableAuthHeadervalue([]string{string(AdminScope), string(ImportExportScope)})
-	if err != nil {
-		return err
-	}
 	req, err := http.NewRequest("POST", deleteURL, bytes.NewBuffer([]byte(body)))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", authheaderval)
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := tlsutils.InvokeControlPlane(req, skipSSL)
+	resp, err := controlplane.GetClient().Do(apiRevisionDeleteResource, req)
 	if err != nil {
 		logger.LoggerTLSUtils.Errorf("Error occured while sending undeploy revision request. Error: %+v", err)
 		return err
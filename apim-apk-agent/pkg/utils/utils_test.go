@@ -20,12 +20,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
 
-	apkmgt "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/service/apkmgt"
 	subscription "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
 )
 
@@ -50,6 +50,33 @@ func (m *MockEventStreamServer) Context() context.Context {
 	return context.Background()
 }
 
+// ignoredT discards the failures of the assertions checked repeatedly until they pass
+type ignoredT struct{}
+
+func (ignoredT) Logf(string, ...interface{})   {}
+func (ignoredT) Errorf(string, ...interface{}) {}
+func (ignoredT) FailNow()                      {}
+
+// waitForSentEvents waits until the expected events are sent to the mock streams as the events are sent by the
+// goroutines of the clients
+func waitForSentEvents(t *testing.T, streams ...*MockEventStreamServer) {
+	assert.Eventually(t, func() bool {
+		for _, stream := range streams {
+			if !stream.AssertExpectations(ignoredT{}) {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+}
+
+// removeAllClientConnections deletes the connections of all the clients
+func removeAllClientConnections() {
+	for _, clientID := range GetClientConnectionIDs() {
+		DeleteClientConnection(clientID)
+	}
+}
+
 // TestAddDeleteAndGetAllClientConnections tests AddClientConnection, DeleteClientConnection,
 // and GetAllClientConnections functions
 func TestAddDeleteAndGetAllClientConnections(t *testing.T) {
@@ -85,13 +112,14 @@ func TestSendInitialEventToAllConnectedClients(t *testing.T) {
 
 	// Test positive case: event sent to all clients
 	SendInitialEventToAllConnectedClients()
+	waitForSentEvents(t, mockConnection1, mockConnection2)
 
 	// Assert that the expectations were met
 	mockConnection1.AssertExpectations(t)
 	mockConnection2.AssertExpectations(t)
 
 	// Test negative case: no clients connected
-	clientConnections = make(map[string]apkmgt.EventStreamService_StreamEventsServer)
+	removeAllClientConnections()
 	SendInitialEventToAllConnectedClients()
 
 	// Assert that no event is sent when there are no connections
@@ -104,8 +132,8 @@ func TestSendInitialEvent(t *testing.T) {
 	mockConnection.On("Send", mock.Anything).Return(nil).Once()
 	AddClientConnection("client1", mockConnection)
 	SendInitialEventToAllConnectedClients()
-	mockConnection.AssertExpectations(t)
-	clientConnections = make(map[string]apkmgt.EventStreamService_StreamEventsServer)
+	waitForSentEvents(t, mockConnection)
+	removeAllClientConnections()
 	SendInitialEventToAllConnectedClients()
 	mockConnection.AssertNotCalled(t, "Send")
 }
@@ -128,8 +156,8 @@ func TestSendEvent(t *testing.T) {
 	SendEvent(event)
 
 	// Assert that the expectations were met
-	mockConnection1.AssertExpectations(t)
-	mockConnection2.AssertExpectations(t)
+	waitForSentEvents(t, mockConnection1, mockConnection2)
+	removeAllClientConnections()
 }
 
 func TestGetUniqueIDOfApplicationMapping(t *testing.T) {